@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"testing"
 
 	"github.com/spf13/viper"
 	"golang.org/x/text/language"
@@ -14,7 +15,9 @@ import (
 )
 
 var (
-	Language = []string{language.Chinese.String(), language.TraditionalChinese.String(), language.English.String(), language.Korean.String(), language.Japanese.String(), language.German.String(), language.French.String(), language.Indonesian.String()}
+	// DefaultLanguage 默认语言，插件信息缺少当前语言的翻译时使用
+	DefaultLanguage = language.Chinese.String()
+	Language        = []string{language.Chinese.String(), language.TraditionalChinese.String(), language.English.String(), language.Korean.String(), language.Japanese.String(), language.German.String(), language.French.String(), language.Indonesian.String()}
 )
 
 var EnvConfig = envConfigSchema{}
@@ -32,6 +35,22 @@ func (s *envConfigSchema) GetNginxContainerName() string {
 	return fmt.Sprintf("dootask-nginx-%s", s.APP_ID)
 }
 
+// GetStoreURL Nginx访问商店的地址，未配置时使用商店容器在DooTask网络中的固定地址
+func (s *envConfigSchema) GetStoreURL() string {
+	if s.STORE_URL != "" {
+		return strings.TrimSuffix(s.STORE_URL, "/")
+	}
+	return fmt.Sprintf("http://%s.18:8080", s.APP_IPPR)
+}
+
+// GetPluginDomain 插件默认的独立域名，未配置基础域名时返回空
+func (s *envConfigSchema) GetPluginDomain(key string) string {
+	if s.PLUGIN_BASE_DOMAIN == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s", key, strings.TrimPrefix(s.PLUGIN_BASE_DOMAIN, "."))
+}
+
 func (s *envConfigSchema) GetDefaultContainerName(key string) string {
 	return fmt.Sprintf("dootask-plugin-%s-%s", key, s.APP_ID)
 }
@@ -51,6 +70,7 @@ type AppConfig struct {
 	PLUGIN_PREFIX       string
 	DB_PREFIX           string
 	PLUGIN_CIDR         string
+	PLUGIN_CIDR6        string
 	NETWORK_NAME        string
 	SHARED_COMPOSE      bool
 	SHARED_COMPOSE_NAME string
@@ -106,6 +126,7 @@ type envConfigSchema struct {
 	// 应用基本配置
 	ENV                 string
 	APP_KEY             string
+	MASTER_KEY          string
 	APP_ID              string
 	APP_IPPR            string
 	STORAGE             string
@@ -114,6 +135,7 @@ type envConfigSchema struct {
 	PLUGIN_PREFIX       string
 	DB_PREFIX           string
 	PLUGIN_CIDR         string
+	PLUGIN_CIDR6        string // 插件的IPv6网段，为空时不分配IPv6地址
 	SHARED_COMPOSE      bool
 	SHARED_COMPOSE_NAME string
 
@@ -133,6 +155,17 @@ type envConfigSchema struct {
 	DOOTASK_DIR string
 	DOOTASK_URL string
 
+	// Nginx访问商店时使用的地址，用于插件的登录校验
+	STORE_URL string
+	// 插件独立域名的基础域名，插件默认使用 <key>.<基础域名>
+	PLUGIN_BASE_DOMAIN string
+	// 申请证书使用的ACME服务目录地址，默认为 Let's Encrypt
+	ACME_DIRECTORY string
+	// 注册ACME账户使用的邮箱
+	ACME_EMAIL string
+	// 额外信任的CA证书文件，用于访问使用自签名证书的ACME服务，例如测试用的 pebble
+	ACME_CA_FILE string
+
 	// 第三方服务配置
 	YoudaoAppKey    string
 	YoudaoAppSecret string
@@ -149,6 +182,7 @@ func (s *envConfigSchema) App() AppConfig {
 		PLUGIN_PREFIX:  s.PLUGIN_PREFIX,
 		DB_PREFIX:      s.DB_PREFIX,
 		PLUGIN_CIDR:    fmt.Sprintf("%s.30/24", s.APP_IPPR),
+		PLUGIN_CIDR6:   s.PLUGIN_CIDR6,
 		NETWORK_NAME:   fmt.Sprintf("dootask-networks-%s", s.APP_ID),
 		SHARED_COMPOSE: s.SHARED_COMPOSE,
 		SHARED_COMPOSE_NAME: func() string {
@@ -219,6 +253,14 @@ func (s *envConfigSchema) ThirdParty() ThirdPartyConfig {
 	}
 }
 
+// 获取用于加密插件敏感信息的密钥，未配置 MASTER_KEY 时使用 APP_KEY
+func (s *envConfigSchema) SecretKey() string {
+	if s.MASTER_KEY != "" {
+		return s.MASTER_KEY
+	}
+	return s.APP_KEY
+}
+
 func (s *envConfigSchema) IsDev() bool {
 	return s.ENV == "dev" || s.ENV == "TESTING"
 }
@@ -253,7 +295,10 @@ func envInit() {
 
 	// 将配置映射到结构体
 	mapConfigToStruct(v)
-	// 检查必填项
+	// 检查必填项，单元测试中不检查，依赖配置的包同样可以测试
+	if testing.Testing() {
+		return
+	}
 	requiredFields := []string{"APP_ID", "REDIS_HOST", "REDIS_PORT"}
 	for _, field := range requiredFields {
 		if v.GetString(field) == "" {
@@ -280,11 +325,13 @@ func setDefaults(v *viper.Viper) {
 	// 应用基本配置默认值
 	v.SetDefault("ENV", "dev")
 	v.SetDefault("APP_ID", "")
+	v.SetDefault("MASTER_KEY", "")
 	v.SetDefault("STORAGE", "sqlite")
 	v.SetDefault("SQLITE_PATH", "./app.db")
 	v.SetDefault("DATA_DIR", "")
 	v.SetDefault("PLUGIN_PREFIX", "plugin-dootask")
 	v.SetDefault("PLUGIN_CIDR", "")
+	v.SetDefault("PLUGIN_CIDR6", "")
 	v.SetDefault("SHARED_COMPOSE", true)
 	v.SetDefault("SHARED_COMPOSE_NAME", "")
 
@@ -299,6 +346,11 @@ func setDefaults(v *viper.Viper) {
 	// DooTask配置默认值
 	v.SetDefault("DOOTASK_DIR", "")
 	v.SetDefault("DOOTASK_URL", "http://127.0.0.1:2222")
+	v.SetDefault("STORE_URL", "")
+	v.SetDefault("PLUGIN_BASE_DOMAIN", "")
+	v.SetDefault("ACME_DIRECTORY", "https://acme-v02.api.letsencrypt.org/directory")
+	v.SetDefault("ACME_EMAIL", "")
+	v.SetDefault("ACME_CA_FILE", "")
 
 	// 第三方服务配置默认值
 	v.SetDefault("YoudaoAppKey", "")
@@ -312,12 +364,14 @@ func mapConfigToStruct(v *viper.Viper) {
 	EnvConfig.APP_ID = v.GetString("APP_ID")
 	EnvConfig.APP_IPPR = v.GetString("APP_IPPR")
 	EnvConfig.APP_KEY = v.GetString("APP_KEY")
+	EnvConfig.MASTER_KEY = v.GetString("MASTER_KEY")
 	EnvConfig.STORAGE = v.GetString("STORAGE")
 	EnvConfig.SQLITE_PATH = v.GetString("SQLITE_PATH")
 	EnvConfig.DATA_DIR = v.GetString("DATA_DIR")
 	EnvConfig.PLUGIN_PREFIX = v.GetString("PLUGIN_PREFIX")
 	EnvConfig.DB_PREFIX = v.GetString("DB_PREFIX")
 	EnvConfig.PLUGIN_CIDR = v.GetString("PLUGIN_CIDR")
+	EnvConfig.PLUGIN_CIDR6 = v.GetString("PLUGIN_CIDR6")
 	EnvConfig.SHARED_COMPOSE = v.GetBool("SHARED_COMPOSE")
 	EnvConfig.SHARED_COMPOSE_NAME = v.GetString("SHARED_COMPOSE_NAME")
 
@@ -336,6 +390,11 @@ func mapConfigToStruct(v *viper.Viper) {
 	// DooTask配置
 	EnvConfig.DOOTASK_DIR = v.GetString("DOOTASK_DIR")
 	EnvConfig.DOOTASK_URL = v.GetString("DOOTASK_URL")
+	EnvConfig.STORE_URL = v.GetString("STORE_URL")
+	EnvConfig.PLUGIN_BASE_DOMAIN = v.GetString("PLUGIN_BASE_DOMAIN")
+	EnvConfig.ACME_DIRECTORY = v.GetString("ACME_DIRECTORY")
+	EnvConfig.ACME_EMAIL = v.GetString("ACME_EMAIL")
+	EnvConfig.ACME_CA_FILE = v.GetString("ACME_CA_FILE")
 
 	// 第三方服务配置
 	EnvConfig.YoudaoAppKey = v.GetString("YoudaoAppKey")
@@ -6,4 +6,9 @@ var (
 	DataDir       string
 	AppInstallDir string
 	NginxDir      string
+	BackupDir     string
+	ACMEDir       string
 )
+
+// BackupLockFile 插件备份目录中的锁文件，备份、导出与恢复期间持有
+const BackupLockFile = ".lock"
@@ -5,3 +5,28 @@ const (
 	MemoryLimit   = "MEMORY_LIMIT"   // 内存限制
 	ContainerName = "CONTAINER_NAME" // 容器名称
 )
+
+// ReservedEnvKeys 由商店注入的环境变量，插件参数不允许覆盖
+var ReservedEnvKeys = []string{
+	"IP_ADDRESS",
+	"IP6_ADDRESS",
+	ContainerName,
+}
+
+// ReservedEnvPrefix 商店注入的 DooTask 相关环境变量前缀，插件参数不允许使用
+const ReservedEnvPrefix = "DOOTASK_"
+
+// SecretMask 敏感信息在接口中的掩码，更新参数时传入该值表示保持原值不变
+const SecretMask = "******"
+
+// StoreSecretEnvKeys 由商店注入的敏感环境变量，保存时需要加密
+var StoreSecretEnvKeys = []string{
+	"DOOTASK_APP_KEY",
+	"DOOTASK_DB_PASSWORD",
+}
+
+// 插件登录校验通过后，Nginx传递给插件的用户信息请求头
+const (
+	AuthHeaderUserID   = "X-Dootask-Userid"   // 用户ID
+	AuthHeaderIdentity = "X-Dootask-Identity" // 用户身份，多个身份以逗号分隔
+)
@@ -28,8 +28,54 @@ var (
 	ErrPluginVersionFailed           = "ErrPluginVersionFailed"           // 获取版本信息失败
 	ErrPluginDependencyFailed        = "ErrPluginDependencyFailed"        // 检查依赖版本失败
 	ErrPluginParamParseFailed        = "ErrPluginParamParseFailed"        // 解析插件参数失败
+	ErrPluginParamGenerateFailed     = "ErrPluginParamGenerateFailed"     // 生成插件参数失败
 	ErrPluginModifyParamFailed       = "ErrPluginModifyParamFailed"       // 修改参数失败
 	ErrPluginRestartFailed           = "ErrPluginRestartFailed"           // 插件重启失败
+	ErrPluginUnknownParam            = "ErrPluginUnknownParam"            // 未声明的插件参数 {{.detail}}
+	ErrPluginReservedParam           = "ErrPluginReservedParam"           // 参数 {{.detail}} 为系统保留变量，不允许设置
+
+	// form
+	ErrFormFieldRequired        = "ErrFormFieldRequired"        // {{.field}} 为必填项
+	ErrFormFieldInvalidNumber   = "ErrFormFieldInvalidNumber"   // {{.field}} 请输入有效的数字
+	ErrFormFieldInvalidOption   = "ErrFormFieldInvalidOption"   // {{.field}} 请选择有效的选项
+	ErrFormFieldInvalidSwitch   = "ErrFormFieldInvalidSwitch"   // {{.field}} 请选择开启或关闭
+	ErrFormFieldInvalidEmail    = "ErrFormFieldInvalidEmail"    // {{.field}} 请输入有效的邮箱地址
+	ErrFormFieldInvalidURL      = "ErrFormFieldInvalidURL"      // {{.field}} 请输入有效的链接
+	ErrFormFieldInvalidPort     = "ErrFormFieldInvalidPort"     // {{.field}} 请输入有效的端口（{{.min}}-{{.max}}）
+	ErrFormFieldInvalidFile     = "ErrFormFieldInvalidFile"     // {{.field}} 文件内容无效
+	ErrFormFieldInvalidKeyValue = "ErrFormFieldInvalidKeyValue" // {{.field}} 键值对格式不正确
+	ErrFormFieldInvalidJSON     = "ErrFormFieldInvalidJSON"     // {{.field}} 请输入有效的JSON
+	ErrFormFieldInvalidDuration = "ErrFormFieldInvalidDuration" // {{.field}} 请输入有效的时长，如 30s、5m、1h30m
+	ErrFormFieldPatternInvalid  = "ErrFormFieldPatternInvalid"  // {{.field}} 正则表达式验证失败
+	ErrFormFieldPatternMismatch = "ErrFormFieldPatternMismatch" // {{.field}} 输入格式不正确
+	ErrFormFieldMinLen          = "ErrFormFieldMinLen"          // {{.field}} 长度不能小于 {{.min}}
+	ErrFormFieldMaxLen          = "ErrFormFieldMaxLen"          // {{.field}} 长度不能大于 {{.max}}
+	ErrFormFieldMin             = "ErrFormFieldMin"             // {{.field}} 不能小于 {{.min}}
+	ErrFormFieldMax             = "ErrFormFieldMax"             // {{.field}} 不能大于 {{.max}}
+	ErrFormRuleEquals           = "ErrFormRuleEquals"           // {{.field}} 与 {{.target}} 不一致
+	ErrFormRuleAtLeastOne       = "ErrFormRuleAtLeastOne"       // {{.fields}} 至少填写一项
+
+	// backup
+	ErrBackupFailed           = "ErrBackupFailed"           // 插件备份失败
+	ErrBackupNotFound         = "ErrBackupNotFound"         // 备份文件不存在
+	ErrBackupManifestInvalid  = "ErrBackupManifestInvalid"  // 备份清单无效
+	ErrBackupVersionMismatch  = "ErrBackupVersionMismatch"  // 备份版本与当前插件版本不一致
+	ErrBackupKeyMismatch      = "ErrBackupKeyMismatch"      // 备份文件与插件不匹配
+	ErrBackupPluginInstalled  = "ErrBackupPluginInstalled"  // 插件已安装，请先卸载后再恢复
+	ErrBackupPluginInstalling = "ErrBackupPluginInstalling" // 插件正在安装或恢复中，请稍后再试
+	ErrRestoreFailed          = "ErrRestoreFailed"          // 插件恢复失败
+	ErrBackupRunning          = "ErrBackupRunning"          // 插件正在备份中
+	ErrBackupCronInvalid      = "ErrBackupCronInvalid"      // 备份计划的cron表达式无效
+
+	// secret
+	ErrSecretEncryptFailed = "ErrSecretEncryptFailed" // 敏感信息加密失败
+	ErrSecretDecryptFailed = "ErrSecretDecryptFailed" // 敏感信息解密失败，请检查加密密钥
+
+	// store
+	ErrStoreExportFailed    = "ErrStoreExportFailed"    // 整站导出失败
+	ErrStoreImportFailed    = "ErrStoreImportFailed"    // 整站导入失败
+	ErrStoreManifestInvalid = "ErrStoreManifestInvalid" // 整站导出清单无效
+	ErrStoreNotEmpty        = "ErrStoreNotEmpty"        // 当前主机已安装插件，无法导入
 
 	// docker
 	ErrDockerClientCreate     = "ErrDockerClientCreate"     // 创建Docker客户端失败
@@ -42,9 +88,35 @@ var (
 	ErrNginxContainerNotFound = "ErrNginxContainerNotFound" // 未找到Nginx容器
 
 	// nginx
-	ErrNginxWriteFile    = "ErrNginxWriteFile"    // 写入文件失败
-	ErrNginxParseContent = "ErrNginxParseContent" // 解析内容失败
-	ErrNginxGetContainer = "ErrNginxGetContainer" // 获取Nginx容器失败
+	ErrNginxWriteFile        = "ErrNginxWriteFile"        // 写入文件失败
+	ErrNginxParseContent     = "ErrNginxParseContent"     // 解析内容失败
+	ErrNginxGetContainer     = "ErrNginxGetContainer"     // 获取Nginx容器失败
+	ErrNginxReconcileFailed  = "ErrNginxReconcileFailed"  // 核对Nginx配置失败
+	ErrNginxTemplateRequired = "ErrNginxTemplateRequired" // 请提供Nginx模板或版本号
+	ErrNginxVersionNotFound  = "ErrNginxVersionNotFound"  // 未找到Nginx配置版本
+	ErrNginxConfigInvalid    = "ErrNginxConfigInvalid"    // Nginx配置检测未通过：{{.detail}}
+	ErrNginxApplyFailed      = "ErrNginxApplyFailed"      // 应用Nginx配置失败，已恢复原配置
+	ErrNginxDomainRequired   = "ErrNginxDomainRequired"   // 插件需要独立域名，请设置域名或配置 PLUGIN_BASE_DOMAIN
+	ErrNginxDomainInvalid    = "ErrNginxDomainInvalid"    // 无效的域名：{{.detail}}
+	ErrNginxDomainInUse      = "ErrNginxDomainInUse"      // 域名已被插件 {{.detail}} 使用
+	ErrNginxDomainReserved   = "ErrNginxDomainReserved"   // 域名 {{.detail}} 为DooTask的域名或基础域名，不能作为插件的独立域名
+	ErrNginxOptionsInvalid   = "ErrNginxOptionsInvalid"   // 无效的访问控制配置：{{.detail}}
+
+	// certificate
+	ErrCertificateInvalid       = "ErrCertificateInvalid"       // 无效的证书：{{.detail}}
+	ErrCertificateNotFound      = "ErrCertificateNotFound"      // 未找到证书
+	ErrCertificateInstallFailed = "ErrCertificateInstallFailed" // 写入证书到Nginx失败
+	ErrCertificateNotACME       = "ErrCertificateNotACME"       // 上传的证书不支持自动续期，请重新上传
+	ErrCertificatePending       = "ErrCertificatePending"       // 证书正在申请中
+	ErrCertificateDomainUnused  = "ErrCertificateDomainUnused"  // 域名 {{.detail}} 未被使用独立域名的插件使用，无法完成HTTP验证
+	ErrCertificateWildcardACME  = "ErrCertificateWildcardACME"  // 通配符证书无法通过HTTP验证申请，请上传证书
+
+	// ip
+	ErrIPAllocateFailed     = "ErrIPAllocateFailed"     // 分配IP地址失败
+	ErrIPAddressInUse       = "ErrIPAddressInUse"       // IP地址已被占用：{{.detail}}
+	ErrIPAddressInvalid     = "ErrIPAddressInvalid"     // 无效的IP地址：{{.detail}}
+	ErrIPRangeTooLarge      = "ErrIPRangeTooLarge"      // IP范围过大
+	ErrIPAllocationNotFound = "ErrIPAllocationNotFound" // 未找到IP分配记录
 
 	// log
 	ErrLogGetFailed  = "ErrLogGetFailed"  // 获取日志失败
@@ -84,6 +84,7 @@ func (*BaseApi) GetAppDetail(c *gin.Context) {
 // @Param key path string true "key"
 // @Param data body request.AppInstall true "RequestBody"
 // @Success 200 {object} dto.Response "success"
+// @Failure 400 {object} dto.Response{data=[]dto.FieldError} "参数验证失败时返回每个字段的错误"
 // @Router /apps/{key} [post]
 func (*BaseApi) InstallApp(c *gin.Context) {
 	err := checkAuth(c, true)
@@ -113,7 +114,7 @@ func (*BaseApi) InstallApp(c *gin.Context) {
 
 	err = appService.InstallApp(dto.NewServiceContext(c), req)
 	if err != nil {
-		helper.ErrorWith(c, err.Error(), nil)
+		helper.ErrorWith(c, err.Error(), err)
 		return
 	}
 	helper.SuccessWith(c, "安装成功")
@@ -255,6 +256,7 @@ func (*BaseApi) GetAppParams(c *gin.Context) {
 // @Param id path integer true "id"
 // @Param data body request.AppInstall true "RequestBody"
 // @Success 200 {object} dto.Response "success"
+// @Failure 400 {object} dto.Response{data=[]dto.FieldError} "参数验证失败时返回每个字段的错误"
 // @Router /apps/installed/{id}/params [put]
 func (*BaseApi) UpdateAppParams(c *gin.Context) {
 	err := checkAuth(c, true)
@@ -284,7 +286,7 @@ func (*BaseApi) UpdateAppParams(c *gin.Context) {
 
 	result, err := appService.UpdateAppParams(dto.NewServiceContext(c), req)
 	if err != nil {
-		helper.ErrorWith(c, err.Error(), nil)
+		helper.ErrorWith(c, err.Error(), err)
 		return
 	}
 	helper.SuccessWith(c, result)
@@ -350,6 +352,208 @@ func (*BaseApi) GetAppLogs(c *gin.Context) {
 	helper.SuccessWith(c, result)
 }
 
+// @Summary 获取插件备份列表
+// @Schemes
+// @Description
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Success 200 {object} dto.Response{data=[]response.AppBackupFile} "success"
+// @Router /apps/installed/{id}/backups [get]
+func (*BaseApi) ListAppBackups(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := appService.ListAppBackups(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 从备份恢复插件
+// @Schemes
+// @Description
+// @Security BearerAuth
+// @Tags app
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Param data body request.AppRestore true "RequestBody"
+// @Success 200 {object} dto.Response "success"
+// @Router /apps/installed/{id}/restore [post]
+func (*BaseApi) RestoreApp(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppRestore
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	err = appService.RestoreApp(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, "恢复中")
+}
+
+// @Summary 获取插件健康状态
+// @Schemes
+// @Description 容器配置了 HEALTHCHECK 时使用Docker的检查结果，否则使用插件声明的HTTP健康检查，log 为最近的检查记录
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Success 200 {object} dto.Response{data=response.AppHealth} "success"
+// @Router /apps/installed/{id}/health [get]
+func (*BaseApi) GetAppHealth(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := appService.GetAppHealth(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 获取插件自动重启策略
+// @Schemes
+// @Description
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Success 200 {object} dto.Response{data=model.AppRestartPolicy} "success"
+// @Router /apps/installed/{id}/restart-policy [get]
+func (*BaseApi) GetRestartPolicy(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := appService.GetRestartPolicy(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 修改插件自动重启策略
+// @Schemes
+// @Description 插件主容器异常退出后等待 backoff 秒重启，之后每次等待时间翻倍，最长 max_backoff 秒；window 分钟内异常退出超过 max_restarts 次时判定为崩溃循环，停止容器并标记为 Dead。compose 中设置了 restart: always 时同样生效。关闭时其他参数可以不传，并取消等待中的自动重启
+// @Security BearerAuth
+// @Tags app
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Param data body request.AppRestartPolicy true "RequestBody"
+// @Success 200 {object} dto.Response{data=model.AppRestartPolicy} "success"
+// @Router /apps/installed/{id}/restart-policy [put]
+func (*BaseApi) UpdateRestartPolicy(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppRestartPolicy
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := appService.UpdateRestartPolicy(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 获取插件定时备份计划
+// @Schemes
+// @Description
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Success 200 {object} dto.Response{data=model.AppBackupSchedule} "success"
+// @Router /apps/installed/{id}/backup-schedule [get]
+func (*BaseApi) GetBackupSchedule(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := appService.GetBackupSchedule(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 修改插件定时备份计划
+// @Schemes
+// @Description cron 为标准的5段表达式（分 时 日 月 周），retention 为保留的定时备份数量，0表示不限制，手动创建的备份不会被清理
+// @Security BearerAuth
+// @Tags app
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Param data body request.AppBackupSchedule true "RequestBody"
+// @Success 200 {object} dto.Response{data=model.AppBackupSchedule} "success"
+// @Router /apps/installed/{id}/backup-schedule [put]
+func (*BaseApi) UpdateBackupSchedule(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppBackupSchedule
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := appService.UpdateBackupSchedule(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
 // @Summary 上传插件
 // @Schemes
 // @Description
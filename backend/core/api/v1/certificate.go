@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"doo-store/backend/core/api/v1/helper"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 获取证书列表
+// @Schemes
+// @Description 获取插件独立域名使用的证书，按过期时间排序
+// @Security BearerAuth
+// @Tags certificate
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Success 200 {object} dto.Response{data=[]model.Certificate} "success"
+// @Router /certificates [get]
+func (*BaseApi) ListCertificates(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := certificateService.List(dto.NewServiceContext(c))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 上传证书
+// @Schemes
+// @Description 上传PEM格式的证书链与私钥，域名已有证书时替换原证书，使用该域名的插件会启用HTTPS
+// @Security BearerAuth
+// @Tags certificate
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param data body request.CertificateUpload true "RequestBody"
+// @Success 200 {object} dto.Response{data=model.Certificate} "success"
+// @Router /certificates [post]
+func (*BaseApi) UploadCertificate(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	var req request.CertificateUpload
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := certificateService.Upload(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 申请证书
+// @Schemes
+// @Description 通过ACME的HTTP-01验证为插件的独立域名申请证书，申请在后台进行，证书会在过期前自动续期
+// @Security BearerAuth
+// @Tags certificate
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param data body request.CertificateIssue true "RequestBody"
+// @Success 200 {object} dto.Response{data=model.Certificate} "success"
+// @Router /certificates/acme [post]
+func (*BaseApi) IssueCertificate(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	var req request.CertificateIssue
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := certificateService.Issue(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 续期证书
+// @Schemes
+// @Description 立即续期通过ACME申请的证书，续期在后台进行
+// @Security BearerAuth
+// @Tags certificate
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "id"
+// @Success 200 {object} dto.Response{data=model.Certificate} "success"
+// @Router /certificates/{id}/renew [post]
+func (*BaseApi) RenewCertificate(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := certificateService.Renew(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 删除证书
+// @Schemes
+// @Description 删除证书，使用该证书的插件会停用HTTPS
+// @Security BearerAuth
+// @Tags certificate
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "id"
+// @Success 200 {object} dto.Response "success"
+// @Router /certificates/{id} [delete]
+func (*BaseApi) DeleteCertificate(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	err = certificateService.Delete(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c)
+}
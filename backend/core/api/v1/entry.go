@@ -15,6 +15,10 @@ type DootaskInfo struct {
 }
 
 var (
-	appService     = service.NewIAppService()
-	dootaskService = service.NewIDootaskService()
+	appService           = service.NewIAppService()
+	dootaskService       = service.NewIDootaskService()
+	ipamService          = service.NewIIPAMService()
+	nginxService         = service.NewINginxService()
+	certificateService   = service.NewICertificateService()
+	statusHistoryService = service.NewIStatusHistoryService()
 )
@@ -3,6 +3,7 @@ package helper
 import (
 	"doo-store/backend/core/dto"
 	"doo-store/backend/i18n"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -96,12 +97,34 @@ func Error(c *gin.Context, values ...any) {
 	Response(c, http.StatusBadRequest, "error", values...)
 }
 
+// ErrorWith 失败，msgKey 为国际化ID
+// err 为表单验证错误时，data 返回每个字段翻译后的错误信息
 func ErrorWith(c *gin.Context, msgKey string, err error, values ...any) {
+	var vErrs dto.ValidationErrors
+	if errors.As(err, &vErrs) {
+		if len(values) == 0 {
+			values = []any{FieldErrors(c, vErrs)}
+		}
+		err = nil
+	}
 	msgDetail := i18n.GetMsgWithMap(c, msgKey, map[string]any{"detail": err})
 	// msgDetail := msgKey
 	Response(c, http.StatusBadRequest, msgDetail, values...)
 }
 
+// FieldErrors 翻译表单验证错误
+func FieldErrors(c *gin.Context, vErrs dto.ValidationErrors) []dto.FieldError {
+	fieldErrors := make([]dto.FieldError, 0, len(vErrs))
+	for _, vErr := range vErrs {
+		fieldErrors = append(fieldErrors, dto.FieldError{
+			Field:   vErr.EnvKey,
+			Label:   vErr.Field,
+			Message: i18n.GetMsgWithMap(c, vErr.MessageID, vErr.Data),
+		})
+	}
+	return fieldErrors
+}
+
 // ResponseWithRet 使用 ret/msg/data 格式的响应
 func ResponseWithRet(c *gin.Context, ret int, msg string, values ...any) {
 	var data any
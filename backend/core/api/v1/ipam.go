@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"doo-store/backend/core/api/v1/helper"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 获取IP使用情况
+// @Description state 可选 reserved、allocated、excluded、untracked，不指定时返回所有非空闲的IP；空闲的IP和起始IP之前的地址只统计数量
+// @Description state 可选 free、reserved、allocated、excluded、untracked，不指定时返回所有非空闲的IP
+// @Security BearerAuth
+// @Tags ipam
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param state query string false "state"
+// @Success 200 {object} dto.Response{data=response.IPAMOverview} "success"
+// @Router /ipam [get]
+func (*BaseApi) GetIPAMOverview(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	var req request.IPAMSearch
+	if err := helper.ValidateQueryParams(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := ipamService.GetOverview(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 预留IP范围
+// @Schemes
+// @Description 预留的IP不会分配给插件，end 为空时只预留 start
+// @Security BearerAuth
+// @Tags ipam
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param data body request.IPRange true "RequestBody"
+// @Success 200 {object} dto.Response{data=[]string} "success"
+// @Router /ipam/reserve [post]
+func (*BaseApi) ReserveIPRange(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	var req request.IPRange
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := ipamService.ReserveRange(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 排除IP范围
+// @Schemes
+// @Description 排除的IP不会分配给插件，也不能在 docker-compose 中使用，end 为空时只排除 start
+// @Security BearerAuth
+// @Tags ipam
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param data body request.IPRange true "RequestBody"
+// @Success 200 {object} dto.Response{data=[]string} "success"
+// @Router /ipam/exclude [post]
+func (*BaseApi) ExcludeIPRange(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	var req request.IPRange
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := ipamService.ExcludeRange(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 释放IP
+// @Schemes
+// @Description 释放预留、排除或已失效的分配记录，仍被已安装插件使用的IP不允许释放
+// @Security BearerAuth
+// @Tags ipam
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param ip path string true "ip"
+// @Success 200 {object} dto.Response "success"
+// @Router /ipam/{ip} [delete]
+func (*BaseApi) ReleaseIP(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	err = ipamService.ReleaseIP(dto.NewServiceContext(c), c.Param("ip"))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c)
+}
+
+// @Summary 核对IP分配
+// @Schemes
+// @Description 对比分配记录与容器实际使用的IP，返回发现的冲突
+// @Security BearerAuth
+// @Tags ipam
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Success 200 {object} dto.Response{data=response.IPReconcileReport} "success"
+// @Router /ipam/reconcile [get]
+func (*BaseApi) ReconcileIPs(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := ipamService.Reconcile(dto.NewServiceContext(c))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
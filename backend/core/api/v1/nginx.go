@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"doo-store/backend/core/api/v1/helper"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 核对Nginx配置
+// @Schemes
+// @Description 对比已安装插件的location配置与Nginx容器中的配置，重新写入缺失或内容不一致的配置并删除残留的配置
+// @Security BearerAuth
+// @Tags nginx
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Success 200 {object} dto.Response{data=nginx.ReconcileReport} "success"
+// @Router /nginx/reconcile [post]
+func (*BaseApi) ReconcileNginx(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	result, err := nginxService.Reconcile(dto.NewServiceContext(c))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 获取插件Nginx配置版本
+// @Schemes
+// @Description 按版本号倒序返回插件每次应用的Nginx配置，active 为当前使用的版本
+// @Security BearerAuth
+// @Tags nginx
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Success 200 {object} dto.Response{data=[]model.NginxLocationVersion} "success"
+// @Router /apps/installed/{id}/nginx [get]
+func (*BaseApi) ListNginxVersions(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := nginxService.ListVersions(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 预览插件Nginx配置
+// @Schemes
+// @Description 渲染模板并在Nginx容器的临时目录中执行 nginx -t，不会重新加载Nginx。template 为空时使用 version 对应的历史版本
+// @Security BearerAuth
+// @Tags nginx
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Param data body request.NginxLocationApply true "RequestBody"
+// @Success 200 {object} dto.Response{data=nginx.PreviewResult} "success"
+// @Router /apps/installed/{id}/nginx/preview [post]
+func (*BaseApi) PreviewNginxLocation(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.NginxLocationApply
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := nginxService.Preview(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 应用插件Nginx配置
+// @Schemes
+// @Description 检测通过后应用配置并保存为新版本，应用失败时恢复原配置。template 为空时使用 version 对应的历史版本
+// @Security BearerAuth
+// @Tags nginx
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Param data body request.NginxLocationApply true "RequestBody"
+// @Success 200 {object} dto.Response{data=model.NginxLocationVersion} "success"
+// @Router /apps/installed/{id}/nginx/apply [post]
+func (*BaseApi) ApplyNginxLocation(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.NginxLocationApply
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := nginxService.Apply(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 修改插件域名
+// @Schemes
+// @Description 设置插件的独立域名，domain 为空时使用 <key>.<PLUGIN_BASE_DOMAIN>。插件使用独立域名时会重新写入Nginx配置
+// @Security BearerAuth
+// @Tags nginx
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Param data body request.AppDomain true "RequestBody"
+// @Success 200 {object} dto.Response "success"
+// @Router /apps/installed/{id}/domain [put]
+func (*BaseApi) UpdateAppDomain(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppDomain
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	err = nginxService.UpdateDomain(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, nil)
+}
+
+// @Summary 获取插件访问控制配置
+// @Schemes
+// @Description 获取插件的IP白名单/黑名单、限流、请求体大小、Basic Auth 与响应头配置，不返回 Basic Auth 的密码
+// @Security BearerAuth
+// @Tags nginx
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Success 200 {object} dto.Response{data=nginx.LocationOptions} "success"
+// @Router /apps/installed/{id}/nginx/options [get]
+func (*BaseApi) GetNginxOptions(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	result, err := nginxService.GetOptions(dto.NewServiceContext(c), int64(id))
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 修改插件访问控制配置
+// @Schemes
+// @Description 配置经过校验后渲染到插件的 location 中，检测通过后重新加载Nginx生效，不会重启插件容器。Basic Auth 用户未提交密码时沿用原密码
+// @Security BearerAuth
+// @Tags nginx
+// @Accept json
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path int true "ID"
+// @Param data body request.NginxLocationOptions true "RequestBody"
+// @Success 200 {object} dto.Response{data=nginx.LocationOptions} "success"
+// @Router /apps/installed/{id}/nginx/options [put]
+func (*BaseApi) UpdateNginxOptions(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.NginxLocationOptions
+	if err := helper.ValidateJSONRequest(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := nginxService.UpdateOptions(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
@@ -1,7 +1,16 @@
 package v1
 
 import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/api/v1/helper"
+	"doo-store/backend/core/dto"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 )
 
 // @Summary health
@@ -18,3 +27,48 @@ func (b *BaseApi) HealthCheck(c *gin.Context) {
 		"status": "ok",
 	})
 }
+
+// @Summary 插件清单的 JSON Schema
+// @Schemes
+// @Description 用于校验 data.json 中的插件清单（dto.Plugin）
+// @Tags public
+// @Produce json
+// @Success 200 {object} object "JSON Schema"
+// @Router /public/plugin-schema [get]
+func (b *BaseApi) PluginSchema(c *gin.Context) {
+	c.Data(200, "application/schema+json", dto.PluginSchema)
+}
+
+// @Summary 插件登录校验
+// @Schemes
+// @Description 供 Nginx auth_request 调用，校验请求中的 token（请求头、Cookie 或原始请求的参数），通过时返回 200 并在响应头中携带用户ID与身份，否则返回 401
+// @Tags public
+// @Param token header string false "token"
+// @Param X-Original-URI header string false "原始请求地址"
+// @Success 200 {string} string ""
+// @Failure 401 {string} string ""
+// @Router /public/auth-check [get]
+func (b *BaseApi) AuthCheck(c *gin.Context) {
+	token := helper.Token(c)
+	if token == "" {
+		// auth_request 子请求不包含原始请求的参数，从原始请求地址中读取
+		if originalURL, err := url.Parse(c.GetHeader("X-Original-URI")); err == nil {
+			token = originalURL.Query().Get("token")
+		}
+	}
+	if token == "" {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	info, err := dootaskService.GetCachedUserInfo(token)
+	if err != nil {
+		log.Debug("插件登录校验失败:", err)
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	if info.UserBasicResp != nil {
+		c.Header(constant.AuthHeaderUserID, strconv.Itoa(info.Userid))
+	}
+	c.Header(constant.AuthHeaderIdentity, strings.Join(info.Identity, ","))
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"doo-store/backend/core/api/v1/helper"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 获取插件状态变化记录
+// @Schemes
+// @Description 插件（kind=app）及其服务容器（kind=service）的状态变化，按时间倒序排列
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Param page query integer true "页码" default(1)
+// @Param page_size query integer true "每页条数" default(100)
+// @Param kind query string false "记录对象" Enums(app, service)
+// @Param since query integer false "开始时间(Unix时间戳，秒)"
+// @Param until query integer false "结束时间(Unix时间戳，秒)"
+// @Success 200 {object} dto.Response{data=dto.PageResult{items=[]model.AppStatusTransition}} "success"
+// @Router /apps/installed/{id}/status-history [get]
+func (*BaseApi) ListStatusHistory(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppStatusHistorySearch
+	if err := helper.ValidateQueryParams(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+
+	result, err := statusHistoryService.ListStatusHistory(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
+
+// @Summary 获取插件可用率
+// @Schemes
+// @Description 根据状态变化记录统计可用率与平均故障间隔（MTBF），只有 Running 计为运行正常，停止与安装中的时间不计入统计，从 Running 变为 Error、Dead 或 UnHealthy 计为一次故障
+// @Security BearerAuth
+// @Tags app
+// @Produce json
+// @Param language header string false "i18n" default(zh)
+// @Param id path integer true "id"
+// @Param hours query integer false "统计最近多少小时，最多 2160" default(168)
+// @Success 200 {object} dto.Response{data=response.AppUptime} "success"
+// @Router /apps/installed/{id}/uptime [get]
+func (*BaseApi) GetAppUptime(c *gin.Context) {
+	err := checkAuth(c, true)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.Atoi(c.Param("id"))
+	var req request.AppUptimeSearch
+	if err := helper.ValidateQueryParams(c, &req); err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	req.InstalledId = int64(id)
+	if req.Hours <= 0 || req.Hours > 2160 {
+		req.Hours = 168
+	}
+
+	result, err := statusHistoryService.GetUptime(dto.NewServiceContext(c), req)
+	if err != nil {
+		helper.ErrorWith(c, err.Error(), nil)
+		return
+	}
+	helper.SuccessWith(c, result)
+}
@@ -22,7 +22,7 @@ func Generate() {
 	// // reuse your gorm db
 	// g.UseDB(gormdb)
 
-	g.ApplyBasic(model.App{}, model.AppDetail{}, model.AppInstalled{}, model.AppServiceStatus{}, model.AppTag{}, model.Tag{}, model.AppLog{})
+	g.ApplyBasic(model.App{}, model.AppDetail{}, model.AppInstalled{}, model.AppServiceStatus{}, model.AppTag{}, model.Tag{}, model.AppLog{}, model.AppBackupSchedule{}, model.IpAllocation{}, model.NginxLocationVersion{}, model.Certificate{}, model.AppStatusTransition{}, model.AppRestartPolicy{})
 
 	// Generate the code
 	g.Execute()
@@ -18,7 +18,7 @@ func Migrate() {
 	if err != nil {
 		panic(fmt.Errorf("db connection failed: %v", err))
 	}
-	err = db.AutoMigrate(&model.App{}, &model.AppDetail{}, &model.AppInstalled{}, &model.AppServiceStatus{}, &model.AppTag{}, &model.Tag{}, &model.AppLog{})
+	err = db.AutoMigrate(&model.App{}, &model.AppDetail{}, &model.AppInstalled{}, &model.AppServiceStatus{}, &model.AppTag{}, &model.Tag{}, &model.AppLog{}, &model.AppBackupSchedule{}, &model.IpAllocation{}, &model.NginxLocationVersion{}, &model.Certificate{}, &model.AppStatusTransition{}, &model.AppRestartPolicy{})
 	if err != nil {
 		panic(fmt.Errorf("db migrate failed: %v", err))
 	}
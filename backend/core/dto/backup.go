@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// BackupFormatVersion 当前备份归档格式版本
+const BackupFormatVersion = 1
+
+// 备份归档中的文件布局
+const (
+	BackupManifestFile = "manifest.json" // 备份清单
+	BackupWorkspaceDir = "workspace"     // 插件工作目录
+	BackupVolumesDir   = "volumes"       // 命名卷数据
+)
+
+// BackupManifest 备份清单，描述备份时插件的安装信息
+type BackupManifest struct {
+	FormatVersion int             `json:"format_version"`
+	Key           string          `json:"key"`
+	Version       string          `json:"version"`
+	ContainerName string          `json:"container_name"`
+	IpAddress     string          `json:"ip_address"`
+	Ip6Address    string          `json:"ip6_address,omitempty"`
+	Params        string          `json:"params"` // 解密后的参数，轮换密钥后备份仍然可以恢复
+	DockerCompose string          `json:"docker_compose"`
+	Location      string          `json:"location"`
+	Domain        string          `json:"domain,omitempty"`
+	Services      []BackupService `json:"services"`
+	Volumes       []string        `json:"volumes"` // compose 中声明的卷键名
+	AppID         string          `json:"app_id"`  // 备份时的 DooTask APP_ID
+	AppIPPR       string          `json:"app_ippr"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// BackupService 备份时插件的服务信息
+type BackupService struct {
+	ServiceName   string `json:"service_name"`
+	ContainerName string `json:"container_name"`
+	IpAddress     string `json:"ip_address"`
+	Image         string `json:"image"`
+}
@@ -0,0 +1,178 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldFilesDir 文件类型字段在插件工作目录中的保存目录
+const FieldFilesDir = "files"
+
+// KeyValue 键值对列表中的一项
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// FlattenFields 获取所有字段（包含选项的子字段），以环境变量名为键
+func FlattenFields(fields []*FormField) map[string]*FormField {
+	result := map[string]*FormField{}
+	for _, field := range fields {
+		result[field.EnvKey] = field
+		for i := range field.Options {
+			for j := range field.Options[i].SubFields {
+				subField := &field.Options[i].SubFields[j]
+				result[subField.EnvKey] = subField
+			}
+		}
+	}
+	return result
+}
+
+// EnvValue 将字段的值序列化为 .env 文件中的值（未转义）
+//   - switch: true/false
+//   - number、port: 不带多余小数位的数字
+//   - checkbox: 逗号分隔的选项值
+//   - key_value: 按键排序的 JSON 对象
+//   - json: 压缩后的 JSON
+//   - file: 文件在工作目录中的相对路径，可直接在 docker-compose 中挂载
+func (f *FormField) EnvValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	switch f.Type {
+	case FieldTypeSwitch:
+		b, err := strconv.ParseBool(FormatEnvValue(value))
+		if err != nil {
+			return "false"
+		}
+		return strconv.FormatBool(b)
+	case FieldTypeKeyValue:
+		items, err := parseKeyValue(value)
+		if err != nil {
+			return FormatEnvValue(value)
+		}
+		object := map[string]string{}
+		for _, item := range items {
+			object[item.Key] = item.Value
+		}
+		data, _ := json.Marshal(object)
+		return string(data)
+	case FieldTypeJSON:
+		if raw, ok := value.(string); ok {
+			data, err := compactJSON(raw)
+			if err != nil {
+				return raw
+			}
+			return string(data)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return FormatEnvValue(value)
+		}
+		return string(data)
+	case FieldTypeFile:
+		if FormatEnvValue(value) == "" {
+			return ""
+		}
+		return "./" + FieldFilesDir + "/" + f.EnvKey
+	default:
+		return FormatEnvValue(value)
+	}
+}
+
+// FormatEnvValue 将任意值格式化为字符串
+func FormatEnvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = FormatEnvValue(item)
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// DecodeFileValue 解析文件类型字段的值，支持 data URL（base64）与纯文本
+func DecodeFileValue(value string) ([]byte, error) {
+	if !strings.HasPrefix(value, "data:") {
+		return []byte(value), nil
+	}
+	index := strings.Index(value, ",")
+	if index < 0 {
+		return nil, errors.New("invalid data url")
+	}
+	meta, data := value[5:index], value[index+1:]
+	if !strings.HasSuffix(meta, ";base64") {
+		return []byte(data), nil
+	}
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// parseKeyValue 解析键值对列表，支持 [{"key":"","value":""}]、{"key":"value"} 以及二者的 JSON 字符串
+func parseKeyValue(value interface{}) ([]KeyValue, error) {
+	if raw, ok := value.(string); ok {
+		if strings.TrimSpace(raw) == "" {
+			return []KeyValue{}, nil
+		}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, err
+		}
+	}
+	items := []KeyValue{}
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid key value item")
+			}
+			key, _ := object["key"].(string)
+			items = append(items, KeyValue{Key: key, Value: FormatEnvValue(object["value"])})
+		}
+	case map[string]interface{}:
+		for key, val := range v {
+			items = append(items, KeyValue{Key: key, Value: FormatEnvValue(val)})
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	default:
+		return nil, errors.New("invalid key value list")
+	}
+	seen := map[string]bool{}
+	for _, item := range items {
+		if strings.TrimSpace(item.Key) == "" || seen[item.Key] {
+			return nil, errors.New("empty or duplicate key")
+		}
+		seen[item.Key] = true
+	}
+	return items, nil
+}
+
+func compactJSON(raw string) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"doo-store/backend/constant"
+	"strings"
+)
+
+// RuleType 跨字段验证规则类型
+type RuleType string
+
+const (
+	RuleEquals     RuleType = "equals"       // Fields 中所有字段的值必须相等，如确认密码
+	RuleAtLeastOne RuleType = "at_least_one" // Fields 中至少填写一个
+	RuleRequiredIf RuleType = "required_if"  // 满足 When 条件时 Fields 必填
+)
+
+// FormRule 定义跨字段验证规则，在 ValidateFormData 中于字段验证之后执行
+// 只对参与验证的字段生效，隐藏或依赖条件不满足的字段会被忽略
+type FormRule struct {
+	Type   RuleType    `json:"type"`
+	Fields []string    `json:"fields"`         // 规则涉及的字段（环境变量名）
+	When   *Dependency `json:"when,omitempty"` // required_if 的触发条件
+}
+
+// validateRules 验证跨字段规则，已存在验证错误的字段不再重复报告
+func (v *formValidator) validateRules(rules []*FormRule) {
+	failed := map[string]bool{}
+	for _, err := range v.errors {
+		failed[err.EnvKey] = true
+	}
+	for _, rule := range rules {
+		fields := make([]*FormField, 0, len(rule.Fields))
+		for _, envKey := range rule.Fields {
+			if field, ok := v.active[envKey]; ok && !failed[envKey] {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch rule.Type {
+		case RuleEquals:
+			first := fields[0]
+			for _, field := range fields[1:] {
+				if v.fieldValues[field.EnvKey] != v.fieldValues[first.EnvKey] {
+					v.addRuleError(failed, newValidationError(*field, constant.ErrFormRuleEquals, map[string]any{"target": first.Label}))
+				}
+			}
+		case RuleAtLeastOne:
+			labels := make([]string, 0, len(fields))
+			filled := false
+			for _, field := range fields {
+				labels = append(labels, field.Label)
+				if strings.TrimSpace(v.fieldValues[field.EnvKey]) != "" {
+					filled = true
+				}
+			}
+			if !filled {
+				v.addRuleError(failed, newValidationError(*fields[0], constant.ErrFormRuleAtLeastOne, map[string]any{"fields": strings.Join(labels, ", ")}))
+			}
+		case RuleRequiredIf:
+			if rule.When == nil || !v.checkDependency(rule.When) {
+				continue
+			}
+			for _, field := range fields {
+				if strings.TrimSpace(v.fieldValues[field.EnvKey]) == "" {
+					v.addRuleError(failed, newValidationError(*field, constant.ErrFormFieldRequired, nil))
+				}
+			}
+		}
+	}
+}
+
+func (v *formValidator) addRuleError(failed map[string]bool, err *ValidationError) {
+	failed[err.EnvKey] = true
+	v.errors = append(v.errors, err)
+}
@@ -1,9 +1,17 @@
 package dto
 
 import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/model"
+	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type FieldType string
@@ -15,13 +23,23 @@ const (
 	FieldTypePassword FieldType = "password"
 	FieldTypeRadio    FieldType = "radio"
 	FieldTypeCheckbox FieldType = "checkbox"
+	FieldTypeTextarea FieldType = "textarea"  // 多行文本
+	FieldTypeSwitch   FieldType = "switch"    // 开关，值为 true/false
+	FieldTypeEmail    FieldType = "email"     // 邮箱
+	FieldTypeURL      FieldType = "url"       // 链接
+	FieldTypePort     FieldType = "port"      // 端口，1-65535
+	FieldTypeFile     FieldType = "file"      // 文件上传，内容为文本或 data URL，保存到工作目录后挂载到容器
+	FieldTypeKeyValue FieldType = "key_value" // 键值对列表
+	FieldTypeJSON     FieldType = "json"      // JSON
+	FieldTypeDuration FieldType = "duration"  // 时长，如 30s、1h30m
 )
 
 // Option 定义选项结构
 type Option struct {
-	Label     string      `json:"label"`
-	Value     string      `json:"value"`
-	SubFields []FormField `json:"sub_fields,omitempty"` // 该选项特有的子字段
+	Label     string         `json:"label"`
+	LabelI18n model.I18nText `json:"label_i18n,omitempty"` // 多语言的选项名称
+	Value     string         `json:"value"`
+	SubFields []FormField    `json:"sub_fields,omitempty"` // 该选项特有的子字段
 }
 
 // Dependency 定义字段间的依赖关系
@@ -33,25 +51,49 @@ type Dependency struct {
 
 // Validation 定义字段验证规则
 type Validation struct {
-	Required bool   `json:"required"`
-	Pattern  string `json:"pattern,omitempty"`
-	MinLen   *int   `json:"min_len,omitempty"`
-	MaxLen   *int   `json:"max_len,omitempty"`
+	Required bool     `json:"required"`
+	Pattern  string   `json:"pattern,omitempty"`
+	MinLen   *int     `json:"min_len,omitempty"`
+	MaxLen   *int     `json:"max_len,omitempty"`
+	Min      *float64 `json:"min,omitempty"` // 数值最小值
+	Max      *float64 `json:"max,omitempty"` // 数值最大值
 }
 
 // FormField 定义通用的表单字段结构
 type FormField struct {
-	Label       string      `json:"label"`
-	EnvKey      string      `json:"env_key"`
-	Type        FieldType   `json:"type"`
-	Default     interface{} `json:"default,omitempty"`
-	Options     []Option    `json:"options,omitempty"`
-	Validation  *Validation `json:"validation,omitempty"`
-	Dependency  *Dependency `json:"dependency,omitempty"`
-	Placeholder string      `json:"placeholder,omitempty"`
-	Order       int         `json:"order"`              // 显示顺序
-	Hidden      bool        `json:"hidden,omitempty"`   // 是否隐藏
-	ReadOnly    bool        `json:"readonly,omitempty"` // 是否只读
+	Label           string         `json:"label"`
+	LabelI18n       model.I18nText `json:"label_i18n,omitempty"` // 多语言的字段名称
+	EnvKey          string         `json:"env_key"`
+	Type            FieldType      `json:"type"`
+	Default         interface{}    `json:"default,omitempty"`
+	Options         []Option       `json:"options,omitempty"`
+	Validation      *Validation    `json:"validation,omitempty"`
+	Dependency      *Dependency    `json:"dependency,omitempty"`
+	Placeholder     string         `json:"placeholder,omitempty"`
+	PlaceholderI18n model.I18nText `json:"placeholder_i18n,omitempty"` // 多语言的占位提示
+	Order           int            `json:"order"`                      // 显示顺序
+	Hidden          bool           `json:"hidden,omitempty"`           // 是否隐藏
+	ReadOnly        bool           `json:"readonly,omitempty"`         // 是否只读
+	Generator       *Generator     `json:"generator,omitempty"`        // 服务端生成值的规则
+}
+
+// Localize 将字段名称、占位提示与选项名称（包含子字段）替换为指定语言的翻译
+func (f *FormField) Localize(lang string) {
+	f.Label = f.LabelI18n.Get(lang, f.Label)
+	f.Placeholder = f.PlaceholderI18n.Get(lang, f.Placeholder)
+	for i := range f.Options {
+		f.Options[i].Label = f.Options[i].LabelI18n.Get(lang, f.Options[i].Label)
+		for j := range f.Options[i].SubFields {
+			f.Options[i].SubFields[j].Localize(lang)
+		}
+	}
+}
+
+// LocalizeFormFields 将表单字段替换为指定语言的翻译
+func LocalizeFormFields(fields []*FormField, lang string) {
+	for _, field := range fields {
+		field.Localize(lang)
+	}
 }
 
 // FormConfig 定义表单配置结构
@@ -59,21 +101,71 @@ type FormConfig struct {
 	Fields []FormField `json:"fields"`
 }
 
+// SecretEnvKeys 获取密码类型字段（包含选项的子字段）的环境变量名
+func SecretEnvKeys(fields []*FormField) []string {
+	keys := []string{}
+	for _, field := range fields {
+		if field.Type == FieldTypePassword {
+			keys = append(keys, field.EnvKey)
+		}
+		for _, option := range field.Options {
+			for _, subField := range option.SubFields {
+				if subField.Type == FieldTypePassword {
+					keys = append(keys, subField.EnvKey)
+				}
+			}
+		}
+	}
+	return keys
+}
+
 // ValidationError 定义验证错误
 type ValidationError struct {
-	Field   string
-	Message string
+	Field     string         // 字段名称
+	EnvKey    string         // 字段对应的环境变量名
+	MessageID string         // 错误信息的国际化ID
+	Data      map[string]any // 错误信息的模板数据，包含字段名称 field
+}
+
+func newValidationError(field FormField, messageID string, data map[string]any) *ValidationError {
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["field"] = field.Label
+	return &ValidationError{
+		Field:     field.Label,
+		EnvKey:    field.EnvKey,
+		MessageID: messageID,
+		Data:      data,
+	}
 }
 
 func (e *ValidationError) Error() string {
-	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	return fmt.Sprintf("%s: %s", e.Field, e.MessageID)
+}
+
+// ValidationErrors 表单的全部验证错误
+// 作为 error 返回时信息为 ErrPluginParamInvalid，接口通过 helper.ErrorWith 返回每个字段的错误
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	return constant.ErrPluginParamInvalid
+}
+
+// FieldError 接口返回的单个字段的验证错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段对应的环境变量名
+	Label   string `json:"label"`   // 字段名称
+	Message string `json:"message"` // 已翻译的错误信息
 }
 
 type formValidator struct {
 	fields      []*FormField
 	params      map[string]interface{}
 	fieldValues map[string]string
-	errors      []error
+	errors      ValidationErrors
+	// 参与验证的字段（未隐藏、依赖条件满足，子字段所属的选项已选中），以环境变量名为键
+	active map[string]*FormField
 }
 
 func FillAndValidateForm(config []*FormField, params map[string]interface{}) []*FormField {
@@ -89,8 +181,12 @@ func FillAndValidateForm(config []*FormField, params map[string]interface{}) []*
 	return filledConfig
 }
 
-func ValidateFormData(config []*FormField, params map[string]interface{}) []error {
-	return newFormValidator(config, params).validate()
+// ValidateFormData 验证表单数据与跨字段规则，返回所有字段的验证错误
+func ValidateFormData(config []*FormField, params map[string]interface{}, rules []*FormRule) ValidationErrors {
+	validator := newFormValidator(config, params)
+	validator.validate()
+	validator.validateRules(rules)
+	return validator.errors
 }
 
 // newFormValidator 创建新的验证器实例
@@ -99,7 +195,8 @@ func newFormValidator(fields []*FormField, params map[string]interface{}) *formV
 		fields:      fields,
 		params:      params,
 		fieldValues: make(map[string]string),
-		errors:      make([]error, 0),
+		errors:      make(ValidationErrors, 0),
+		active:      make(map[string]*FormField),
 	}
 }
 
@@ -130,7 +227,7 @@ func (v *formValidator) fillConfigValues() []*FormField {
 }
 
 // validate 执行验证流程
-func (v *formValidator) validate() []error {
+func (v *formValidator) validate() ValidationErrors {
 	v.preprocessParams()
 	v.validateFields()
 	return v.errors
@@ -168,31 +265,46 @@ func (v *formValidator) preprocessParams() {
 // validateFields 验证所有字段
 func (v *formValidator) validateFields() {
 	for _, field := range v.fields {
-		if field.Hidden {
-			continue
-		}
+		v.validateFieldTree(field)
+	}
+}
 
-		value, exists := v.fieldValues[field.EnvKey]
+// validateFieldTree 验证字段及其已选中选项的子字段
+func (v *formValidator) validateFieldTree(field *FormField) {
+	if field.Hidden {
+		return
+	}
+
+	value, exists := v.fieldValues[field.EnvKey]
+
+	if field.Dependency != nil && !v.checkDependency(field.Dependency) {
+		return
+	}
+	v.active[field.EnvKey] = field
 
-		if field.Dependency != nil && !v.checkDependency(field.Dependency) {
+	if err := v.validateField(*field, value, exists); err != nil {
+		v.errors = append(v.errors, err)
+	}
+
+	// 选中的选项的子字段
+	selected := strings.Split(value, ",")
+	for i := range field.Options {
+		option := &field.Options[i]
+		if len(option.SubFields) == 0 || !slices.Contains(selected, option.Value) {
 			continue
 		}
-
-		if err := v.validateField(*field, value, exists); err != nil {
-			v.errors = append(v.errors, err)
+		for j := range option.SubFields {
+			v.validateFieldTree(&option.SubFields[j])
 		}
 	}
 }
 
 // validateField 验证单个字段
-func (v *formValidator) validateField(field FormField, value string, exists bool) error {
+func (v *formValidator) validateField(field FormField, value string, exists bool) *ValidationError {
 	// 验证必填
 	if field.Validation != nil && field.Validation.Required {
 		if !exists || strings.TrimSpace(value) == "" {
-			return &ValidationError{
-				Field:   field.Label,
-				Message: "此字段为必填项",
-			}
+			return newValidationError(field, constant.ErrFormFieldRequired, nil)
 		}
 	}
 
@@ -211,40 +323,63 @@ func (v *formValidator) validateField(field FormField, value string, exists bool
 }
 
 // validateFieldType 验证字段类型
-func (v *formValidator) validateFieldType(field FormField, value string) error {
+func (v *formValidator) validateFieldType(field FormField, value string) *ValidationError {
 	switch field.Type {
 	case FieldTypeNumber:
 		if matched, _ := regexp.MatchString(`^-?\d+(\.\d+)?$`, value); !matched {
-			return &ValidationError{
-				Field:   field.Label,
-				Message: "请输入有效的数字",
-			}
+			return newValidationError(field, constant.ErrFormFieldInvalidNumber, nil)
 		}
 	case FieldTypeSelect, FieldTypeRadio:
 		if len(field.Options) > 0 {
 			if !v.isValidOption(field.Options, value) {
-				return &ValidationError{
-					Field:   field.Label,
-					Message: "请选择有效的选项",
-				}
+				return newValidationError(field, constant.ErrFormFieldInvalidOption, nil)
 			}
 		}
 	case FieldTypeCheckbox:
 		values := strings.Split(value, ",")
 		for _, val := range values {
 			if !v.isValidOption(field.Options, val) {
-				return &ValidationError{
-					Field:   field.Label,
-					Message: "存在无效的选项值",
-				}
+				return newValidationError(field, constant.ErrFormFieldInvalidOption, nil)
 			}
 		}
+	case FieldTypeSwitch:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return newValidationError(field, constant.ErrFormFieldInvalidSwitch, nil)
+		}
+	case FieldTypeEmail:
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			return newValidationError(field, constant.ErrFormFieldInvalidEmail, nil)
+		}
+	case FieldTypeURL:
+		if u, err := url.ParseRequestURI(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return newValidationError(field, constant.ErrFormFieldInvalidURL, nil)
+		}
+	case FieldTypePort:
+		if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+			return newValidationError(field, constant.ErrFormFieldInvalidPort, map[string]any{"min": 1, "max": 65535})
+		}
+	case FieldTypeFile:
+		if _, err := DecodeFileValue(value); err != nil {
+			return newValidationError(field, constant.ErrFormFieldInvalidFile, nil)
+		}
+	case FieldTypeKeyValue:
+		if _, err := parseKeyValue(v.params[field.EnvKey]); err != nil {
+			return newValidationError(field, constant.ErrFormFieldInvalidKeyValue, nil)
+		}
+	case FieldTypeJSON:
+		if raw, ok := v.params[field.EnvKey].(string); ok && !json.Valid([]byte(raw)) {
+			return newValidationError(field, constant.ErrFormFieldInvalidJSON, nil)
+		}
+	case FieldTypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return newValidationError(field, constant.ErrFormFieldInvalidDuration, nil)
+		}
 	}
 	return nil
 }
 
 // validateFieldFormat 验证字段格式
-func (v *formValidator) validateFieldFormat(field FormField, value string) error {
+func (v *formValidator) validateFieldFormat(field FormField, value string) *ValidationError {
 	if field.Validation == nil {
 		return nil
 	}
@@ -252,30 +387,31 @@ func (v *formValidator) validateFieldFormat(field FormField, value string) error
 	if field.Validation.Pattern != "" {
 		matched, err := regexp.MatchString(field.Validation.Pattern, value)
 		if err != nil {
-			return &ValidationError{
-				Field:   field.Label,
-				Message: "正则表达式验证失败",
-			}
+			return newValidationError(field, constant.ErrFormFieldPatternInvalid, nil)
 		}
 		if !matched {
-			return &ValidationError{
-				Field:   field.Label,
-				Message: "输入格式不正确",
-			}
+			return newValidationError(field, constant.ErrFormFieldPatternMismatch, nil)
 		}
 	}
 
 	if field.Validation.MinLen != nil && len(value) < *field.Validation.MinLen {
-		return &ValidationError{
-			Field:   field.Label,
-			Message: fmt.Sprintf("长度不能小于 %d", *field.Validation.MinLen),
-		}
+		return newValidationError(field, constant.ErrFormFieldMinLen, map[string]any{"min": *field.Validation.MinLen})
 	}
 
 	if field.Validation.MaxLen != nil && len(value) > *field.Validation.MaxLen {
-		return &ValidationError{
-			Field:   field.Label,
-			Message: fmt.Sprintf("长度不能大于 %d", *field.Validation.MaxLen),
+		return newValidationError(field, constant.ErrFormFieldMaxLen, map[string]any{"max": *field.Validation.MaxLen})
+	}
+
+	if field.Validation.Min != nil || field.Validation.Max != nil {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return newValidationError(field, constant.ErrFormFieldInvalidNumber, nil)
+		}
+		if field.Validation.Min != nil && number < *field.Validation.Min {
+			return newValidationError(field, constant.ErrFormFieldMin, map[string]any{"min": *field.Validation.Min})
+		}
+		if field.Validation.Max != nil && number > *field.Validation.Max {
+			return newValidationError(field, constant.ErrFormFieldMax, map[string]any{"max": *field.Validation.Max})
 		}
 	}
 
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"crypto/rand"
+	"doo-store/backend/utils/common"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+)
+
+type GeneratorType string
+
+const (
+	GeneratorRandomString GeneratorType = "random_string" // 随机字符串
+	GeneratorUUID         GeneratorType = "uuid"          // UUID
+	GeneratorPassword     GeneratorType = "password"      // 随机密码
+	GeneratorPort         GeneratorType = "port"          // 下一个未被容器发布的端口，宿主机上非Docker进程占用的端口无法探测
+)
+
+const (
+	defaultGenerateLength = 16
+	defaultPortMin        = 10000
+	defaultPortMax        = 65535
+	randomStringChars     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+// Generator 定义字段值的服务端生成规则
+// 安装时对未填写的字段生成一次，生成的值与用户输入一样保存，修改参数时只有显式要求才会重新生成
+type Generator struct {
+	Type    GeneratorType `json:"type"`
+	Length  int           `json:"length,omitempty"`   // random_string、password 的长度，默认16
+	Charset string        `json:"charset,omitempty"`  // password 的字符集，与 common.GeneratePassword 的 t 参数一致
+	PortMin int           `json:"port_min,omitempty"` // port 的范围，默认 10000-65535
+	PortMax int           `json:"port_max,omitempty"`
+}
+
+// PortAllocator 在 [min, max] 范围内分配一个空闲端口
+type PortAllocator func(min, max int) (int, error)
+
+// Generate 生成字段值
+func (g *Generator) Generate(allocatePort PortAllocator) (string, error) {
+	length := g.Length
+	if length <= 0 {
+		length = defaultGenerateLength
+	}
+	switch g.Type {
+	case GeneratorRandomString:
+		return randomString(length)
+	case GeneratorUUID:
+		return uuid.NewString(), nil
+	case GeneratorPassword:
+		return common.GeneratePassword(length, g.Charset), nil
+	case GeneratorPort:
+		min, max := g.PortMin, g.PortMax
+		if min <= 0 {
+			min = defaultPortMin
+		}
+		if max <= 0 || max > defaultPortMax {
+			max = defaultPortMax
+		}
+		if allocatePort == nil {
+			return "", fmt.Errorf("port allocator is not available")
+		}
+		port, err := allocatePort(min, max)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d", port), nil
+	default:
+		return "", fmt.Errorf("unsupported generator type: %s", g.Type)
+	}
+}
+
+// GenerateFieldValues 为带有生成规则的字段（包含选项的子字段）生成值
+// 参数中已有非空值的字段不会重新生成，regenerate 中列出的字段总是重新生成
+func GenerateFieldValues(fields []*FormField, params map[string]interface{}, regenerate []string, allocatePort PortAllocator) error {
+	generate := func(field *FormField) error {
+		if field.Generator == nil {
+			return nil
+		}
+		if value, exists := params[field.EnvKey]; exists && value != nil && fmt.Sprintf("%v", value) != "" {
+			if !common.InArray(field.EnvKey, regenerate) {
+				return nil
+			}
+		}
+		value, err := field.Generator.Generate(allocatePort)
+		if err != nil {
+			return fmt.Errorf("generate value for %s failed: %w", field.EnvKey, err)
+		}
+		params[field.EnvKey] = value
+		return nil
+	}
+	for _, field := range fields {
+		if err := generate(field); err != nil {
+			return err
+		}
+		for i := range field.Options {
+			for j := range field.Options[i].SubFields {
+				if err := generate(&field.Options[i].SubFields[j]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func randomString(length int) (string, error) {
+	result := make([]byte, length)
+	max := big.NewInt(int64(len(randomStringChars)))
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = randomStringChars[n.Int64()]
+	}
+	return string(result), nil
+}
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"doo-store/backend/config"
+	"doo-store/backend/core/model"
 
 	"encoding/json"
 	"fmt"
@@ -9,20 +10,26 @@ import (
 )
 
 type Plugin struct {
-	Name           string       `json:"name"`
-	Key            string       `json:"key"`
-	Description    string       `json:"description"`
-	Icon           string       `json:"icon"`
-	Version        string       `json:"version"`
-	Github         string       `json:"github"`
-	Class          string       `json:"class"`
-	DependsVersion string       `json:"depends_version"`
-	Repo           string       `json:"repo"`
-	Volume         []Volume     `json:"volume"`
-	Env            []EnvElement `json:"env"`
-	Command        string       `json:"command"`
-	NginxConfig    string       `json:"nginx_config"`
-	DockerCompose  string       `json:"docker_compose"`
+	Name            string             `json:"name"`
+	NameI18n        model.I18nText     `json:"name_i18n,omitempty"` // 多语言的名称，键为语言代码
+	Key             string             `json:"key"`
+	Description     string             `json:"description"`
+	DescriptionI18n model.I18nText     `json:"description_i18n,omitempty"` // 多语言的描述，键为语言代码
+	Icon            string             `json:"icon"`
+	Version         string             `json:"version"`
+	Github          string             `json:"github"`
+	Class           string             `json:"class"`
+	DependsVersion  string             `json:"depends_version"`
+	Repo            string             `json:"repo"`
+	Volume          []Volume           `json:"volume"`
+	Env             []EnvElement       `json:"env"`
+	Rules           []*FormRule        `json:"rules,omitempty"` // 表单的跨字段验证规则
+	Command         string             `json:"command"`
+	NginxConfig     string             `json:"nginx_config"`
+	NginxAuth       bool               `json:"nginx_auth,omitempty"`      // 生成的 location 是否需要登录 DooTask 后才能访问
+	NginxRootPath   bool               `json:"nginx_root_path,omitempty"` // 插件不支持子路径，需要使用独立域名挂载在根路径
+	HealthCheck     *model.HealthCheck `json:"health_check,omitempty"`    // 镜像没有 HEALTHCHECK 时使用的HTTP健康检查
+	DockerCompose   string             `json:"docker_compose"`
 }
 
 type EnvElement struct {
@@ -118,6 +125,9 @@ func (p *Plugin) GenParams() string {
 	params := map[string]interface{}{
 		"form_fields": formFields,
 	}
+	if len(p.Rules) > 0 {
+		params["rules"] = p.Rules
+	}
 	jsonData, err := json.Marshal(params)
 	if err != nil {
 		return ""
@@ -125,12 +135,27 @@ func (p *Plugin) GenParams() string {
 	return string(jsonData)
 }
 
-// GenNginxConfig 生成Nginx配置信息
+// GenNginxConfig 生成Nginx配置信息，开启 NginxAuth 时通过 auth_request 校验登录状态，
+// 开启 NginxRootPath 时生成绑定独立域名的 server 块
 func (p *Plugin) GenNginxConfig() string {
 	if p.NginxConfig != "" {
 		return p.NginxConfig
 	}
-	return `location /plugin/{{.Key}}/ {
+	location := defaultNginxConfig
+	if p.NginxRootPath {
+		location = strings.Replace(location, "location /plugin/{{.Key}}/ {", "location / {", 1)
+	}
+	if p.NginxAuth {
+		location = "{{.AuthLocation}}\n\n" + strings.Replace(location, "{\n", "{\n\t{{.AuthRequest}}\n", 1)
+	}
+	if p.NginxRootPath {
+		return "server {\n\tlisten 80;\n\tserver_name {{.ServerName}};\n\t{{.TLS}}\n\n\t{{.ACMEChallenge}}\n\n\t" + strings.ReplaceAll(strings.ReplaceAll(location, "\n", "\n\t"), "\n\t\n", "\n\n") + "\n}"
+	}
+	return location
+}
+
+// defaultNginxConfig 插件未提供 nginx_config 时使用的 location 模板
+const defaultNginxConfig = `location /plugin/{{.Key}}/ {
 	proxy_http_version 1.1;
 	proxy_set_header X-Real-IP $remote_addr;
 	proxy_set_header X-Real-PORT $remote_port;
@@ -147,7 +172,6 @@ func (p *Plugin) GenNginxConfig() string {
 	proxy_set_header Connection $connection_upgrade;
 	proxy_pass http://{{.ContainerName}}:{{.Port}}/;
 }`
-}
 
 func (Plugin) getSpaces(num int) string {
 	spaces := strings.Repeat(" ", 2*num)
@@ -0,0 +1,8 @@
+package dto
+
+import _ "embed"
+
+// PluginSchema 插件清单（dto.Plugin）的 JSON Schema
+//
+//go:embed plugin.schema.json
+var PluginSchema []byte
@@ -1,6 +1,9 @@
 package request
 
-import "doo-store/backend/core/dto"
+import (
+	"doo-store/backend/core/dto"
+	"doo-store/backend/utils/nginx"
+)
 
 type AppSearch struct {
 	dto.PageInfo
@@ -19,6 +22,8 @@ type AppInstall struct {
 	MemoryLimit   string                 `json:"memory_limit" binding:"required"`
 	MemoryUnit    string                 `json:"memory_unit"`
 	Params        map[string]interface{} `json:"params" binding:"required"`
+	Regenerate    []string               `json:"regenerate"` // 修改参数时需要重新生成值的字段
+	Domain        string                 `json:"domain"`     // 插件的独立域名，为空时使用 <key>.<基础域名>
 }
 
 type AppUnInstall struct {
@@ -53,3 +58,75 @@ type PluginUpload struct {
 type GetInstalledPluginInfo struct {
 	Key string `form:"key" json:"-" binding:"required"`
 }
+
+type AppRestore struct {
+	InstalledId int64  `json:"-"`
+	File        string `json:"file" binding:"required"`
+}
+
+type AppBackupSchedule struct {
+	InstalledId int64  `json:"-"`
+	Cron        string `json:"cron"`
+	Retention   int    `json:"retention" binding:"min=0"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// AppRestartPolicy 关闭自动重启时其他参数可以不传，未传的参数保持原值
+type AppRestartPolicy struct {
+	InstalledId int64 `json:"-"`
+	Enabled     bool  `json:"enabled"`
+	MaxRestarts int   `json:"max_restarts" binding:"required_if=Enabled true,omitempty,min=1,max=100"`                 // 统计时间内最多自动重启的次数
+	Window      int   `json:"window" binding:"required_if=Enabled true,omitempty,min=1,max=1440"`                      // 统计崩溃次数的时间范围（分钟）
+	Backoff     int   `json:"backoff" binding:"required_if=Enabled true,omitempty,min=1,max=3600,ltefield=MaxBackoff"` // 首次重启前等待的秒数
+	MaxBackoff  int   `json:"max_backoff" binding:"required_if=Enabled true,omitempty,min=1,max=3600"`                 // 重启前最长等待的秒数
+}
+
+type IPAMSearch struct {
+	State string `form:"state" binding:"omitempty,oneof=reserved allocated excluded untracked"`
+}
+
+type IPRange struct {
+	Start string `json:"start" binding:"required"`
+	End   string `json:"end"`
+	Note  string `json:"note"`
+}
+
+type NginxLocationApply struct {
+	InstalledId int64  `json:"-"`
+	Template    string `json:"template"`
+	Version     int    `json:"version" binding:"min=0"`
+	Remark      string `json:"remark"`
+}
+
+type AppDomain struct {
+	InstalledId int64  `json:"-"`
+	Domain      string `json:"domain"` // 为空时使用 <key>.<基础域名>
+}
+
+type CertificateUpload struct {
+	Domain string `json:"domain"` // 为空时使用证书中的第一个域名
+	Cert   string `json:"cert" binding:"required"`
+	Key    string `json:"key" binding:"required"`
+}
+
+type CertificateIssue struct {
+	Domain string `json:"domain" binding:"required"`
+}
+
+type NginxLocationOptions struct {
+	InstalledId int64 `json:"-"`
+	nginx.LocationOptions
+}
+
+type AppStatusHistorySearch struct {
+	dto.PageInfo
+	InstalledId int64  `json:"-"`
+	Kind        string `form:"kind" json:"kind" binding:"omitempty,oneof=app service"` // 记录对象，为空时返回全部
+	Since       int64  `form:"since" json:"since"`                                     // 开始时间(Unix时间戳，秒)
+	Until       int64  `form:"until" json:"until"`                                     // 结束时间(Unix时间戳，秒)
+}
+
+type AppUptimeSearch struct {
+	InstalledId int64 `json:"-"`
+	Hours       int   `form:"hours" json:"hours"` // 统计最近多少小时，默认 168
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"doo-store/backend/core/dto"
 	"doo-store/backend/core/model"
+	"time"
 )
 
 // type FormField struct {
@@ -24,6 +25,7 @@ type AppDetail struct {
 
 type AppParams struct {
 	FormFields []*dto.FormField `json:"form_fields"`
+	Rules      []*dto.FormRule  `json:"rules,omitempty"` // 跨字段验证规则
 }
 
 type AppInstalledParamsResp struct {
@@ -37,6 +39,88 @@ type GetInstalledPluginInfoResp struct {
 	Name          string `json:"name"`
 	Key           string `json:"key"`
 	Location      string `json:"location"`
+	Domain        string `json:"domain,omitempty"` // 使用独立域名时插件的域名
 	Status        string `json:"status"`
 	CloudProvider string `json:"cloud_provider,omitempty"`
 }
+
+// AppHealth 插件的健康状态
+type AppHealth struct {
+	Status      string             `json:"status"`                 // 插件状态
+	Health      string             `json:"health"`                 // 健康状态，没有健康检查时为空
+	Message     string             `json:"message"`                // 状态说明
+	HealthCheck *model.HealthCheck `json:"health_check,omitempty"` // 插件声明的HTTP健康检查
+	Log         model.HealthLog    `json:"log"`                    // 最近的健康检查记录
+}
+
+// AppUptime 插件在统计时间段内的可用性
+type AppUptime struct {
+	Start           time.Time        `json:"start"`
+	End             time.Time        `json:"end"`
+	ObservedSeconds int64            `json:"observed_seconds"` // 计入统计的时长，不包括停止与安装中的时间
+	UpSeconds       int64            `json:"up_seconds"`       // 运行正常的时长
+	UptimePercent   float64          `json:"uptime_percent"`   // 可用率，没有可统计的时长时为 0
+	Failures        int              `json:"failures"`         // 从运行状态变为异常的次数
+	MTBFSeconds     *int64           `json:"mtbf_seconds"`     // 平均故障间隔，没有故障时为 null
+	StatusSeconds   map[string]int64 `json:"status_seconds"`   // 各状态的持续时长
+}
+
+type AppBackupFile struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	Scheduled bool      `json:"scheduled"` // 是否为定时备份，只有定时备份会按保留数量清理
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IP 地址在网段中的状态，除以下状态外还包括分配记录的 reserved、allocated、excluded
+const (
+	IPStateFree      = "free"      // 未使用
+	IPStateUntracked = "untracked" // 被容器使用但没有分配记录
+)
+
+type IPAddressInfo struct {
+	IpAddress   string     `json:"ip_address"`
+	State       string     `json:"state"`
+	OwnerType   string     `json:"owner_type"`
+	OwnerName   string     `json:"owner_name"`
+	InstallID   int64      `json:"install_id"`
+	AllocatedAt *time.Time `json:"allocated_at"`
+}
+
+type IPAMOverview struct {
+	Network   string           `json:"network"`
+	Network6  string           `json:"network6,omitempty"`
+	Total     int              `json:"total"`
+	Free      int              `json:"free"`
+	Reserved  int              `json:"reserved"`
+	Allocated int              `json:"allocated"`
+	Excluded  int              `json:"excluded"`
+	Untracked int              `json:"untracked"`
+	Addresses []*IPAddressInfo `json:"addresses"`
+}
+
+// IP 冲突类型
+const (
+	IPConflictDuplicate     = "duplicate"      // 多个容器使用同一个IP
+	IPConflictUntracked     = "untracked"      // 容器使用的IP没有分配记录
+	IPConflictOwnerMismatch = "owner_mismatch" // 容器与分配记录的使用方不一致
+	IPConflictMissing       = "missing"        // 已分配的IP没有容器使用
+	IPConflictOrphaned      = "orphaned"       // 分配记录对应的插件已不存在
+)
+
+type IPConflict struct {
+	IpAddress  string   `json:"ip_address"`
+	Type       string   `json:"type"`
+	Containers []string `json:"containers"`
+	InstallID  int64    `json:"install_id"`
+	OwnerName  string   `json:"owner_name"`
+	Message    string   `json:"message"`
+}
+
+type IPReconcileReport struct {
+	Network     string        `json:"network"`
+	Network6    string        `json:"network6,omitempty"`
+	Allocations int           `json:"allocations"`
+	Containers  int           `json:"containers"`
+	Conflicts   []*IPConflict `json:"conflicts"`
+}
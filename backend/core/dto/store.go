@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"doo-store/backend/core/model"
+	"time"
+)
+
+// StoreFormatVersion 当前整站导出归档格式版本
+const StoreFormatVersion = 1
+
+// 整站导出归档中的文件布局
+const (
+	StoreManifestFile = "store.json" // 整站导出清单
+	StoreInstalledDir = "installed"  // 已安装插件目录，每个插件的备份内容位于 installed/<key> 下
+)
+
+// StoreManifest 整站导出清单，包含插件目录与已安装插件信息
+type StoreManifest struct {
+	FormatVersion      int              `json:"format_version"`
+	AppID              string           `json:"app_id"`
+	AppIPPR            string           `json:"app_ippr"`
+	NetworkName        string           `json:"network_name"`
+	NginxContainerName string           `json:"nginx_container_name"`
+	WithVolumes        bool             `json:"with_volumes"`
+	Tags               []StoreTag       `json:"tags"`
+	Apps               []StoreApp       `json:"apps"`
+	Installed          []StoreInstalled `json:"installed"`
+	CreatedAt          time.Time        `json:"created_at"`
+}
+
+// StoreTag 插件分类
+type StoreTag struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Sort int    `json:"sort"`
+}
+
+// StoreApp 插件目录中的插件及其详情
+type StoreApp struct {
+	Name            string             `json:"name"`
+	Key             string             `json:"key"`
+	Icon            string             `json:"icon"`
+	Description     string             `json:"description"`
+	NameI18n        model.I18nText     `json:"name_i18n,omitempty"`
+	DescriptionI18n model.I18nText     `json:"description_i18n,omitempty"`
+	Github          string             `json:"github"`
+	Class           string             `json:"class"`
+	DependsVersion  string             `json:"depends_version"`
+	Sort            int                `json:"sort"`
+	Tags            []string           `json:"tags"` // 分类的 key
+	Repo            string             `json:"repo"`
+	Version         string             `json:"version"`
+	Params          string             `json:"params"`
+	DockerCompose   string             `json:"docker_compose"`
+	NginxConfig     string             `json:"nginx_config"`
+	HealthCheck     *model.HealthCheck `json:"health_check,omitempty"`
+	DetailStatus    string             `json:"detail_status"`
+}
+
+// StoreInstalled 已安装的插件
+type StoreInstalled struct {
+	Key            string               `json:"key"`
+	Dir            string               `json:"dir"` // 备份内容在归档中的目录
+	Status         string               `json:"status"`
+	BackupSchedule *StoreBackupSchedule `json:"backup_schedule,omitempty"`
+}
+
+// StoreBackupSchedule 插件的定时备份计划
+type StoreBackupSchedule struct {
+	Cron      string `json:"cron"`
+	Retention int    `json:"retention"`
+	Enabled   bool   `json:"enabled"`
+}
@@ -11,12 +11,21 @@ type App struct {
 	DependsVersion string `json:"depends_version"`
 	Sort           int    `json:"sort" gorm:"default:999"`
 	Status         string `json:"status" gorm:"size:20;not null;default:''"`
+	// 多语言的名称与描述，键为语言代码
+	NameI18n        I18nText `json:"name_i18n,omitempty" gorm:"type:text;serializer:json"`
+	DescriptionI18n I18nText `json:"description_i18n,omitempty" gorm:"type:text;serializer:json"`
 }
 
 func (*App) TableName() string {
 	return TableName("apps")
 }
 
+// Localize 将名称与描述替换为指定语言的翻译
+func (a *App) Localize(lang string) {
+	a.Name = a.NameI18n.Get(lang, a.Name)
+	a.Description = a.DescriptionI18n.Get(lang, a.Description)
+}
+
 const (
 	// 插件状态
 	PluginStatusRunning    = "Running"
@@ -43,6 +52,11 @@ const (
 	ContainerName = "CONTAINER_NAME"
 )
 
+// IsRunningStatus 判断插件状态是否表示容器正在运行
+func IsRunningStatus(status string) bool {
+	return status == PluginStatusRunning || status == PluginStatusUnHealthy
+}
+
 // 插件操作
 type PluginAction string
 
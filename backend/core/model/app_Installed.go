@@ -2,22 +2,32 @@ package model
 
 type AppInstalled struct {
 	BaseModel
-	Name          string `json:"name" gorm:"size:60;not null;default:''"`
-	IpAddress     string `json:"ip_address" gorm:"size:60;not null;default:''"`
-	AppID         int64  `json:"app_id"`
-	AppDetailID   int64  `json:"app_detail_id"`
-	Key           string `json:"key" gorm:"size:60"`
-	Repo          string `json:"repo"`
-	Class         string `json:"class"`
-	Version       string `json:"version" gorm:"size:40;not null;default:''"`
-	Params        string `json:"params" gorm:"type:text"`
-	Env           string `json:"env" gorm:"type:text"`
-	DockerCompose string `json:"docker_compose" gorm:"type:text"`
-	Message       string `json:"message" gorm:"default:''"`
-	Location      string `json:"location"`
-	Status        string `json:"status" gorm:"size:20;not null;default:''"`
+	Name          string    `json:"name" gorm:"size:60;not null;default:''"`
+	IpAddress     string    `json:"ip_address" gorm:"size:60;not null;default:''"`
+	Ip6Address    string    `json:"ip6_address" gorm:"size:60;not null;default:''"`
+	AppID         int64     `json:"app_id"`
+	AppDetailID   int64     `json:"app_detail_id"`
+	Key           string    `json:"key" gorm:"size:60"`
+	Repo          string    `json:"repo"`
+	Class         string    `json:"class"`
+	Version       string    `json:"version" gorm:"size:40;not null;default:''"`
+	Params        string    `json:"params" gorm:"type:text"`
+	Env           string    `json:"env" gorm:"type:text"`
+	DockerCompose string    `json:"docker_compose" gorm:"type:text"`
+	Message       string    `json:"message" gorm:"default:''"`
+	Location      string    `json:"location"`
+	Domain        string    `json:"domain" gorm:"size:255;not null;default:''"` // 管理员设置的独立域名
+	NginxOptions  string    `json:"-" gorm:"type:text"`                         // Nginx访问控制与限流配置，JSON格式
+	Health        string    `json:"health" gorm:"size:20;not null;default:''"`  // 健康状态，容器没有健康检查时为空
+	HealthLog     HealthLog `json:"-" gorm:"type:text;serializer:json"`         // 最近的健康检查记录
+	Status        string    `json:"status" gorm:"size:20;not null;default:''"`
 }
 
 func (*AppInstalled) TableName() string {
 	return TableName("app_installed")
 }
+
+// IsRunning 判断插件的容器是否在运行，健康检查失败的插件容器同样在运行
+func (a *AppInstalled) IsRunning() bool {
+	return IsRunningStatus(a.Status)
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// AppBackupSchedule 插件定时备份计划
+type AppBackupSchedule struct {
+	BaseModel
+	AppInstalledId int64      `json:"app_installed_id" gorm:"comment:安装ID;not null;uniqueIndex"`
+	Cron           string     `json:"cron" gorm:"size:100;comment:cron表达式;not null;default:''"`
+	Retention      int        `json:"retention" gorm:"comment:保留的定时备份数量，0表示不限制;not null;default:0"`
+	Enabled        bool       `json:"enabled" gorm:"comment:是否启用;not null;default:false"`
+	LastRunAt      *time.Time `json:"last_run_at" gorm:"comment:上次执行时间"`
+	NextRunAt      *time.Time `json:"next_run_at" gorm:"comment:下次执行时间"`
+	LastResult     string     `json:"last_result" gorm:"comment:上次执行结果;default:''"`
+}
+
+func (*AppBackupSchedule) TableName() string {
+	return TableName("app_backup_schedules")
+}
@@ -2,14 +2,15 @@ package model
 
 type AppDetail struct {
 	BaseModel
-	AppID          int64  `json:"app_id"`
-	Repo           string `json:"repo"`
-	Version        string `json:"version" gorm:"size:40;not null;default:''"`
-	DependsVersion string `json:"depends_version"`
-	Params         string `json:"-" gorm:"type:text"`
-	DockerCompose  string `json:"docker_compose" gorm:"type:text"`
-	NginxConfig    string `json:"nginx_config"`
-	Status         string `json:"status" gorm:"size:200;not null;default:''"`
+	AppID          int64        `json:"app_id"`
+	Repo           string       `json:"repo"`
+	Version        string       `json:"version" gorm:"size:40;not null;default:''"`
+	DependsVersion string       `json:"depends_version"`
+	Params         string       `json:"-" gorm:"type:text"`
+	DockerCompose  string       `json:"docker_compose" gorm:"type:text"`
+	NginxConfig    string       `json:"nginx_config"`
+	HealthCheck    *HealthCheck `json:"health_check,omitempty" gorm:"type:text;serializer:json"` // HTTP健康检查
+	Status         string       `json:"status" gorm:"size:200;not null;default:''"`
 }
 
 func (*AppDetail) TableName() string {
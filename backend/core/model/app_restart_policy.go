@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// 自动重启策略的默认值
+const (
+	DefaultRestartMaxRestarts = 5   // 统计时间内最多自动重启的次数
+	DefaultRestartWindow      = 10  // 统计崩溃次数的时间范围（分钟）
+	DefaultRestartBackoff     = 10  // 首次重启前等待的秒数
+	DefaultRestartMaxBackoff  = 300 // 重启前最长等待的秒数
+)
+
+// AppRestartPolicy 插件自动重启策略，插件主容器异常退出后等待一段时间重启，
+// 统计时间内异常退出次数超过上限时判定为崩溃循环，停止容器并标记为 Dead
+type AppRestartPolicy struct {
+	BaseModel
+	AppInstalledId int64      `json:"app_installed_id" gorm:"comment:安装ID;not null;uniqueIndex"`
+	Enabled        bool       `json:"enabled" gorm:"comment:是否启用;not null;default:false"`
+	MaxRestarts    int        `json:"max_restarts" gorm:"comment:统计时间内最多自动重启的次数;not null;default:0"`
+	Window         int        `json:"window" gorm:"comment:统计崩溃次数的时间范围（分钟）;not null;default:0"`
+	Backoff        int        `json:"backoff" gorm:"comment:首次重启前等待的秒数，之后每次翻倍;not null;default:0"`
+	MaxBackoff     int        `json:"max_backoff" gorm:"comment:重启前最长等待的秒数;not null;default:0"`
+	LastRestartAt  *time.Time `json:"last_restart_at" gorm:"comment:上次自动重启时间"`
+	GaveUpAt       *time.Time `json:"gave_up_at" gorm:"comment:判定为崩溃循环的时间"`
+}
+
+func (*AppRestartPolicy) TableName() string {
+	return TableName("app_restart_policies")
+}
+
+// NewAppRestartPolicy 使用默认值创建未启用的重启策略
+func NewAppRestartPolicy(appInstalledId int64) *AppRestartPolicy {
+	return &AppRestartPolicy{
+		AppInstalledId: appInstalledId,
+		MaxRestarts:    DefaultRestartMaxRestarts,
+		Window:         DefaultRestartWindow,
+		Backoff:        DefaultRestartBackoff,
+		MaxBackoff:     DefaultRestartMaxBackoff,
+	}
+}
+
+// BackoffDuration 第 failures 次异常退出后重启前等待的时间
+func (p *AppRestartPolicy) BackoffDuration(failures int) time.Duration {
+	delay := time.Duration(p.Backoff) * time.Second
+	maxDelay := time.Duration(p.MaxBackoff) * time.Second
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// WindowDuration 统计崩溃次数的时间范围
+func (p *AppRestartPolicy) WindowDuration() time.Duration {
+	return time.Duration(p.Window) * time.Minute
+}
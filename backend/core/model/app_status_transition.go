@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// 状态变化记录的对象
+const (
+	TransitionKindApp     = "app"     // 插件，状态为 PluginStatus*
+	TransitionKindService = "service" // 插件的服务容器，状态为容器状态
+)
+
+// AppStatusTransition 插件或服务的状态变化记录
+type AppStatusTransition struct {
+	ID            int64     `gorm:"primaryKey;not null;autoIncrement:true;comment:'id'" json:"id"`
+	InstallID     int64     `json:"install_id" gorm:"index:idx_status_transition;comment:安装ID;not null"`
+	Kind          string    `json:"kind" gorm:"size:10;index:idx_status_transition;comment:记录对象;not null;default:''"`
+	ContainerName string    `json:"container_name" gorm:"size:60;index:idx_status_transition;comment:容器名;not null;default:''"`
+	ServiceName   string    `json:"service_name" gorm:"size:60;comment:服务名;not null;default:''"`
+	From          string    `json:"from" gorm:"column:from_status;size:20;comment:原状态;not null;default:''"`
+	To            string    `json:"to" gorm:"column:to_status;size:20;comment:新状态;not null;default:''"`
+	Message       string    `json:"message" gorm:"comment:消息;default:''"`
+	ExitCode      *int      `json:"exit_code" gorm:"comment:容器退出码"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+func (*AppStatusTransition) TableName() string {
+	return TableName("app_status_transitions")
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// 证书来源
+const (
+	CertificateSourceUpload = "upload" // 管理员上传
+	CertificateSourceACME   = "acme"   // 通过ACME自动申请
+)
+
+// 证书状态
+const (
+	CertificateStatusPending = "pending" // 申请中
+	CertificateStatusValid   = "valid"   // 有效
+	CertificateStatusExpired = "expired" // 已过期
+	CertificateStatusFailed  = "failed"  // 申请或续期失败
+)
+
+// Certificate 插件独立域名使用的证书，Domain 可以是通配符域名
+type Certificate struct {
+	BaseModel
+	Domain    string     `json:"domain" gorm:"size:255;comment:域名;not null;uniqueIndex"`
+	Source    string     `json:"source" gorm:"size:20;comment:来源 upload/acme;not null;default:''"`
+	Status    string     `json:"status" gorm:"size:20;comment:状态;not null;default:''"`
+	Cert      string     `json:"cert" gorm:"type:text;comment:PEM格式的证书链"`
+	Key       string     `json:"-" gorm:"type:text;comment:加密后的PEM格式私钥"`
+	Issuer    string     `json:"issuer" gorm:"comment:颁发者;default:''"`
+	NotBefore *time.Time `json:"not_before" gorm:"comment:生效时间"`
+	NotAfter  *time.Time `json:"not_after" gorm:"comment:过期时间"`
+	AutoRenew bool       `json:"auto_renew" gorm:"comment:是否自动续期;not null;default:false"`
+	RenewedAt *time.Time `json:"renewed_at" gorm:"comment:上次签发时间"`
+	Message   string     `json:"message" gorm:"type:text;comment:上次申请或续期失败的原因"`
+}
+
+func (*Certificate) TableName() string {
+	return TableName("certificates")
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// HealthLogSize 保留的健康检查记录条数
+const HealthLogSize = 5
+
+// 插件健康状态，与Docker的健康状态一致
+const (
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// HealthCheck 插件声明的HTTP健康检查，镜像没有 HEALTHCHECK 时由商店访问该地址探测
+type HealthCheck struct {
+	Path         string `json:"path"`                    // 请求路径，例如 /healthz
+	Port         int    `json:"port"`                    // 容器端口
+	Interval     int    `json:"interval,omitempty"`      // 探测间隔（秒），默认 30
+	Timeout      int    `json:"timeout,omitempty"`       // 超时时间（秒），默认 5
+	Retries      int    `json:"retries,omitempty"`       // 连续失败多少次后判定为不健康，默认 3
+	ExpectStatus int    `json:"expect_status,omitempty"` // 期望的HTTP状态码，为空时接受 2xx 与 3xx
+}
+
+// IntervalDuration 探测间隔
+func (h *HealthCheck) IntervalDuration() time.Duration {
+	if h.Interval <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(h.Interval) * time.Second
+}
+
+// TimeoutDuration 超时时间
+func (h *HealthCheck) TimeoutDuration() time.Duration {
+	if h.Timeout <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(h.Timeout) * time.Second
+}
+
+// RetryCount 判定为不健康前允许的连续失败次数
+func (h *HealthCheck) RetryCount() int {
+	if h.Retries <= 0 {
+		return 3
+	}
+	return h.Retries
+}
+
+// Accept 判断HTTP状态码是否表示健康
+func (h *HealthCheck) Accept(statusCode int) bool {
+	if h.ExpectStatus != 0 {
+		return statusCode == h.ExpectStatus
+	}
+	return statusCode >= 200 && statusCode < 400
+}
+
+// HealthProbe 一次健康检查的结果
+type HealthProbe struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ExitCode int       `json:"exit_code"` // 0 表示成功
+	Output   string    `json:"output"`
+}
+
+// HealthLog 最近的健康检查记录，按时间先后排列
+type HealthLog []HealthProbe
+
+// Append 追加检查记录，只保留最近 HealthLogSize 条
+func (l HealthLog) Append(probes ...HealthProbe) HealthLog {
+	log := append(append(HealthLog{}, l...), probes...)
+	if len(log) > HealthLogSize {
+		log = log[len(log)-HealthLogSize:]
+	}
+	return log
+}
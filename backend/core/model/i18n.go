@@ -0,0 +1,30 @@
+package model
+
+import (
+	"doo-store/backend/config"
+	"strings"
+)
+
+// I18nText 多语言文本，键为语言代码（如 zh、en、zh-Hant），值为对应语言的文本
+type I18nText map[string]string
+
+// Get 获取指定语言的文本
+// 依次匹配完整语言代码、主语言（如 en-US 匹配 en）与默认语言，均不存在时返回 fallback
+func (t I18nText) Get(lang, fallback string) string {
+	if len(t) == 0 {
+		return fallback
+	}
+	candidates := []string{lang}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		candidates = append(candidates, base)
+	}
+	candidates = append(candidates, config.DefaultLanguage)
+	for _, candidate := range candidates {
+		for key, value := range t {
+			if value != "" && strings.EqualFold(key, candidate) {
+				return value
+			}
+		}
+	}
+	return fallback
+}
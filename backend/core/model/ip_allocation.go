@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// IP 地址的使用方
+const (
+	IPOwnerInstallation = "installation" // 插件安装的主容器
+	IPOwnerService      = "service"      // 插件中其他服务的容器
+	IPOwnerAdmin        = "admin"        // 管理员手动预留或排除
+)
+
+// IP 地址的分配状态
+const (
+	IPStateReserved  = "reserved"  // 已预留，插件尚未完成安装或由管理员预留
+	IPStateAllocated = "allocated" // 已分配给插件使用
+	IPStateReleased  = "released"  // 已释放，可以重新分配
+	IPStateExcluded  = "excluded"  // 已排除，不允许分配
+)
+
+// IpAllocation 插件网段中的 IP 分配记录，每个 IP 只有一条记录
+type IpAllocation struct {
+	BaseModel
+	IpAddress   string     `json:"ip_address" gorm:"size:60;uniqueIndex;comment:IP地址;not null"`
+	OwnerType   string     `json:"owner_type" gorm:"size:20;comment:使用方类型;not null;default:''"`
+	OwnerID     int64      `json:"owner_id" gorm:"comment:使用方ID;not null;default:0"`
+	OwnerName   string     `json:"owner_name" gorm:"size:60;comment:使用方名称;not null;default:''"`
+	InstallID   int64      `json:"install_id" gorm:"index;comment:安装ID;not null;default:0"`
+	State       string     `json:"state" gorm:"size:20;comment:状态;not null;default:''"`
+	AllocatedAt *time.Time `json:"allocated_at" gorm:"comment:分配时间"`
+	ReleasedAt  *time.Time `json:"released_at" gorm:"comment:释放时间"`
+}
+
+func (*IpAllocation) TableName() string {
+	return TableName("ip_allocations")
+}
+
+// Active IP 是否处于占用状态
+func (a *IpAllocation) Active() bool {
+	return a.State == IPStateReserved || a.State == IPStateAllocated
+}
@@ -0,0 +1,18 @@
+package model
+
+// NginxLocationVersion 插件Nginx location配置的历史版本，Active 为当前使用的版本
+type NginxLocationVersion struct {
+	BaseModel
+	AppInstalledId int64  `json:"app_installed_id" gorm:"comment:安装ID;not null;index"`
+	Key            string `json:"key" gorm:"size:60;comment:插件Key;not null;default:''"`
+	Version        int    `json:"version" gorm:"comment:版本号;not null;default:0"`
+	Template       string `json:"template" gorm:"type:text;comment:模板"`
+	Content        string `json:"content" gorm:"type:text;comment:渲染后的配置"`
+	Port           int    `json:"port" gorm:"comment:端口;not null;default:0"`
+	Active         bool   `json:"active" gorm:"comment:是否为当前使用的版本;not null;default:false"`
+	Remark         string `json:"remark" gorm:"comment:备注;default:''"`
+}
+
+func (*NginxLocationVersion) TableName() string {
+	return TableName("nginx_location_versions")
+}
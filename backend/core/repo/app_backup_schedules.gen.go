@@ -0,0 +1,416 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newAppBackupSchedule(db *gorm.DB, opts ...gen.DOOption) appBackupSchedule {
+	_appBackupSchedule := appBackupSchedule{}
+
+	_appBackupSchedule.appBackupScheduleDo.UseDB(db, opts...)
+	_appBackupSchedule.appBackupScheduleDo.UseModel(&model.AppBackupSchedule{})
+
+	tableName := _appBackupSchedule.appBackupScheduleDo.TableName()
+	_appBackupSchedule.ALL = field.NewAsterisk(tableName)
+	_appBackupSchedule.ID = field.NewInt64(tableName, "id")
+	_appBackupSchedule.CreatedAt = field.NewTime(tableName, "created_at")
+	_appBackupSchedule.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_appBackupSchedule.AppInstalledId = field.NewInt64(tableName, "app_installed_id")
+	_appBackupSchedule.Cron = field.NewString(tableName, "cron")
+	_appBackupSchedule.Retention = field.NewInt(tableName, "retention")
+	_appBackupSchedule.Enabled = field.NewBool(tableName, "enabled")
+	_appBackupSchedule.LastRunAt = field.NewTime(tableName, "last_run_at")
+	_appBackupSchedule.NextRunAt = field.NewTime(tableName, "next_run_at")
+	_appBackupSchedule.LastResult = field.NewString(tableName, "last_result")
+
+	_appBackupSchedule.fillFieldMap()
+
+	return _appBackupSchedule
+}
+
+type appBackupSchedule struct {
+	appBackupScheduleDo
+
+	ALL            field.Asterisk
+	ID             field.Int64
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	AppInstalledId field.Int64
+	Cron           field.String
+	Retention      field.Int
+	Enabled        field.Bool
+	LastRunAt      field.Time
+	NextRunAt      field.Time
+	LastResult     field.String
+
+	fieldMap map[string]field.Expr
+}
+
+func (a appBackupSchedule) Table(newTableName string) *appBackupSchedule {
+	a.appBackupScheduleDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a appBackupSchedule) As(alias string) *appBackupSchedule {
+	a.appBackupScheduleDo.DO = *(a.appBackupScheduleDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *appBackupSchedule) updateTableName(table string) *appBackupSchedule {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt64(table, "id")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.AppInstalledId = field.NewInt64(table, "app_installed_id")
+	a.Cron = field.NewString(table, "cron")
+	a.Retention = field.NewInt(table, "retention")
+	a.Enabled = field.NewBool(table, "enabled")
+	a.LastRunAt = field.NewTime(table, "last_run_at")
+	a.NextRunAt = field.NewTime(table, "next_run_at")
+	a.LastResult = field.NewString(table, "last_result")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *appBackupSchedule) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *appBackupSchedule) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 10)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["app_installed_id"] = a.AppInstalledId
+	a.fieldMap["cron"] = a.Cron
+	a.fieldMap["retention"] = a.Retention
+	a.fieldMap["enabled"] = a.Enabled
+	a.fieldMap["last_run_at"] = a.LastRunAt
+	a.fieldMap["next_run_at"] = a.NextRunAt
+	a.fieldMap["last_result"] = a.LastResult
+}
+
+func (a appBackupSchedule) clone(db *gorm.DB) appBackupSchedule {
+	a.appBackupScheduleDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a appBackupSchedule) replaceDB(db *gorm.DB) appBackupSchedule {
+	a.appBackupScheduleDo.ReplaceDB(db)
+	return a
+}
+
+type appBackupScheduleDo struct{ gen.DO }
+
+type IAppBackupScheduleDo interface {
+	gen.SubQuery
+	Debug() IAppBackupScheduleDo
+	WithContext(ctx context.Context) IAppBackupScheduleDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAppBackupScheduleDo
+	WriteDB() IAppBackupScheduleDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAppBackupScheduleDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAppBackupScheduleDo
+	Not(conds ...gen.Condition) IAppBackupScheduleDo
+	Or(conds ...gen.Condition) IAppBackupScheduleDo
+	Select(conds ...field.Expr) IAppBackupScheduleDo
+	Where(conds ...gen.Condition) IAppBackupScheduleDo
+	Order(conds ...field.Expr) IAppBackupScheduleDo
+	Distinct(cols ...field.Expr) IAppBackupScheduleDo
+	Omit(cols ...field.Expr) IAppBackupScheduleDo
+	Join(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo
+	Group(cols ...field.Expr) IAppBackupScheduleDo
+	Having(conds ...gen.Condition) IAppBackupScheduleDo
+	Limit(limit int) IAppBackupScheduleDo
+	Offset(offset int) IAppBackupScheduleDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAppBackupScheduleDo
+	Unscoped() IAppBackupScheduleDo
+	Create(values ...*model.AppBackupSchedule) error
+	CreateInBatches(values []*model.AppBackupSchedule, batchSize int) error
+	Save(values ...*model.AppBackupSchedule) error
+	First() (*model.AppBackupSchedule, error)
+	Take() (*model.AppBackupSchedule, error)
+	Last() (*model.AppBackupSchedule, error)
+	Find() ([]*model.AppBackupSchedule, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppBackupSchedule, err error)
+	FindInBatches(result *[]*model.AppBackupSchedule, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AppBackupSchedule) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAppBackupScheduleDo
+	Assign(attrs ...field.AssignExpr) IAppBackupScheduleDo
+	Joins(fields ...field.RelationField) IAppBackupScheduleDo
+	Preload(fields ...field.RelationField) IAppBackupScheduleDo
+	FirstOrInit() (*model.AppBackupSchedule, error)
+	FirstOrCreate() (*model.AppBackupSchedule, error)
+	FindByPage(offset int, limit int) (result []*model.AppBackupSchedule, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAppBackupScheduleDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a appBackupScheduleDo) Debug() IAppBackupScheduleDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a appBackupScheduleDo) WithContext(ctx context.Context) IAppBackupScheduleDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a appBackupScheduleDo) ReadDB() IAppBackupScheduleDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a appBackupScheduleDo) WriteDB() IAppBackupScheduleDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a appBackupScheduleDo) Session(config *gorm.Session) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a appBackupScheduleDo) Clauses(conds ...clause.Expression) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a appBackupScheduleDo) Returning(value interface{}, columns ...string) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a appBackupScheduleDo) Not(conds ...gen.Condition) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a appBackupScheduleDo) Or(conds ...gen.Condition) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a appBackupScheduleDo) Select(conds ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a appBackupScheduleDo) Where(conds ...gen.Condition) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a appBackupScheduleDo) Order(conds ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a appBackupScheduleDo) Distinct(cols ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a appBackupScheduleDo) Omit(cols ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a appBackupScheduleDo) Join(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a appBackupScheduleDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a appBackupScheduleDo) RightJoin(table schema.Tabler, on ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a appBackupScheduleDo) Group(cols ...field.Expr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a appBackupScheduleDo) Having(conds ...gen.Condition) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a appBackupScheduleDo) Limit(limit int) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a appBackupScheduleDo) Offset(offset int) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a appBackupScheduleDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a appBackupScheduleDo) Unscoped() IAppBackupScheduleDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a appBackupScheduleDo) Create(values ...*model.AppBackupSchedule) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a appBackupScheduleDo) CreateInBatches(values []*model.AppBackupSchedule, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a appBackupScheduleDo) Save(values ...*model.AppBackupSchedule) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a appBackupScheduleDo) First() (*model.AppBackupSchedule, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppBackupSchedule), nil
+	}
+}
+
+func (a appBackupScheduleDo) Take() (*model.AppBackupSchedule, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppBackupSchedule), nil
+	}
+}
+
+func (a appBackupScheduleDo) Last() (*model.AppBackupSchedule, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppBackupSchedule), nil
+	}
+}
+
+func (a appBackupScheduleDo) Find() ([]*model.AppBackupSchedule, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AppBackupSchedule), err
+}
+
+func (a appBackupScheduleDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppBackupSchedule, err error) {
+	buf := make([]*model.AppBackupSchedule, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a appBackupScheduleDo) FindInBatches(result *[]*model.AppBackupSchedule, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a appBackupScheduleDo) Attrs(attrs ...field.AssignExpr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a appBackupScheduleDo) Assign(attrs ...field.AssignExpr) IAppBackupScheduleDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a appBackupScheduleDo) Joins(fields ...field.RelationField) IAppBackupScheduleDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a appBackupScheduleDo) Preload(fields ...field.RelationField) IAppBackupScheduleDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a appBackupScheduleDo) FirstOrInit() (*model.AppBackupSchedule, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppBackupSchedule), nil
+	}
+}
+
+func (a appBackupScheduleDo) FirstOrCreate() (*model.AppBackupSchedule, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppBackupSchedule), nil
+	}
+}
+
+func (a appBackupScheduleDo) FindByPage(offset int, limit int) (result []*model.AppBackupSchedule, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a appBackupScheduleDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a appBackupScheduleDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a appBackupScheduleDo) Delete(models ...*model.AppBackupSchedule) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *appBackupScheduleDo) withDO(do gen.Dao) *appBackupScheduleDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
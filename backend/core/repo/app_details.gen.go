@@ -37,6 +37,7 @@ func newAppDetail(db *gorm.DB, opts ...gen.DOOption) appDetail {
 	_appDetail.Params = field.NewString(tableName, "params")
 	_appDetail.DockerCompose = field.NewString(tableName, "docker_compose")
 	_appDetail.NginxConfig = field.NewString(tableName, "nginx_config")
+	_appDetail.HealthCheck = field.NewField(tableName, "health_check")
 	_appDetail.Status = field.NewString(tableName, "status")
 
 	_appDetail.fillFieldMap()
@@ -58,6 +59,7 @@ type appDetail struct {
 	Params         field.String
 	DockerCompose  field.String
 	NginxConfig    field.String
+	HealthCheck    field.Field
 	Status         field.String
 
 	fieldMap map[string]field.Expr
@@ -85,6 +87,7 @@ func (a *appDetail) updateTableName(table string) *appDetail {
 	a.Params = field.NewString(table, "params")
 	a.DockerCompose = field.NewString(table, "docker_compose")
 	a.NginxConfig = field.NewString(table, "nginx_config")
+	a.HealthCheck = field.NewField(table, "health_check")
 	a.Status = field.NewString(table, "status")
 
 	a.fillFieldMap()
@@ -102,7 +105,7 @@ func (a *appDetail) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *appDetail) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 11)
+	a.fieldMap = make(map[string]field.Expr, 12)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
@@ -113,6 +116,7 @@ func (a *appDetail) fillFieldMap() {
 	a.fieldMap["params"] = a.Params
 	a.fieldMap["docker_compose"] = a.DockerCompose
 	a.fieldMap["nginx_config"] = a.NginxConfig
+	a.fieldMap["health_check"] = a.HealthCheck
 	a.fieldMap["status"] = a.Status
 }
 
@@ -32,6 +32,7 @@ func newAppInstalled(db *gorm.DB, opts ...gen.DOOption) appInstalled {
 	_appInstalled.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_appInstalled.Name = field.NewString(tableName, "name")
 	_appInstalled.IpAddress = field.NewString(tableName, "ip_address")
+	_appInstalled.Ip6Address = field.NewString(tableName, "ip6_address")
 	_appInstalled.AppID = field.NewInt64(tableName, "app_id")
 	_appInstalled.AppDetailID = field.NewInt64(tableName, "app_detail_id")
 	_appInstalled.Key = field.NewString(tableName, "key")
@@ -43,6 +44,10 @@ func newAppInstalled(db *gorm.DB, opts ...gen.DOOption) appInstalled {
 	_appInstalled.DockerCompose = field.NewString(tableName, "docker_compose")
 	_appInstalled.Message = field.NewString(tableName, "message")
 	_appInstalled.Location = field.NewString(tableName, "location")
+	_appInstalled.Domain = field.NewString(tableName, "domain")
+	_appInstalled.NginxOptions = field.NewString(tableName, "nginx_options")
+	_appInstalled.Health = field.NewString(tableName, "health")
+	_appInstalled.HealthLog = field.NewField(tableName, "health_log")
 	_appInstalled.Status = field.NewString(tableName, "status")
 
 	_appInstalled.fillFieldMap()
@@ -59,6 +64,7 @@ type appInstalled struct {
 	UpdatedAt     field.Time
 	Name          field.String
 	IpAddress     field.String
+	Ip6Address    field.String
 	AppID         field.Int64
 	AppDetailID   field.Int64
 	Key           field.String
@@ -70,6 +76,10 @@ type appInstalled struct {
 	DockerCompose field.String
 	Message       field.String
 	Location      field.String
+	Domain        field.String
+	NginxOptions  field.String
+	Health        field.String
+	HealthLog     field.Field
 	Status        field.String
 
 	fieldMap map[string]field.Expr
@@ -92,6 +102,7 @@ func (a *appInstalled) updateTableName(table string) *appInstalled {
 	a.UpdatedAt = field.NewTime(table, "updated_at")
 	a.Name = field.NewString(table, "name")
 	a.IpAddress = field.NewString(table, "ip_address")
+	a.Ip6Address = field.NewString(table, "ip6_address")
 	a.AppID = field.NewInt64(table, "app_id")
 	a.AppDetailID = field.NewInt64(table, "app_detail_id")
 	a.Key = field.NewString(table, "key")
@@ -103,6 +114,10 @@ func (a *appInstalled) updateTableName(table string) *appInstalled {
 	a.DockerCompose = field.NewString(table, "docker_compose")
 	a.Message = field.NewString(table, "message")
 	a.Location = field.NewString(table, "location")
+	a.Domain = field.NewString(table, "domain")
+	a.NginxOptions = field.NewString(table, "nginx_options")
+	a.Health = field.NewString(table, "health")
+	a.HealthLog = field.NewField(table, "health_log")
 	a.Status = field.NewString(table, "status")
 
 	a.fillFieldMap()
@@ -120,12 +135,13 @@ func (a *appInstalled) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (a *appInstalled) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 17)
+	a.fieldMap = make(map[string]field.Expr, 22)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
 	a.fieldMap["name"] = a.Name
 	a.fieldMap["ip_address"] = a.IpAddress
+	a.fieldMap["ip6_address"] = a.Ip6Address
 	a.fieldMap["app_id"] = a.AppID
 	a.fieldMap["app_detail_id"] = a.AppDetailID
 	a.fieldMap["key"] = a.Key
@@ -137,6 +153,10 @@ func (a *appInstalled) fillFieldMap() {
 	a.fieldMap["docker_compose"] = a.DockerCompose
 	a.fieldMap["message"] = a.Message
 	a.fieldMap["location"] = a.Location
+	a.fieldMap["domain"] = a.Domain
+	a.fieldMap["nginx_options"] = a.NginxOptions
+	a.fieldMap["health"] = a.Health
+	a.fieldMap["health_log"] = a.HealthLog
 	a.fieldMap["status"] = a.Status
 }
 
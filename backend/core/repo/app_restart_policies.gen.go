@@ -0,0 +1,420 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newAppRestartPolicy(db *gorm.DB, opts ...gen.DOOption) appRestartPolicy {
+	_appRestartPolicy := appRestartPolicy{}
+
+	_appRestartPolicy.appRestartPolicyDo.UseDB(db, opts...)
+	_appRestartPolicy.appRestartPolicyDo.UseModel(&model.AppRestartPolicy{})
+
+	tableName := _appRestartPolicy.appRestartPolicyDo.TableName()
+	_appRestartPolicy.ALL = field.NewAsterisk(tableName)
+	_appRestartPolicy.ID = field.NewInt64(tableName, "id")
+	_appRestartPolicy.CreatedAt = field.NewTime(tableName, "created_at")
+	_appRestartPolicy.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_appRestartPolicy.AppInstalledId = field.NewInt64(tableName, "app_installed_id")
+	_appRestartPolicy.Enabled = field.NewBool(tableName, "enabled")
+	_appRestartPolicy.MaxRestarts = field.NewInt(tableName, "max_restarts")
+	_appRestartPolicy.Window = field.NewInt(tableName, "window")
+	_appRestartPolicy.Backoff = field.NewInt(tableName, "backoff")
+	_appRestartPolicy.MaxBackoff = field.NewInt(tableName, "max_backoff")
+	_appRestartPolicy.LastRestartAt = field.NewTime(tableName, "last_restart_at")
+	_appRestartPolicy.GaveUpAt = field.NewTime(tableName, "gave_up_at")
+
+	_appRestartPolicy.fillFieldMap()
+
+	return _appRestartPolicy
+}
+
+type appRestartPolicy struct {
+	appRestartPolicyDo
+
+	ALL            field.Asterisk
+	ID             field.Int64
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	AppInstalledId field.Int64
+	Enabled        field.Bool
+	MaxRestarts    field.Int
+	Window         field.Int
+	Backoff        field.Int
+	MaxBackoff     field.Int
+	LastRestartAt  field.Time
+	GaveUpAt       field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (a appRestartPolicy) Table(newTableName string) *appRestartPolicy {
+	a.appRestartPolicyDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a appRestartPolicy) As(alias string) *appRestartPolicy {
+	a.appRestartPolicyDo.DO = *(a.appRestartPolicyDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *appRestartPolicy) updateTableName(table string) *appRestartPolicy {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt64(table, "id")
+	a.CreatedAt = field.NewTime(table, "created_at")
+	a.UpdatedAt = field.NewTime(table, "updated_at")
+	a.AppInstalledId = field.NewInt64(table, "app_installed_id")
+	a.Enabled = field.NewBool(table, "enabled")
+	a.MaxRestarts = field.NewInt(table, "max_restarts")
+	a.Window = field.NewInt(table, "window")
+	a.Backoff = field.NewInt(table, "backoff")
+	a.MaxBackoff = field.NewInt(table, "max_backoff")
+	a.LastRestartAt = field.NewTime(table, "last_restart_at")
+	a.GaveUpAt = field.NewTime(table, "gave_up_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *appRestartPolicy) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *appRestartPolicy) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 11)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["created_at"] = a.CreatedAt
+	a.fieldMap["updated_at"] = a.UpdatedAt
+	a.fieldMap["app_installed_id"] = a.AppInstalledId
+	a.fieldMap["enabled"] = a.Enabled
+	a.fieldMap["max_restarts"] = a.MaxRestarts
+	a.fieldMap["window"] = a.Window
+	a.fieldMap["backoff"] = a.Backoff
+	a.fieldMap["max_backoff"] = a.MaxBackoff
+	a.fieldMap["last_restart_at"] = a.LastRestartAt
+	a.fieldMap["gave_up_at"] = a.GaveUpAt
+}
+
+func (a appRestartPolicy) clone(db *gorm.DB) appRestartPolicy {
+	a.appRestartPolicyDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a appRestartPolicy) replaceDB(db *gorm.DB) appRestartPolicy {
+	a.appRestartPolicyDo.ReplaceDB(db)
+	return a
+}
+
+type appRestartPolicyDo struct{ gen.DO }
+
+type IAppRestartPolicyDo interface {
+	gen.SubQuery
+	Debug() IAppRestartPolicyDo
+	WithContext(ctx context.Context) IAppRestartPolicyDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAppRestartPolicyDo
+	WriteDB() IAppRestartPolicyDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAppRestartPolicyDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAppRestartPolicyDo
+	Not(conds ...gen.Condition) IAppRestartPolicyDo
+	Or(conds ...gen.Condition) IAppRestartPolicyDo
+	Select(conds ...field.Expr) IAppRestartPolicyDo
+	Where(conds ...gen.Condition) IAppRestartPolicyDo
+	Order(conds ...field.Expr) IAppRestartPolicyDo
+	Distinct(cols ...field.Expr) IAppRestartPolicyDo
+	Omit(cols ...field.Expr) IAppRestartPolicyDo
+	Join(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo
+	Group(cols ...field.Expr) IAppRestartPolicyDo
+	Having(conds ...gen.Condition) IAppRestartPolicyDo
+	Limit(limit int) IAppRestartPolicyDo
+	Offset(offset int) IAppRestartPolicyDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAppRestartPolicyDo
+	Unscoped() IAppRestartPolicyDo
+	Create(values ...*model.AppRestartPolicy) error
+	CreateInBatches(values []*model.AppRestartPolicy, batchSize int) error
+	Save(values ...*model.AppRestartPolicy) error
+	First() (*model.AppRestartPolicy, error)
+	Take() (*model.AppRestartPolicy, error)
+	Last() (*model.AppRestartPolicy, error)
+	Find() ([]*model.AppRestartPolicy, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppRestartPolicy, err error)
+	FindInBatches(result *[]*model.AppRestartPolicy, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AppRestartPolicy) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAppRestartPolicyDo
+	Assign(attrs ...field.AssignExpr) IAppRestartPolicyDo
+	Joins(fields ...field.RelationField) IAppRestartPolicyDo
+	Preload(fields ...field.RelationField) IAppRestartPolicyDo
+	FirstOrInit() (*model.AppRestartPolicy, error)
+	FirstOrCreate() (*model.AppRestartPolicy, error)
+	FindByPage(offset int, limit int) (result []*model.AppRestartPolicy, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAppRestartPolicyDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a appRestartPolicyDo) Debug() IAppRestartPolicyDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a appRestartPolicyDo) WithContext(ctx context.Context) IAppRestartPolicyDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a appRestartPolicyDo) ReadDB() IAppRestartPolicyDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a appRestartPolicyDo) WriteDB() IAppRestartPolicyDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a appRestartPolicyDo) Session(config *gorm.Session) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a appRestartPolicyDo) Clauses(conds ...clause.Expression) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a appRestartPolicyDo) Returning(value interface{}, columns ...string) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a appRestartPolicyDo) Not(conds ...gen.Condition) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a appRestartPolicyDo) Or(conds ...gen.Condition) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a appRestartPolicyDo) Select(conds ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a appRestartPolicyDo) Where(conds ...gen.Condition) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a appRestartPolicyDo) Order(conds ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a appRestartPolicyDo) Distinct(cols ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a appRestartPolicyDo) Omit(cols ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a appRestartPolicyDo) Join(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a appRestartPolicyDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a appRestartPolicyDo) RightJoin(table schema.Tabler, on ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a appRestartPolicyDo) Group(cols ...field.Expr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a appRestartPolicyDo) Having(conds ...gen.Condition) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a appRestartPolicyDo) Limit(limit int) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a appRestartPolicyDo) Offset(offset int) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a appRestartPolicyDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a appRestartPolicyDo) Unscoped() IAppRestartPolicyDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a appRestartPolicyDo) Create(values ...*model.AppRestartPolicy) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a appRestartPolicyDo) CreateInBatches(values []*model.AppRestartPolicy, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a appRestartPolicyDo) Save(values ...*model.AppRestartPolicy) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a appRestartPolicyDo) First() (*model.AppRestartPolicy, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppRestartPolicy), nil
+	}
+}
+
+func (a appRestartPolicyDo) Take() (*model.AppRestartPolicy, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppRestartPolicy), nil
+	}
+}
+
+func (a appRestartPolicyDo) Last() (*model.AppRestartPolicy, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppRestartPolicy), nil
+	}
+}
+
+func (a appRestartPolicyDo) Find() ([]*model.AppRestartPolicy, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AppRestartPolicy), err
+}
+
+func (a appRestartPolicyDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppRestartPolicy, err error) {
+	buf := make([]*model.AppRestartPolicy, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a appRestartPolicyDo) FindInBatches(result *[]*model.AppRestartPolicy, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a appRestartPolicyDo) Attrs(attrs ...field.AssignExpr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a appRestartPolicyDo) Assign(attrs ...field.AssignExpr) IAppRestartPolicyDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a appRestartPolicyDo) Joins(fields ...field.RelationField) IAppRestartPolicyDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a appRestartPolicyDo) Preload(fields ...field.RelationField) IAppRestartPolicyDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a appRestartPolicyDo) FirstOrInit() (*model.AppRestartPolicy, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppRestartPolicy), nil
+	}
+}
+
+func (a appRestartPolicyDo) FirstOrCreate() (*model.AppRestartPolicy, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppRestartPolicy), nil
+	}
+}
+
+func (a appRestartPolicyDo) FindByPage(offset int, limit int) (result []*model.AppRestartPolicy, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a appRestartPolicyDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a appRestartPolicyDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a appRestartPolicyDo) Delete(models ...*model.AppRestartPolicy) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *appRestartPolicyDo) withDO(do gen.Dao) *appRestartPolicyDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
@@ -0,0 +1,416 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newAppStatusTransition(db *gorm.DB, opts ...gen.DOOption) appStatusTransition {
+	_appStatusTransition := appStatusTransition{}
+
+	_appStatusTransition.appStatusTransitionDo.UseDB(db, opts...)
+	_appStatusTransition.appStatusTransitionDo.UseModel(&model.AppStatusTransition{})
+
+	tableName := _appStatusTransition.appStatusTransitionDo.TableName()
+	_appStatusTransition.ALL = field.NewAsterisk(tableName)
+	_appStatusTransition.ID = field.NewInt64(tableName, "id")
+	_appStatusTransition.InstallID = field.NewInt64(tableName, "install_id")
+	_appStatusTransition.Kind = field.NewString(tableName, "kind")
+	_appStatusTransition.ContainerName = field.NewString(tableName, "container_name")
+	_appStatusTransition.ServiceName = field.NewString(tableName, "service_name")
+	_appStatusTransition.From = field.NewString(tableName, "from_status")
+	_appStatusTransition.To = field.NewString(tableName, "to_status")
+	_appStatusTransition.Message = field.NewString(tableName, "message")
+	_appStatusTransition.ExitCode = field.NewInt(tableName, "exit_code")
+	_appStatusTransition.CreatedAt = field.NewTime(tableName, "created_at")
+
+	_appStatusTransition.fillFieldMap()
+
+	return _appStatusTransition
+}
+
+type appStatusTransition struct {
+	appStatusTransitionDo
+
+	ALL           field.Asterisk
+	ID            field.Int64
+	InstallID     field.Int64
+	Kind          field.String
+	ContainerName field.String
+	ServiceName   field.String
+	From          field.String
+	To            field.String
+	Message       field.String
+	ExitCode      field.Int
+	CreatedAt     field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (a appStatusTransition) Table(newTableName string) *appStatusTransition {
+	a.appStatusTransitionDo.UseTable(newTableName)
+	return a.updateTableName(newTableName)
+}
+
+func (a appStatusTransition) As(alias string) *appStatusTransition {
+	a.appStatusTransitionDo.DO = *(a.appStatusTransitionDo.As(alias).(*gen.DO))
+	return a.updateTableName(alias)
+}
+
+func (a *appStatusTransition) updateTableName(table string) *appStatusTransition {
+	a.ALL = field.NewAsterisk(table)
+	a.ID = field.NewInt64(table, "id")
+	a.InstallID = field.NewInt64(table, "install_id")
+	a.Kind = field.NewString(table, "kind")
+	a.ContainerName = field.NewString(table, "container_name")
+	a.ServiceName = field.NewString(table, "service_name")
+	a.From = field.NewString(table, "from_status")
+	a.To = field.NewString(table, "to_status")
+	a.Message = field.NewString(table, "message")
+	a.ExitCode = field.NewInt(table, "exit_code")
+	a.CreatedAt = field.NewTime(table, "created_at")
+
+	a.fillFieldMap()
+
+	return a
+}
+
+func (a *appStatusTransition) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := a.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (a *appStatusTransition) fillFieldMap() {
+	a.fieldMap = make(map[string]field.Expr, 10)
+	a.fieldMap["id"] = a.ID
+	a.fieldMap["install_id"] = a.InstallID
+	a.fieldMap["kind"] = a.Kind
+	a.fieldMap["container_name"] = a.ContainerName
+	a.fieldMap["service_name"] = a.ServiceName
+	a.fieldMap["from_status"] = a.From
+	a.fieldMap["to_status"] = a.To
+	a.fieldMap["message"] = a.Message
+	a.fieldMap["exit_code"] = a.ExitCode
+	a.fieldMap["created_at"] = a.CreatedAt
+}
+
+func (a appStatusTransition) clone(db *gorm.DB) appStatusTransition {
+	a.appStatusTransitionDo.ReplaceConnPool(db.Statement.ConnPool)
+	return a
+}
+
+func (a appStatusTransition) replaceDB(db *gorm.DB) appStatusTransition {
+	a.appStatusTransitionDo.ReplaceDB(db)
+	return a
+}
+
+type appStatusTransitionDo struct{ gen.DO }
+
+type IAppStatusTransitionDo interface {
+	gen.SubQuery
+	Debug() IAppStatusTransitionDo
+	WithContext(ctx context.Context) IAppStatusTransitionDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IAppStatusTransitionDo
+	WriteDB() IAppStatusTransitionDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IAppStatusTransitionDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IAppStatusTransitionDo
+	Not(conds ...gen.Condition) IAppStatusTransitionDo
+	Or(conds ...gen.Condition) IAppStatusTransitionDo
+	Select(conds ...field.Expr) IAppStatusTransitionDo
+	Where(conds ...gen.Condition) IAppStatusTransitionDo
+	Order(conds ...field.Expr) IAppStatusTransitionDo
+	Distinct(cols ...field.Expr) IAppStatusTransitionDo
+	Omit(cols ...field.Expr) IAppStatusTransitionDo
+	Join(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo
+	Group(cols ...field.Expr) IAppStatusTransitionDo
+	Having(conds ...gen.Condition) IAppStatusTransitionDo
+	Limit(limit int) IAppStatusTransitionDo
+	Offset(offset int) IAppStatusTransitionDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IAppStatusTransitionDo
+	Unscoped() IAppStatusTransitionDo
+	Create(values ...*model.AppStatusTransition) error
+	CreateInBatches(values []*model.AppStatusTransition, batchSize int) error
+	Save(values ...*model.AppStatusTransition) error
+	First() (*model.AppStatusTransition, error)
+	Take() (*model.AppStatusTransition, error)
+	Last() (*model.AppStatusTransition, error)
+	Find() ([]*model.AppStatusTransition, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppStatusTransition, err error)
+	FindInBatches(result *[]*model.AppStatusTransition, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.AppStatusTransition) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IAppStatusTransitionDo
+	Assign(attrs ...field.AssignExpr) IAppStatusTransitionDo
+	Joins(fields ...field.RelationField) IAppStatusTransitionDo
+	Preload(fields ...field.RelationField) IAppStatusTransitionDo
+	FirstOrInit() (*model.AppStatusTransition, error)
+	FirstOrCreate() (*model.AppStatusTransition, error)
+	FindByPage(offset int, limit int) (result []*model.AppStatusTransition, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IAppStatusTransitionDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (a appStatusTransitionDo) Debug() IAppStatusTransitionDo {
+	return a.withDO(a.DO.Debug())
+}
+
+func (a appStatusTransitionDo) WithContext(ctx context.Context) IAppStatusTransitionDo {
+	return a.withDO(a.DO.WithContext(ctx))
+}
+
+func (a appStatusTransitionDo) ReadDB() IAppStatusTransitionDo {
+	return a.Clauses(dbresolver.Read)
+}
+
+func (a appStatusTransitionDo) WriteDB() IAppStatusTransitionDo {
+	return a.Clauses(dbresolver.Write)
+}
+
+func (a appStatusTransitionDo) Session(config *gorm.Session) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Session(config))
+}
+
+func (a appStatusTransitionDo) Clauses(conds ...clause.Expression) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Clauses(conds...))
+}
+
+func (a appStatusTransitionDo) Returning(value interface{}, columns ...string) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Returning(value, columns...))
+}
+
+func (a appStatusTransitionDo) Not(conds ...gen.Condition) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Not(conds...))
+}
+
+func (a appStatusTransitionDo) Or(conds ...gen.Condition) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Or(conds...))
+}
+
+func (a appStatusTransitionDo) Select(conds ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Select(conds...))
+}
+
+func (a appStatusTransitionDo) Where(conds ...gen.Condition) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Where(conds...))
+}
+
+func (a appStatusTransitionDo) Order(conds ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Order(conds...))
+}
+
+func (a appStatusTransitionDo) Distinct(cols ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Distinct(cols...))
+}
+
+func (a appStatusTransitionDo) Omit(cols ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Omit(cols...))
+}
+
+func (a appStatusTransitionDo) Join(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Join(table, on...))
+}
+
+func (a appStatusTransitionDo) LeftJoin(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.LeftJoin(table, on...))
+}
+
+func (a appStatusTransitionDo) RightJoin(table schema.Tabler, on ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.RightJoin(table, on...))
+}
+
+func (a appStatusTransitionDo) Group(cols ...field.Expr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Group(cols...))
+}
+
+func (a appStatusTransitionDo) Having(conds ...gen.Condition) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Having(conds...))
+}
+
+func (a appStatusTransitionDo) Limit(limit int) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Limit(limit))
+}
+
+func (a appStatusTransitionDo) Offset(offset int) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Offset(offset))
+}
+
+func (a appStatusTransitionDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Scopes(funcs...))
+}
+
+func (a appStatusTransitionDo) Unscoped() IAppStatusTransitionDo {
+	return a.withDO(a.DO.Unscoped())
+}
+
+func (a appStatusTransitionDo) Create(values ...*model.AppStatusTransition) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Create(values)
+}
+
+func (a appStatusTransitionDo) CreateInBatches(values []*model.AppStatusTransition, batchSize int) error {
+	return a.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (a appStatusTransitionDo) Save(values ...*model.AppStatusTransition) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return a.DO.Save(values)
+}
+
+func (a appStatusTransitionDo) First() (*model.AppStatusTransition, error) {
+	if result, err := a.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppStatusTransition), nil
+	}
+}
+
+func (a appStatusTransitionDo) Take() (*model.AppStatusTransition, error) {
+	if result, err := a.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppStatusTransition), nil
+	}
+}
+
+func (a appStatusTransitionDo) Last() (*model.AppStatusTransition, error) {
+	if result, err := a.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppStatusTransition), nil
+	}
+}
+
+func (a appStatusTransitionDo) Find() ([]*model.AppStatusTransition, error) {
+	result, err := a.DO.Find()
+	return result.([]*model.AppStatusTransition), err
+}
+
+func (a appStatusTransitionDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.AppStatusTransition, err error) {
+	buf := make([]*model.AppStatusTransition, 0, batchSize)
+	err = a.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (a appStatusTransitionDo) FindInBatches(result *[]*model.AppStatusTransition, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return a.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (a appStatusTransitionDo) Attrs(attrs ...field.AssignExpr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Attrs(attrs...))
+}
+
+func (a appStatusTransitionDo) Assign(attrs ...field.AssignExpr) IAppStatusTransitionDo {
+	return a.withDO(a.DO.Assign(attrs...))
+}
+
+func (a appStatusTransitionDo) Joins(fields ...field.RelationField) IAppStatusTransitionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Joins(_f))
+	}
+	return &a
+}
+
+func (a appStatusTransitionDo) Preload(fields ...field.RelationField) IAppStatusTransitionDo {
+	for _, _f := range fields {
+		a = *a.withDO(a.DO.Preload(_f))
+	}
+	return &a
+}
+
+func (a appStatusTransitionDo) FirstOrInit() (*model.AppStatusTransition, error) {
+	if result, err := a.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppStatusTransition), nil
+	}
+}
+
+func (a appStatusTransitionDo) FirstOrCreate() (*model.AppStatusTransition, error) {
+	if result, err := a.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.AppStatusTransition), nil
+	}
+}
+
+func (a appStatusTransitionDo) FindByPage(offset int, limit int) (result []*model.AppStatusTransition, count int64, err error) {
+	result, err = a.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = a.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (a appStatusTransitionDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = a.Count()
+	if err != nil {
+		return
+	}
+
+	err = a.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (a appStatusTransitionDo) Scan(result interface{}) (err error) {
+	return a.DO.Scan(result)
+}
+
+func (a appStatusTransitionDo) Delete(models ...*model.AppStatusTransition) (result gen.ResultInfo, err error) {
+	return a.DO.Delete(models)
+}
+
+func (a *appStatusTransitionDo) withDO(do gen.Dao) *appStatusTransitionDo {
+	a.DO = *do.(*gen.DO)
+	return a
+}
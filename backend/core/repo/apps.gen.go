@@ -39,6 +39,8 @@ func newApp(db *gorm.DB, opts ...gen.DOOption) app {
 	_app.DependsVersion = field.NewString(tableName, "depends_version")
 	_app.Sort = field.NewInt(tableName, "sort")
 	_app.Status = field.NewString(tableName, "status")
+	_app.NameI18n = field.NewField(tableName, "name_i18n")
+	_app.DescriptionI18n = field.NewField(tableName, "description_i18n")
 
 	_app.fillFieldMap()
 
@@ -48,19 +50,21 @@ func newApp(db *gorm.DB, opts ...gen.DOOption) app {
 type app struct {
 	appDo
 
-	ALL            field.Asterisk
-	ID             field.Int64
-	CreatedAt      field.Time
-	UpdatedAt      field.Time
-	Name           field.String
-	Key            field.String
-	Icon           field.String
-	Description    field.String
-	Github         field.String
-	Class          field.String
-	DependsVersion field.String
-	Sort           field.Int
-	Status         field.String
+	ALL             field.Asterisk
+	ID              field.Int64
+	CreatedAt       field.Time
+	UpdatedAt       field.Time
+	Name            field.String
+	Key             field.String
+	Icon            field.String
+	Description     field.String
+	Github          field.String
+	Class           field.String
+	DependsVersion  field.String
+	Sort            field.Int
+	Status          field.String
+	NameI18n        field.Field
+	DescriptionI18n field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -89,6 +93,8 @@ func (a *app) updateTableName(table string) *app {
 	a.DependsVersion = field.NewString(table, "depends_version")
 	a.Sort = field.NewInt(table, "sort")
 	a.Status = field.NewString(table, "status")
+	a.NameI18n = field.NewField(table, "name_i18n")
+	a.DescriptionI18n = field.NewField(table, "description_i18n")
 
 	a.fillFieldMap()
 
@@ -105,7 +111,7 @@ func (a *app) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *app) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 12)
+	a.fieldMap = make(map[string]field.Expr, 14)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
@@ -118,6 +124,8 @@ func (a *app) fillFieldMap() {
 	a.fieldMap["depends_version"] = a.DependsVersion
 	a.fieldMap["sort"] = a.Sort
 	a.fieldMap["status"] = a.Status
+	a.fieldMap["name_i18n"] = a.NameI18n
+	a.fieldMap["description_i18n"] = a.DescriptionI18n
 }
 
 func (a app) clone(db *gorm.DB) app {
@@ -0,0 +1,432 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newCertificate(db *gorm.DB, opts ...gen.DOOption) certificate {
+	_certificate := certificate{}
+
+	_certificate.certificateDo.UseDB(db, opts...)
+	_certificate.certificateDo.UseModel(&model.Certificate{})
+
+	tableName := _certificate.certificateDo.TableName()
+	_certificate.ALL = field.NewAsterisk(tableName)
+	_certificate.ID = field.NewInt64(tableName, "id")
+	_certificate.CreatedAt = field.NewTime(tableName, "created_at")
+	_certificate.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_certificate.Domain = field.NewString(tableName, "domain")
+	_certificate.Source = field.NewString(tableName, "source")
+	_certificate.Status = field.NewString(tableName, "status")
+	_certificate.Cert = field.NewString(tableName, "cert")
+	_certificate.Key = field.NewString(tableName, "key")
+	_certificate.Issuer = field.NewString(tableName, "issuer")
+	_certificate.NotBefore = field.NewTime(tableName, "not_before")
+	_certificate.NotAfter = field.NewTime(tableName, "not_after")
+	_certificate.AutoRenew = field.NewBool(tableName, "auto_renew")
+	_certificate.RenewedAt = field.NewTime(tableName, "renewed_at")
+	_certificate.Message = field.NewString(tableName, "message")
+
+	_certificate.fillFieldMap()
+
+	return _certificate
+}
+
+type certificate struct {
+	certificateDo
+
+	ALL       field.Asterisk
+	ID        field.Int64
+	CreatedAt field.Time
+	UpdatedAt field.Time
+	Domain    field.String
+	Source    field.String
+	Status    field.String
+	Cert      field.String
+	Key       field.String
+	Issuer    field.String
+	NotBefore field.Time
+	NotAfter  field.Time
+	AutoRenew field.Bool
+	RenewedAt field.Time
+	Message   field.String
+
+	fieldMap map[string]field.Expr
+}
+
+func (c certificate) Table(newTableName string) *certificate {
+	c.certificateDo.UseTable(newTableName)
+	return c.updateTableName(newTableName)
+}
+
+func (c certificate) As(alias string) *certificate {
+	c.certificateDo.DO = *(c.certificateDo.As(alias).(*gen.DO))
+	return c.updateTableName(alias)
+}
+
+func (c *certificate) updateTableName(table string) *certificate {
+	c.ALL = field.NewAsterisk(table)
+	c.ID = field.NewInt64(table, "id")
+	c.CreatedAt = field.NewTime(table, "created_at")
+	c.UpdatedAt = field.NewTime(table, "updated_at")
+	c.Domain = field.NewString(table, "domain")
+	c.Source = field.NewString(table, "source")
+	c.Status = field.NewString(table, "status")
+	c.Cert = field.NewString(table, "cert")
+	c.Key = field.NewString(table, "key")
+	c.Issuer = field.NewString(table, "issuer")
+	c.NotBefore = field.NewTime(table, "not_before")
+	c.NotAfter = field.NewTime(table, "not_after")
+	c.AutoRenew = field.NewBool(table, "auto_renew")
+	c.RenewedAt = field.NewTime(table, "renewed_at")
+	c.Message = field.NewString(table, "message")
+
+	c.fillFieldMap()
+
+	return c
+}
+
+func (c *certificate) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := c.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (c *certificate) fillFieldMap() {
+	c.fieldMap = make(map[string]field.Expr, 14)
+	c.fieldMap["id"] = c.ID
+	c.fieldMap["created_at"] = c.CreatedAt
+	c.fieldMap["updated_at"] = c.UpdatedAt
+	c.fieldMap["domain"] = c.Domain
+	c.fieldMap["source"] = c.Source
+	c.fieldMap["status"] = c.Status
+	c.fieldMap["cert"] = c.Cert
+	c.fieldMap["key"] = c.Key
+	c.fieldMap["issuer"] = c.Issuer
+	c.fieldMap["not_before"] = c.NotBefore
+	c.fieldMap["not_after"] = c.NotAfter
+	c.fieldMap["auto_renew"] = c.AutoRenew
+	c.fieldMap["renewed_at"] = c.RenewedAt
+	c.fieldMap["message"] = c.Message
+}
+
+func (c certificate) clone(db *gorm.DB) certificate {
+	c.certificateDo.ReplaceConnPool(db.Statement.ConnPool)
+	return c
+}
+
+func (c certificate) replaceDB(db *gorm.DB) certificate {
+	c.certificateDo.ReplaceDB(db)
+	return c
+}
+
+type certificateDo struct{ gen.DO }
+
+type ICertificateDo interface {
+	gen.SubQuery
+	Debug() ICertificateDo
+	WithContext(ctx context.Context) ICertificateDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() ICertificateDo
+	WriteDB() ICertificateDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) ICertificateDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) ICertificateDo
+	Not(conds ...gen.Condition) ICertificateDo
+	Or(conds ...gen.Condition) ICertificateDo
+	Select(conds ...field.Expr) ICertificateDo
+	Where(conds ...gen.Condition) ICertificateDo
+	Order(conds ...field.Expr) ICertificateDo
+	Distinct(cols ...field.Expr) ICertificateDo
+	Omit(cols ...field.Expr) ICertificateDo
+	Join(table schema.Tabler, on ...field.Expr) ICertificateDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) ICertificateDo
+	RightJoin(table schema.Tabler, on ...field.Expr) ICertificateDo
+	Group(cols ...field.Expr) ICertificateDo
+	Having(conds ...gen.Condition) ICertificateDo
+	Limit(limit int) ICertificateDo
+	Offset(offset int) ICertificateDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) ICertificateDo
+	Unscoped() ICertificateDo
+	Create(values ...*model.Certificate) error
+	CreateInBatches(values []*model.Certificate, batchSize int) error
+	Save(values ...*model.Certificate) error
+	First() (*model.Certificate, error)
+	Take() (*model.Certificate, error)
+	Last() (*model.Certificate, error)
+	Find() ([]*model.Certificate, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.Certificate, err error)
+	FindInBatches(result *[]*model.Certificate, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.Certificate) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) ICertificateDo
+	Assign(attrs ...field.AssignExpr) ICertificateDo
+	Joins(fields ...field.RelationField) ICertificateDo
+	Preload(fields ...field.RelationField) ICertificateDo
+	FirstOrInit() (*model.Certificate, error)
+	FirstOrCreate() (*model.Certificate, error)
+	FindByPage(offset int, limit int) (result []*model.Certificate, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) ICertificateDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (c certificateDo) Debug() ICertificateDo {
+	return c.withDO(c.DO.Debug())
+}
+
+func (c certificateDo) WithContext(ctx context.Context) ICertificateDo {
+	return c.withDO(c.DO.WithContext(ctx))
+}
+
+func (c certificateDo) ReadDB() ICertificateDo {
+	return c.Clauses(dbresolver.Read)
+}
+
+func (c certificateDo) WriteDB() ICertificateDo {
+	return c.Clauses(dbresolver.Write)
+}
+
+func (c certificateDo) Session(config *gorm.Session) ICertificateDo {
+	return c.withDO(c.DO.Session(config))
+}
+
+func (c certificateDo) Clauses(conds ...clause.Expression) ICertificateDo {
+	return c.withDO(c.DO.Clauses(conds...))
+}
+
+func (c certificateDo) Returning(value interface{}, columns ...string) ICertificateDo {
+	return c.withDO(c.DO.Returning(value, columns...))
+}
+
+func (c certificateDo) Not(conds ...gen.Condition) ICertificateDo {
+	return c.withDO(c.DO.Not(conds...))
+}
+
+func (c certificateDo) Or(conds ...gen.Condition) ICertificateDo {
+	return c.withDO(c.DO.Or(conds...))
+}
+
+func (c certificateDo) Select(conds ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Select(conds...))
+}
+
+func (c certificateDo) Where(conds ...gen.Condition) ICertificateDo {
+	return c.withDO(c.DO.Where(conds...))
+}
+
+func (c certificateDo) Order(conds ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Order(conds...))
+}
+
+func (c certificateDo) Distinct(cols ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Distinct(cols...))
+}
+
+func (c certificateDo) Omit(cols ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Omit(cols...))
+}
+
+func (c certificateDo) Join(table schema.Tabler, on ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Join(table, on...))
+}
+
+func (c certificateDo) LeftJoin(table schema.Tabler, on ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.LeftJoin(table, on...))
+}
+
+func (c certificateDo) RightJoin(table schema.Tabler, on ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.RightJoin(table, on...))
+}
+
+func (c certificateDo) Group(cols ...field.Expr) ICertificateDo {
+	return c.withDO(c.DO.Group(cols...))
+}
+
+func (c certificateDo) Having(conds ...gen.Condition) ICertificateDo {
+	return c.withDO(c.DO.Having(conds...))
+}
+
+func (c certificateDo) Limit(limit int) ICertificateDo {
+	return c.withDO(c.DO.Limit(limit))
+}
+
+func (c certificateDo) Offset(offset int) ICertificateDo {
+	return c.withDO(c.DO.Offset(offset))
+}
+
+func (c certificateDo) Scopes(funcs ...func(gen.Dao) gen.Dao) ICertificateDo {
+	return c.withDO(c.DO.Scopes(funcs...))
+}
+
+func (c certificateDo) Unscoped() ICertificateDo {
+	return c.withDO(c.DO.Unscoped())
+}
+
+func (c certificateDo) Create(values ...*model.Certificate) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Create(values)
+}
+
+func (c certificateDo) CreateInBatches(values []*model.Certificate, batchSize int) error {
+	return c.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (c certificateDo) Save(values ...*model.Certificate) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return c.DO.Save(values)
+}
+
+func (c certificateDo) First() (*model.Certificate, error) {
+	if result, err := c.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.Certificate), nil
+	}
+}
+
+func (c certificateDo) Take() (*model.Certificate, error) {
+	if result, err := c.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.Certificate), nil
+	}
+}
+
+func (c certificateDo) Last() (*model.Certificate, error) {
+	if result, err := c.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.Certificate), nil
+	}
+}
+
+func (c certificateDo) Find() ([]*model.Certificate, error) {
+	result, err := c.DO.Find()
+	return result.([]*model.Certificate), err
+}
+
+func (c certificateDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.Certificate, err error) {
+	buf := make([]*model.Certificate, 0, batchSize)
+	err = c.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (c certificateDo) FindInBatches(result *[]*model.Certificate, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return c.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (c certificateDo) Attrs(attrs ...field.AssignExpr) ICertificateDo {
+	return c.withDO(c.DO.Attrs(attrs...))
+}
+
+func (c certificateDo) Assign(attrs ...field.AssignExpr) ICertificateDo {
+	return c.withDO(c.DO.Assign(attrs...))
+}
+
+func (c certificateDo) Joins(fields ...field.RelationField) ICertificateDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Joins(_f))
+	}
+	return &c
+}
+
+func (c certificateDo) Preload(fields ...field.RelationField) ICertificateDo {
+	for _, _f := range fields {
+		c = *c.withDO(c.DO.Preload(_f))
+	}
+	return &c
+}
+
+func (c certificateDo) FirstOrInit() (*model.Certificate, error) {
+	if result, err := c.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.Certificate), nil
+	}
+}
+
+func (c certificateDo) FirstOrCreate() (*model.Certificate, error) {
+	if result, err := c.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.Certificate), nil
+	}
+}
+
+func (c certificateDo) FindByPage(offset int, limit int) (result []*model.Certificate, count int64, err error) {
+	result, err = c.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = c.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (c certificateDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = c.Count()
+	if err != nil {
+		return
+	}
+
+	err = c.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (c certificateDo) Scan(result interface{}) (err error) {
+	return c.DO.Scan(result)
+}
+
+func (c certificateDo) Delete(models ...*model.Certificate) (result gen.ResultInfo, err error) {
+	return c.DO.Delete(models)
+}
+
+func (c *certificateDo) withDO(do gen.Dao) *certificateDo {
+	c.DO = *do.(*gen.DO)
+	return c
+}
@@ -16,64 +16,94 @@ import (
 )
 
 var (
-	Q                = new(Query)
-	App              *app
-	AppDetail        *appDetail
-	AppInstalled     *appInstalled
-	AppLog           *appLog
-	AppServiceStatus *appServiceStatus
-	AppTag           *appTag
-	Tag              *tag
+	Q                    = new(Query)
+	App                  *app
+	AppBackupSchedule    *appBackupSchedule
+	AppDetail            *appDetail
+	AppInstalled         *appInstalled
+	AppLog               *appLog
+	AppRestartPolicy     *appRestartPolicy
+	AppServiceStatus     *appServiceStatus
+	AppStatusTransition  *appStatusTransition
+	AppTag               *appTag
+	Certificate          *certificate
+	IpAllocation         *ipAllocation
+	NginxLocationVersion *nginxLocationVersion
+	Tag                  *tag
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	App = &Q.App
+	AppBackupSchedule = &Q.AppBackupSchedule
 	AppDetail = &Q.AppDetail
 	AppInstalled = &Q.AppInstalled
 	AppLog = &Q.AppLog
+	AppRestartPolicy = &Q.AppRestartPolicy
 	AppServiceStatus = &Q.AppServiceStatus
+	AppStatusTransition = &Q.AppStatusTransition
 	AppTag = &Q.AppTag
+	Certificate = &Q.Certificate
+	IpAllocation = &Q.IpAllocation
+	NginxLocationVersion = &Q.NginxLocationVersion
 	Tag = &Q.Tag
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:               db,
-		App:              newApp(db, opts...),
-		AppDetail:        newAppDetail(db, opts...),
-		AppInstalled:     newAppInstalled(db, opts...),
-		AppLog:           newAppLog(db, opts...),
-		AppServiceStatus: newAppServiceStatus(db, opts...),
-		AppTag:           newAppTag(db, opts...),
-		Tag:              newTag(db, opts...),
+		db:                   db,
+		App:                  newApp(db, opts...),
+		AppBackupSchedule:    newAppBackupSchedule(db, opts...),
+		AppDetail:            newAppDetail(db, opts...),
+		AppInstalled:         newAppInstalled(db, opts...),
+		AppLog:               newAppLog(db, opts...),
+		AppRestartPolicy:     newAppRestartPolicy(db, opts...),
+		AppServiceStatus:     newAppServiceStatus(db, opts...),
+		AppStatusTransition:  newAppStatusTransition(db, opts...),
+		AppTag:               newAppTag(db, opts...),
+		Certificate:          newCertificate(db, opts...),
+		IpAllocation:         newIpAllocation(db, opts...),
+		NginxLocationVersion: newNginxLocationVersion(db, opts...),
+		Tag:                  newTag(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	App              app
-	AppDetail        appDetail
-	AppInstalled     appInstalled
-	AppLog           appLog
-	AppServiceStatus appServiceStatus
-	AppTag           appTag
-	Tag              tag
+	App                  app
+	AppBackupSchedule    appBackupSchedule
+	AppDetail            appDetail
+	AppInstalled         appInstalled
+	AppLog               appLog
+	AppRestartPolicy     appRestartPolicy
+	AppServiceStatus     appServiceStatus
+	AppStatusTransition  appStatusTransition
+	AppTag               appTag
+	Certificate          certificate
+	IpAllocation         ipAllocation
+	NginxLocationVersion nginxLocationVersion
+	Tag                  tag
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		App:              q.App.clone(db),
-		AppDetail:        q.AppDetail.clone(db),
-		AppInstalled:     q.AppInstalled.clone(db),
-		AppLog:           q.AppLog.clone(db),
-		AppServiceStatus: q.AppServiceStatus.clone(db),
-		AppTag:           q.AppTag.clone(db),
-		Tag:              q.Tag.clone(db),
+		db:                   db,
+		App:                  q.App.clone(db),
+		AppBackupSchedule:    q.AppBackupSchedule.clone(db),
+		AppDetail:            q.AppDetail.clone(db),
+		AppInstalled:         q.AppInstalled.clone(db),
+		AppLog:               q.AppLog.clone(db),
+		AppRestartPolicy:     q.AppRestartPolicy.clone(db),
+		AppServiceStatus:     q.AppServiceStatus.clone(db),
+		AppStatusTransition:  q.AppStatusTransition.clone(db),
+		AppTag:               q.AppTag.clone(db),
+		Certificate:          q.Certificate.clone(db),
+		IpAllocation:         q.IpAllocation.clone(db),
+		NginxLocationVersion: q.NginxLocationVersion.clone(db),
+		Tag:                  q.Tag.clone(db),
 	}
 }
 
@@ -87,36 +117,54 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		App:              q.App.replaceDB(db),
-		AppDetail:        q.AppDetail.replaceDB(db),
-		AppInstalled:     q.AppInstalled.replaceDB(db),
-		AppLog:           q.AppLog.replaceDB(db),
-		AppServiceStatus: q.AppServiceStatus.replaceDB(db),
-		AppTag:           q.AppTag.replaceDB(db),
-		Tag:              q.Tag.replaceDB(db),
+		db:                   db,
+		App:                  q.App.replaceDB(db),
+		AppBackupSchedule:    q.AppBackupSchedule.replaceDB(db),
+		AppDetail:            q.AppDetail.replaceDB(db),
+		AppInstalled:         q.AppInstalled.replaceDB(db),
+		AppLog:               q.AppLog.replaceDB(db),
+		AppRestartPolicy:     q.AppRestartPolicy.replaceDB(db),
+		AppServiceStatus:     q.AppServiceStatus.replaceDB(db),
+		AppStatusTransition:  q.AppStatusTransition.replaceDB(db),
+		AppTag:               q.AppTag.replaceDB(db),
+		Certificate:          q.Certificate.replaceDB(db),
+		IpAllocation:         q.IpAllocation.replaceDB(db),
+		NginxLocationVersion: q.NginxLocationVersion.replaceDB(db),
+		Tag:                  q.Tag.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	App              IAppDo
-	AppDetail        IAppDetailDo
-	AppInstalled     IAppInstalledDo
-	AppLog           IAppLogDo
-	AppServiceStatus IAppServiceStatusDo
-	AppTag           IAppTagDo
-	Tag              ITagDo
+	App                  IAppDo
+	AppBackupSchedule    IAppBackupScheduleDo
+	AppDetail            IAppDetailDo
+	AppInstalled         IAppInstalledDo
+	AppLog               IAppLogDo
+	AppRestartPolicy     IAppRestartPolicyDo
+	AppServiceStatus     IAppServiceStatusDo
+	AppStatusTransition  IAppStatusTransitionDo
+	AppTag               IAppTagDo
+	Certificate          ICertificateDo
+	IpAllocation         IIpAllocationDo
+	NginxLocationVersion INginxLocationVersionDo
+	Tag                  ITagDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		App:              q.App.WithContext(ctx),
-		AppDetail:        q.AppDetail.WithContext(ctx),
-		AppInstalled:     q.AppInstalled.WithContext(ctx),
-		AppLog:           q.AppLog.WithContext(ctx),
-		AppServiceStatus: q.AppServiceStatus.WithContext(ctx),
-		AppTag:           q.AppTag.WithContext(ctx),
-		Tag:              q.Tag.WithContext(ctx),
+		App:                  q.App.WithContext(ctx),
+		AppBackupSchedule:    q.AppBackupSchedule.WithContext(ctx),
+		AppDetail:            q.AppDetail.WithContext(ctx),
+		AppInstalled:         q.AppInstalled.WithContext(ctx),
+		AppLog:               q.AppLog.WithContext(ctx),
+		AppRestartPolicy:     q.AppRestartPolicy.WithContext(ctx),
+		AppServiceStatus:     q.AppServiceStatus.WithContext(ctx),
+		AppStatusTransition:  q.AppStatusTransition.WithContext(ctx),
+		AppTag:               q.AppTag.WithContext(ctx),
+		Certificate:          q.Certificate.WithContext(ctx),
+		IpAllocation:         q.IpAllocation.WithContext(ctx),
+		NginxLocationVersion: q.NginxLocationVersion.WithContext(ctx),
+		Tag:                  q.Tag.WithContext(ctx),
 	}
 }
 
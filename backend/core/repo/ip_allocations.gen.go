@@ -0,0 +1,420 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newIpAllocation(db *gorm.DB, opts ...gen.DOOption) ipAllocation {
+	_ipAllocation := ipAllocation{}
+
+	_ipAllocation.ipAllocationDo.UseDB(db, opts...)
+	_ipAllocation.ipAllocationDo.UseModel(&model.IpAllocation{})
+
+	tableName := _ipAllocation.ipAllocationDo.TableName()
+	_ipAllocation.ALL = field.NewAsterisk(tableName)
+	_ipAllocation.ID = field.NewInt64(tableName, "id")
+	_ipAllocation.CreatedAt = field.NewTime(tableName, "created_at")
+	_ipAllocation.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_ipAllocation.IpAddress = field.NewString(tableName, "ip_address")
+	_ipAllocation.OwnerType = field.NewString(tableName, "owner_type")
+	_ipAllocation.OwnerID = field.NewInt64(tableName, "owner_id")
+	_ipAllocation.OwnerName = field.NewString(tableName, "owner_name")
+	_ipAllocation.InstallID = field.NewInt64(tableName, "install_id")
+	_ipAllocation.State = field.NewString(tableName, "state")
+	_ipAllocation.AllocatedAt = field.NewTime(tableName, "allocated_at")
+	_ipAllocation.ReleasedAt = field.NewTime(tableName, "released_at")
+
+	_ipAllocation.fillFieldMap()
+
+	return _ipAllocation
+}
+
+type ipAllocation struct {
+	ipAllocationDo
+
+	ALL         field.Asterisk
+	ID          field.Int64
+	CreatedAt   field.Time
+	UpdatedAt   field.Time
+	IpAddress   field.String
+	OwnerType   field.String
+	OwnerID     field.Int64
+	OwnerName   field.String
+	InstallID   field.Int64
+	State       field.String
+	AllocatedAt field.Time
+	ReleasedAt  field.Time
+
+	fieldMap map[string]field.Expr
+}
+
+func (i ipAllocation) Table(newTableName string) *ipAllocation {
+	i.ipAllocationDo.UseTable(newTableName)
+	return i.updateTableName(newTableName)
+}
+
+func (i ipAllocation) As(alias string) *ipAllocation {
+	i.ipAllocationDo.DO = *(i.ipAllocationDo.As(alias).(*gen.DO))
+	return i.updateTableName(alias)
+}
+
+func (i *ipAllocation) updateTableName(table string) *ipAllocation {
+	i.ALL = field.NewAsterisk(table)
+	i.ID = field.NewInt64(table, "id")
+	i.CreatedAt = field.NewTime(table, "created_at")
+	i.UpdatedAt = field.NewTime(table, "updated_at")
+	i.IpAddress = field.NewString(table, "ip_address")
+	i.OwnerType = field.NewString(table, "owner_type")
+	i.OwnerID = field.NewInt64(table, "owner_id")
+	i.OwnerName = field.NewString(table, "owner_name")
+	i.InstallID = field.NewInt64(table, "install_id")
+	i.State = field.NewString(table, "state")
+	i.AllocatedAt = field.NewTime(table, "allocated_at")
+	i.ReleasedAt = field.NewTime(table, "released_at")
+
+	i.fillFieldMap()
+
+	return i
+}
+
+func (i *ipAllocation) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := i.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (i *ipAllocation) fillFieldMap() {
+	i.fieldMap = make(map[string]field.Expr, 11)
+	i.fieldMap["id"] = i.ID
+	i.fieldMap["created_at"] = i.CreatedAt
+	i.fieldMap["updated_at"] = i.UpdatedAt
+	i.fieldMap["ip_address"] = i.IpAddress
+	i.fieldMap["owner_type"] = i.OwnerType
+	i.fieldMap["owner_id"] = i.OwnerID
+	i.fieldMap["owner_name"] = i.OwnerName
+	i.fieldMap["install_id"] = i.InstallID
+	i.fieldMap["state"] = i.State
+	i.fieldMap["allocated_at"] = i.AllocatedAt
+	i.fieldMap["released_at"] = i.ReleasedAt
+}
+
+func (i ipAllocation) clone(db *gorm.DB) ipAllocation {
+	i.ipAllocationDo.ReplaceConnPool(db.Statement.ConnPool)
+	return i
+}
+
+func (i ipAllocation) replaceDB(db *gorm.DB) ipAllocation {
+	i.ipAllocationDo.ReplaceDB(db)
+	return i
+}
+
+type ipAllocationDo struct{ gen.DO }
+
+type IIpAllocationDo interface {
+	gen.SubQuery
+	Debug() IIpAllocationDo
+	WithContext(ctx context.Context) IIpAllocationDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() IIpAllocationDo
+	WriteDB() IIpAllocationDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) IIpAllocationDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) IIpAllocationDo
+	Not(conds ...gen.Condition) IIpAllocationDo
+	Or(conds ...gen.Condition) IIpAllocationDo
+	Select(conds ...field.Expr) IIpAllocationDo
+	Where(conds ...gen.Condition) IIpAllocationDo
+	Order(conds ...field.Expr) IIpAllocationDo
+	Distinct(cols ...field.Expr) IIpAllocationDo
+	Omit(cols ...field.Expr) IIpAllocationDo
+	Join(table schema.Tabler, on ...field.Expr) IIpAllocationDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) IIpAllocationDo
+	RightJoin(table schema.Tabler, on ...field.Expr) IIpAllocationDo
+	Group(cols ...field.Expr) IIpAllocationDo
+	Having(conds ...gen.Condition) IIpAllocationDo
+	Limit(limit int) IIpAllocationDo
+	Offset(offset int) IIpAllocationDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) IIpAllocationDo
+	Unscoped() IIpAllocationDo
+	Create(values ...*model.IpAllocation) error
+	CreateInBatches(values []*model.IpAllocation, batchSize int) error
+	Save(values ...*model.IpAllocation) error
+	First() (*model.IpAllocation, error)
+	Take() (*model.IpAllocation, error)
+	Last() (*model.IpAllocation, error)
+	Find() ([]*model.IpAllocation, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.IpAllocation, err error)
+	FindInBatches(result *[]*model.IpAllocation, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.IpAllocation) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) IIpAllocationDo
+	Assign(attrs ...field.AssignExpr) IIpAllocationDo
+	Joins(fields ...field.RelationField) IIpAllocationDo
+	Preload(fields ...field.RelationField) IIpAllocationDo
+	FirstOrInit() (*model.IpAllocation, error)
+	FirstOrCreate() (*model.IpAllocation, error)
+	FindByPage(offset int, limit int) (result []*model.IpAllocation, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) IIpAllocationDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (i ipAllocationDo) Debug() IIpAllocationDo {
+	return i.withDO(i.DO.Debug())
+}
+
+func (i ipAllocationDo) WithContext(ctx context.Context) IIpAllocationDo {
+	return i.withDO(i.DO.WithContext(ctx))
+}
+
+func (i ipAllocationDo) ReadDB() IIpAllocationDo {
+	return i.Clauses(dbresolver.Read)
+}
+
+func (i ipAllocationDo) WriteDB() IIpAllocationDo {
+	return i.Clauses(dbresolver.Write)
+}
+
+func (i ipAllocationDo) Session(config *gorm.Session) IIpAllocationDo {
+	return i.withDO(i.DO.Session(config))
+}
+
+func (i ipAllocationDo) Clauses(conds ...clause.Expression) IIpAllocationDo {
+	return i.withDO(i.DO.Clauses(conds...))
+}
+
+func (i ipAllocationDo) Returning(value interface{}, columns ...string) IIpAllocationDo {
+	return i.withDO(i.DO.Returning(value, columns...))
+}
+
+func (i ipAllocationDo) Not(conds ...gen.Condition) IIpAllocationDo {
+	return i.withDO(i.DO.Not(conds...))
+}
+
+func (i ipAllocationDo) Or(conds ...gen.Condition) IIpAllocationDo {
+	return i.withDO(i.DO.Or(conds...))
+}
+
+func (i ipAllocationDo) Select(conds ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Select(conds...))
+}
+
+func (i ipAllocationDo) Where(conds ...gen.Condition) IIpAllocationDo {
+	return i.withDO(i.DO.Where(conds...))
+}
+
+func (i ipAllocationDo) Order(conds ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Order(conds...))
+}
+
+func (i ipAllocationDo) Distinct(cols ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Distinct(cols...))
+}
+
+func (i ipAllocationDo) Omit(cols ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Omit(cols...))
+}
+
+func (i ipAllocationDo) Join(table schema.Tabler, on ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Join(table, on...))
+}
+
+func (i ipAllocationDo) LeftJoin(table schema.Tabler, on ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.LeftJoin(table, on...))
+}
+
+func (i ipAllocationDo) RightJoin(table schema.Tabler, on ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.RightJoin(table, on...))
+}
+
+func (i ipAllocationDo) Group(cols ...field.Expr) IIpAllocationDo {
+	return i.withDO(i.DO.Group(cols...))
+}
+
+func (i ipAllocationDo) Having(conds ...gen.Condition) IIpAllocationDo {
+	return i.withDO(i.DO.Having(conds...))
+}
+
+func (i ipAllocationDo) Limit(limit int) IIpAllocationDo {
+	return i.withDO(i.DO.Limit(limit))
+}
+
+func (i ipAllocationDo) Offset(offset int) IIpAllocationDo {
+	return i.withDO(i.DO.Offset(offset))
+}
+
+func (i ipAllocationDo) Scopes(funcs ...func(gen.Dao) gen.Dao) IIpAllocationDo {
+	return i.withDO(i.DO.Scopes(funcs...))
+}
+
+func (i ipAllocationDo) Unscoped() IIpAllocationDo {
+	return i.withDO(i.DO.Unscoped())
+}
+
+func (i ipAllocationDo) Create(values ...*model.IpAllocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return i.DO.Create(values)
+}
+
+func (i ipAllocationDo) CreateInBatches(values []*model.IpAllocation, batchSize int) error {
+	return i.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (i ipAllocationDo) Save(values ...*model.IpAllocation) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return i.DO.Save(values)
+}
+
+func (i ipAllocationDo) First() (*model.IpAllocation, error) {
+	if result, err := i.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.IpAllocation), nil
+	}
+}
+
+func (i ipAllocationDo) Take() (*model.IpAllocation, error) {
+	if result, err := i.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.IpAllocation), nil
+	}
+}
+
+func (i ipAllocationDo) Last() (*model.IpAllocation, error) {
+	if result, err := i.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.IpAllocation), nil
+	}
+}
+
+func (i ipAllocationDo) Find() ([]*model.IpAllocation, error) {
+	result, err := i.DO.Find()
+	return result.([]*model.IpAllocation), err
+}
+
+func (i ipAllocationDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.IpAllocation, err error) {
+	buf := make([]*model.IpAllocation, 0, batchSize)
+	err = i.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (i ipAllocationDo) FindInBatches(result *[]*model.IpAllocation, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return i.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (i ipAllocationDo) Attrs(attrs ...field.AssignExpr) IIpAllocationDo {
+	return i.withDO(i.DO.Attrs(attrs...))
+}
+
+func (i ipAllocationDo) Assign(attrs ...field.AssignExpr) IIpAllocationDo {
+	return i.withDO(i.DO.Assign(attrs...))
+}
+
+func (i ipAllocationDo) Joins(fields ...field.RelationField) IIpAllocationDo {
+	for _, _f := range fields {
+		i = *i.withDO(i.DO.Joins(_f))
+	}
+	return &i
+}
+
+func (i ipAllocationDo) Preload(fields ...field.RelationField) IIpAllocationDo {
+	for _, _f := range fields {
+		i = *i.withDO(i.DO.Preload(_f))
+	}
+	return &i
+}
+
+func (i ipAllocationDo) FirstOrInit() (*model.IpAllocation, error) {
+	if result, err := i.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.IpAllocation), nil
+	}
+}
+
+func (i ipAllocationDo) FirstOrCreate() (*model.IpAllocation, error) {
+	if result, err := i.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.IpAllocation), nil
+	}
+}
+
+func (i ipAllocationDo) FindByPage(offset int, limit int) (result []*model.IpAllocation, count int64, err error) {
+	result, err = i.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = i.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (i ipAllocationDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = i.Count()
+	if err != nil {
+		return
+	}
+
+	err = i.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (i ipAllocationDo) Scan(result interface{}) (err error) {
+	return i.DO.Scan(result)
+}
+
+func (i ipAllocationDo) Delete(models ...*model.IpAllocation) (result gen.ResultInfo, err error) {
+	return i.DO.Delete(models)
+}
+
+func (i *ipAllocationDo) withDO(do gen.Dao) *ipAllocationDo {
+	i.DO = *do.(*gen.DO)
+	return i
+}
@@ -0,0 +1,420 @@
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+// Code generated by gorm.io/gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+
+	"gorm.io/plugin/dbresolver"
+
+	"doo-store/backend/core/model"
+)
+
+func newNginxLocationVersion(db *gorm.DB, opts ...gen.DOOption) nginxLocationVersion {
+	_nginxLocationVersion := nginxLocationVersion{}
+
+	_nginxLocationVersion.nginxLocationVersionDo.UseDB(db, opts...)
+	_nginxLocationVersion.nginxLocationVersionDo.UseModel(&model.NginxLocationVersion{})
+
+	tableName := _nginxLocationVersion.nginxLocationVersionDo.TableName()
+	_nginxLocationVersion.ALL = field.NewAsterisk(tableName)
+	_nginxLocationVersion.ID = field.NewInt64(tableName, "id")
+	_nginxLocationVersion.CreatedAt = field.NewTime(tableName, "created_at")
+	_nginxLocationVersion.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_nginxLocationVersion.AppInstalledId = field.NewInt64(tableName, "app_installed_id")
+	_nginxLocationVersion.Key = field.NewString(tableName, "key")
+	_nginxLocationVersion.Version = field.NewInt(tableName, "version")
+	_nginxLocationVersion.Template = field.NewString(tableName, "template")
+	_nginxLocationVersion.Content = field.NewString(tableName, "content")
+	_nginxLocationVersion.Port = field.NewInt(tableName, "port")
+	_nginxLocationVersion.Active = field.NewBool(tableName, "active")
+	_nginxLocationVersion.Remark = field.NewString(tableName, "remark")
+
+	_nginxLocationVersion.fillFieldMap()
+
+	return _nginxLocationVersion
+}
+
+type nginxLocationVersion struct {
+	nginxLocationVersionDo
+
+	ALL            field.Asterisk
+	ID             field.Int64
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	AppInstalledId field.Int64
+	Key            field.String
+	Version        field.Int
+	Template       field.String
+	Content        field.String
+	Port           field.Int
+	Active         field.Bool
+	Remark         field.String
+
+	fieldMap map[string]field.Expr
+}
+
+func (n nginxLocationVersion) Table(newTableName string) *nginxLocationVersion {
+	n.nginxLocationVersionDo.UseTable(newTableName)
+	return n.updateTableName(newTableName)
+}
+
+func (n nginxLocationVersion) As(alias string) *nginxLocationVersion {
+	n.nginxLocationVersionDo.DO = *(n.nginxLocationVersionDo.As(alias).(*gen.DO))
+	return n.updateTableName(alias)
+}
+
+func (n *nginxLocationVersion) updateTableName(table string) *nginxLocationVersion {
+	n.ALL = field.NewAsterisk(table)
+	n.ID = field.NewInt64(table, "id")
+	n.CreatedAt = field.NewTime(table, "created_at")
+	n.UpdatedAt = field.NewTime(table, "updated_at")
+	n.AppInstalledId = field.NewInt64(table, "app_installed_id")
+	n.Key = field.NewString(table, "key")
+	n.Version = field.NewInt(table, "version")
+	n.Template = field.NewString(table, "template")
+	n.Content = field.NewString(table, "content")
+	n.Port = field.NewInt(table, "port")
+	n.Active = field.NewBool(table, "active")
+	n.Remark = field.NewString(table, "remark")
+
+	n.fillFieldMap()
+
+	return n
+}
+
+func (n *nginxLocationVersion) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
+	_f, ok := n.fieldMap[fieldName]
+	if !ok || _f == nil {
+		return nil, false
+	}
+	_oe, ok := _f.(field.OrderExpr)
+	return _oe, ok
+}
+
+func (n *nginxLocationVersion) fillFieldMap() {
+	n.fieldMap = make(map[string]field.Expr, 11)
+	n.fieldMap["id"] = n.ID
+	n.fieldMap["created_at"] = n.CreatedAt
+	n.fieldMap["updated_at"] = n.UpdatedAt
+	n.fieldMap["app_installed_id"] = n.AppInstalledId
+	n.fieldMap["key"] = n.Key
+	n.fieldMap["version"] = n.Version
+	n.fieldMap["template"] = n.Template
+	n.fieldMap["content"] = n.Content
+	n.fieldMap["port"] = n.Port
+	n.fieldMap["active"] = n.Active
+	n.fieldMap["remark"] = n.Remark
+}
+
+func (n nginxLocationVersion) clone(db *gorm.DB) nginxLocationVersion {
+	n.nginxLocationVersionDo.ReplaceConnPool(db.Statement.ConnPool)
+	return n
+}
+
+func (n nginxLocationVersion) replaceDB(db *gorm.DB) nginxLocationVersion {
+	n.nginxLocationVersionDo.ReplaceDB(db)
+	return n
+}
+
+type nginxLocationVersionDo struct{ gen.DO }
+
+type INginxLocationVersionDo interface {
+	gen.SubQuery
+	Debug() INginxLocationVersionDo
+	WithContext(ctx context.Context) INginxLocationVersionDo
+	WithResult(fc func(tx gen.Dao)) gen.ResultInfo
+	ReplaceDB(db *gorm.DB)
+	ReadDB() INginxLocationVersionDo
+	WriteDB() INginxLocationVersionDo
+	As(alias string) gen.Dao
+	Session(config *gorm.Session) INginxLocationVersionDo
+	Columns(cols ...field.Expr) gen.Columns
+	Clauses(conds ...clause.Expression) INginxLocationVersionDo
+	Not(conds ...gen.Condition) INginxLocationVersionDo
+	Or(conds ...gen.Condition) INginxLocationVersionDo
+	Select(conds ...field.Expr) INginxLocationVersionDo
+	Where(conds ...gen.Condition) INginxLocationVersionDo
+	Order(conds ...field.Expr) INginxLocationVersionDo
+	Distinct(cols ...field.Expr) INginxLocationVersionDo
+	Omit(cols ...field.Expr) INginxLocationVersionDo
+	Join(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo
+	LeftJoin(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo
+	RightJoin(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo
+	Group(cols ...field.Expr) INginxLocationVersionDo
+	Having(conds ...gen.Condition) INginxLocationVersionDo
+	Limit(limit int) INginxLocationVersionDo
+	Offset(offset int) INginxLocationVersionDo
+	Count() (count int64, err error)
+	Scopes(funcs ...func(gen.Dao) gen.Dao) INginxLocationVersionDo
+	Unscoped() INginxLocationVersionDo
+	Create(values ...*model.NginxLocationVersion) error
+	CreateInBatches(values []*model.NginxLocationVersion, batchSize int) error
+	Save(values ...*model.NginxLocationVersion) error
+	First() (*model.NginxLocationVersion, error)
+	Take() (*model.NginxLocationVersion, error)
+	Last() (*model.NginxLocationVersion, error)
+	Find() ([]*model.NginxLocationVersion, error)
+	FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.NginxLocationVersion, err error)
+	FindInBatches(result *[]*model.NginxLocationVersion, batchSize int, fc func(tx gen.Dao, batch int) error) error
+	Pluck(column field.Expr, dest interface{}) error
+	Delete(...*model.NginxLocationVersion) (info gen.ResultInfo, err error)
+	Update(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	Updates(value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumn(column field.Expr, value interface{}) (info gen.ResultInfo, err error)
+	UpdateColumnSimple(columns ...field.AssignExpr) (info gen.ResultInfo, err error)
+	UpdateColumns(value interface{}) (info gen.ResultInfo, err error)
+	UpdateFrom(q gen.SubQuery) gen.Dao
+	Attrs(attrs ...field.AssignExpr) INginxLocationVersionDo
+	Assign(attrs ...field.AssignExpr) INginxLocationVersionDo
+	Joins(fields ...field.RelationField) INginxLocationVersionDo
+	Preload(fields ...field.RelationField) INginxLocationVersionDo
+	FirstOrInit() (*model.NginxLocationVersion, error)
+	FirstOrCreate() (*model.NginxLocationVersion, error)
+	FindByPage(offset int, limit int) (result []*model.NginxLocationVersion, count int64, err error)
+	ScanByPage(result interface{}, offset int, limit int) (count int64, err error)
+	Scan(result interface{}) (err error)
+	Returning(value interface{}, columns ...string) INginxLocationVersionDo
+	UnderlyingDB() *gorm.DB
+	schema.Tabler
+}
+
+func (n nginxLocationVersionDo) Debug() INginxLocationVersionDo {
+	return n.withDO(n.DO.Debug())
+}
+
+func (n nginxLocationVersionDo) WithContext(ctx context.Context) INginxLocationVersionDo {
+	return n.withDO(n.DO.WithContext(ctx))
+}
+
+func (n nginxLocationVersionDo) ReadDB() INginxLocationVersionDo {
+	return n.Clauses(dbresolver.Read)
+}
+
+func (n nginxLocationVersionDo) WriteDB() INginxLocationVersionDo {
+	return n.Clauses(dbresolver.Write)
+}
+
+func (n nginxLocationVersionDo) Session(config *gorm.Session) INginxLocationVersionDo {
+	return n.withDO(n.DO.Session(config))
+}
+
+func (n nginxLocationVersionDo) Clauses(conds ...clause.Expression) INginxLocationVersionDo {
+	return n.withDO(n.DO.Clauses(conds...))
+}
+
+func (n nginxLocationVersionDo) Returning(value interface{}, columns ...string) INginxLocationVersionDo {
+	return n.withDO(n.DO.Returning(value, columns...))
+}
+
+func (n nginxLocationVersionDo) Not(conds ...gen.Condition) INginxLocationVersionDo {
+	return n.withDO(n.DO.Not(conds...))
+}
+
+func (n nginxLocationVersionDo) Or(conds ...gen.Condition) INginxLocationVersionDo {
+	return n.withDO(n.DO.Or(conds...))
+}
+
+func (n nginxLocationVersionDo) Select(conds ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Select(conds...))
+}
+
+func (n nginxLocationVersionDo) Where(conds ...gen.Condition) INginxLocationVersionDo {
+	return n.withDO(n.DO.Where(conds...))
+}
+
+func (n nginxLocationVersionDo) Order(conds ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Order(conds...))
+}
+
+func (n nginxLocationVersionDo) Distinct(cols ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Distinct(cols...))
+}
+
+func (n nginxLocationVersionDo) Omit(cols ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Omit(cols...))
+}
+
+func (n nginxLocationVersionDo) Join(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Join(table, on...))
+}
+
+func (n nginxLocationVersionDo) LeftJoin(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.LeftJoin(table, on...))
+}
+
+func (n nginxLocationVersionDo) RightJoin(table schema.Tabler, on ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.RightJoin(table, on...))
+}
+
+func (n nginxLocationVersionDo) Group(cols ...field.Expr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Group(cols...))
+}
+
+func (n nginxLocationVersionDo) Having(conds ...gen.Condition) INginxLocationVersionDo {
+	return n.withDO(n.DO.Having(conds...))
+}
+
+func (n nginxLocationVersionDo) Limit(limit int) INginxLocationVersionDo {
+	return n.withDO(n.DO.Limit(limit))
+}
+
+func (n nginxLocationVersionDo) Offset(offset int) INginxLocationVersionDo {
+	return n.withDO(n.DO.Offset(offset))
+}
+
+func (n nginxLocationVersionDo) Scopes(funcs ...func(gen.Dao) gen.Dao) INginxLocationVersionDo {
+	return n.withDO(n.DO.Scopes(funcs...))
+}
+
+func (n nginxLocationVersionDo) Unscoped() INginxLocationVersionDo {
+	return n.withDO(n.DO.Unscoped())
+}
+
+func (n nginxLocationVersionDo) Create(values ...*model.NginxLocationVersion) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return n.DO.Create(values)
+}
+
+func (n nginxLocationVersionDo) CreateInBatches(values []*model.NginxLocationVersion, batchSize int) error {
+	return n.DO.CreateInBatches(values, batchSize)
+}
+
+// Save : !!! underlying implementation is different with GORM
+// The method is equivalent to executing the statement: db.Clauses(clause.OnConflict{UpdateAll: true}).Create(values)
+func (n nginxLocationVersionDo) Save(values ...*model.NginxLocationVersion) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return n.DO.Save(values)
+}
+
+func (n nginxLocationVersionDo) First() (*model.NginxLocationVersion, error) {
+	if result, err := n.DO.First(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NginxLocationVersion), nil
+	}
+}
+
+func (n nginxLocationVersionDo) Take() (*model.NginxLocationVersion, error) {
+	if result, err := n.DO.Take(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NginxLocationVersion), nil
+	}
+}
+
+func (n nginxLocationVersionDo) Last() (*model.NginxLocationVersion, error) {
+	if result, err := n.DO.Last(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NginxLocationVersion), nil
+	}
+}
+
+func (n nginxLocationVersionDo) Find() ([]*model.NginxLocationVersion, error) {
+	result, err := n.DO.Find()
+	return result.([]*model.NginxLocationVersion), err
+}
+
+func (n nginxLocationVersionDo) FindInBatch(batchSize int, fc func(tx gen.Dao, batch int) error) (results []*model.NginxLocationVersion, err error) {
+	buf := make([]*model.NginxLocationVersion, 0, batchSize)
+	err = n.DO.FindInBatches(&buf, batchSize, func(tx gen.Dao, batch int) error {
+		defer func() { results = append(results, buf...) }()
+		return fc(tx, batch)
+	})
+	return results, err
+}
+
+func (n nginxLocationVersionDo) FindInBatches(result *[]*model.NginxLocationVersion, batchSize int, fc func(tx gen.Dao, batch int) error) error {
+	return n.DO.FindInBatches(result, batchSize, fc)
+}
+
+func (n nginxLocationVersionDo) Attrs(attrs ...field.AssignExpr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Attrs(attrs...))
+}
+
+func (n nginxLocationVersionDo) Assign(attrs ...field.AssignExpr) INginxLocationVersionDo {
+	return n.withDO(n.DO.Assign(attrs...))
+}
+
+func (n nginxLocationVersionDo) Joins(fields ...field.RelationField) INginxLocationVersionDo {
+	for _, _f := range fields {
+		n = *n.withDO(n.DO.Joins(_f))
+	}
+	return &n
+}
+
+func (n nginxLocationVersionDo) Preload(fields ...field.RelationField) INginxLocationVersionDo {
+	for _, _f := range fields {
+		n = *n.withDO(n.DO.Preload(_f))
+	}
+	return &n
+}
+
+func (n nginxLocationVersionDo) FirstOrInit() (*model.NginxLocationVersion, error) {
+	if result, err := n.DO.FirstOrInit(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NginxLocationVersion), nil
+	}
+}
+
+func (n nginxLocationVersionDo) FirstOrCreate() (*model.NginxLocationVersion, error) {
+	if result, err := n.DO.FirstOrCreate(); err != nil {
+		return nil, err
+	} else {
+		return result.(*model.NginxLocationVersion), nil
+	}
+}
+
+func (n nginxLocationVersionDo) FindByPage(offset int, limit int) (result []*model.NginxLocationVersion, count int64, err error) {
+	result, err = n.Offset(offset).Limit(limit).Find()
+	if err != nil {
+		return
+	}
+
+	if size := len(result); 0 < limit && 0 < size && size < limit {
+		count = int64(size + offset)
+		return
+	}
+
+	count, err = n.Offset(-1).Limit(-1).Count()
+	return
+}
+
+func (n nginxLocationVersionDo) ScanByPage(result interface{}, offset int, limit int) (count int64, err error) {
+	count, err = n.Count()
+	if err != nil {
+		return
+	}
+
+	err = n.Offset(offset).Limit(limit).Scan(result)
+	return
+}
+
+func (n nginxLocationVersionDo) Scan(result interface{}) (err error) {
+	return n.DO.Scan(result)
+}
+
+func (n nginxLocationVersionDo) Delete(models ...*model.NginxLocationVersion) (result gen.ResultInfo, err error) {
+	return n.DO.Delete(models)
+}
+
+func (n *nginxLocationVersionDo) withDO(do gen.Dao) *nginxLocationVersionDo {
+	n.DO = *do.(*gen.DO)
+	return n
+}
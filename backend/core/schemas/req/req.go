@@ -1,9 +1,14 @@
 package req
 
+import "doo-store/backend/core/dto"
+
 type GenEnvReq struct {
 	AppKey        string
 	ContainerName string
 	IPAddress     string
+	IP6Address    string // 未启用IPv6时为空
 	Envs          map[string]any
+	Fields        []*dto.FormField // 插件表单字段，用于按字段类型格式化环境变量
+	SecretKeys    []string         // 需要加密保存的环境变量
 	WriteFile     bool
 }
@@ -25,6 +25,7 @@ func (m PluginActinManager) Restart(appInstalled *model.AppInstalled, envContent
 		return fmt.Errorf("执行docker compose down命令失败: %w", err)
 	}
 	_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusInstalling)
+	recordAppStatus(appInstalled, model.PluginStatusInstalling, "")
 	// 写入docker-compose.yaml和环境文件
 	composeFile, err = pluginHelper.WriteComposeFile(appKey, appInstalled.DockerCompose)
 	if err != nil {
@@ -40,9 +41,11 @@ func (m PluginActinManager) Restart(appInstalled *model.AppInstalled, envContent
 	if err != nil {
 		log.Error("执行docker compose up命令错误", stdout)
 		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusUpErr)
+		recordAppStatus(appInstalled, model.PluginStatusUpErr, stdout)
 		return err
 	}
 	_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusRunning)
+	recordAppStatus(appInstalled, model.PluginStatusRunning, "")
 
 	return nil
 }
@@ -106,7 +109,11 @@ func (m PluginActinManager) Up(appInstalled *model.AppInstalled, envContent stri
 				Message: err.Error(),
 			},
 		)
+		recordAppStatus(appInstalled, model.PluginStatusUpErr, err.Error())
 		stderr = err.Error()
+	} else {
+		recordAppStatus(appInstalled, model.PluginStatusRunning, "")
+		recordServiceStatuses(appInstalled)
 	}
 	insertLog(appInstalled.ID, "插件启动", stderr)
 	return err
@@ -141,6 +148,7 @@ func (m PluginActinManager) Start(appInstalled *model.AppInstalled) error {
 			repo.AppInstalled.Message.ColumnName().String(): "",
 		},
 	)
+	recordAppStatus(appInstalled, model.PluginStatusRunning, "")
 	insertLog(appInstalled.ID, fmt.Sprintf("插件操作[%s]", "start"), stdout)
 	return nil
 }
@@ -152,6 +160,7 @@ func (m PluginActinManager) Stop(appInstalled *model.AppInstalled) error {
 	if err != nil {
 		return err
 	}
+	recordAppStatus(appInstalled, model.PluginStatusStopped, "")
 	stdout, err := compose.Stop(composeFile)
 	if err != nil {
 		return fmt.Errorf("error docker compose stop: %s", err.Error())
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/response"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/archive"
+	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/docker"
+	"doo-store/backend/utils/filelock"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type AppBackupManager struct {
+}
+
+var appBackupManager = AppBackupManager{}
+
+// scheduledBackupSuffix 定时备份文件名中的标识，格式为 <key>-scheduled-<时间>.tar.gz
+const scheduledBackupSuffix = "scheduled"
+
+// GetBackupDir 获取插件的备份目录
+func (m AppBackupManager) GetBackupDir(key string) string {
+	return path.Join(constant.BackupDir, key)
+}
+
+// Lock 获取插件的备份锁，同一插件同一时间只允许一个备份或导出，返回释放锁的函数
+// 使用备份目录中的文件锁，命令行的整站导出与服务进程之间同样互斥
+func (m AppBackupManager) Lock(key string) (func(), error) {
+	unlock, err := filelock.TryLock(path.Join(m.GetBackupDir(key), constant.BackupLockFile))
+	if errors.Is(err, filelock.ErrLocked) {
+		return nil, errors.New(constant.ErrBackupRunning)
+	}
+	return unlock, err
+}
+
+// IsScheduled 判断备份文件是否为定时备份
+func (m AppBackupManager) IsScheduled(key, name string) bool {
+	return strings.HasPrefix(name, fmt.Sprintf("%s-%s-", key, scheduledBackupSuffix))
+}
+
+// GetBackupFile 获取备份文件的完整路径，文件名中不允许包含路径
+func (m AppBackupManager) GetBackupFile(key, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || !strings.HasSuffix(name, ".tar.gz") {
+		return "", errors.New(constant.ErrBackupNotFound)
+	}
+	file := path.Join(m.GetBackupDir(key), name)
+	if _, err := os.Stat(file); err != nil {
+		return "", errors.New(constant.ErrBackupNotFound)
+	}
+	return file, nil
+}
+
+// Backup 备份插件，备份期间会停止插件的容器
+// 归档中包含备份清单、插件工作目录以及 compose 管理的命名卷，scheduled 为 true 时文件名中带有定时备份标识
+func (m AppBackupManager) Backup(appInstalled *model.AppInstalled, scheduled bool) (string, error) {
+	unlock, err := m.Lock(appInstalled.Key)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	log.Info("开始备份插件:", appInstalled.Key)
+	name := fmt.Sprintf("%s-%s.tar.gz", appInstalled.Key, time.Now().Format("20060102150405"))
+	if scheduled {
+		name = fmt.Sprintf("%s-%s-%s.tar.gz", appInstalled.Key, scheduledBackupSuffix, time.Now().Format("20060102150405"))
+	}
+	backupFile := path.Join(m.GetBackupDir(appInstalled.Key), name)
+	err = func() error {
+		writer, err := archive.NewTarGzWriter(backupFile)
+		if err != nil {
+			return err
+		}
+		// 备份中的参数未加密，只允许商店进程读取
+		if err := os.Chmod(backupFile, 0600); err != nil {
+			log.Warn("修改备份文件权限失败:", err)
+		}
+		if err := m.WriteArchive(writer, "", appInstalled, true); err != nil {
+			_ = writer.Close()
+			return err
+		}
+		return writer.Close()
+	}()
+	if err != nil {
+		log.Error("写入备份文件失败:", err)
+		_ = os.Remove(backupFile)
+		insertLog(appInstalled.ID, "插件备份", err.Error())
+		return "", errors.New(constant.ErrBackupFailed)
+	}
+
+	insertLog(appInstalled.ID, "插件备份", name)
+	log.Info("插件备份完成:", backupFile)
+	return name, nil
+}
+
+// WriteArchive 将插件的备份清单、工作目录以及命名卷写入归档的 prefix 目录下，写入命名卷期间会停止插件的容器
+// 清单中的参数以解密后的形式保存，不依赖备份时的密钥
+func (m AppBackupManager) WriteArchive(writer *archive.TarGzWriter, prefix string, appInstalled *model.AppInstalled, withVolumes bool) error {
+	appKey, composeFile := pluginHelper.GetAppKeyAndComposeFile(appInstalled.Key)
+	params, err := secretManager.DecryptJson(appInstalled.Params)
+	if err != nil {
+		return fmt.Errorf("decrypt params failed: %w", err)
+	}
+
+	volumes := map[string]string{}
+	if withVolumes {
+		dockerCompose, err := compose.PreCheck(appInstalled.DockerCompose)
+		if err != nil {
+			return fmt.Errorf("parse docker-compose failed: %w", err)
+		}
+		volumes = dockerCompose.ExtractVolumes(compose.ProjectName(composeFile))
+	}
+
+	services, err := repo.AppServiceStatus.Where(repo.AppServiceStatus.InstallID.Eq(appInstalled.ID)).Find()
+	if err != nil {
+		return fmt.Errorf("query services failed: %w", err)
+	}
+
+	manifest := dto.BackupManifest{
+		FormatVersion: dto.BackupFormatVersion,
+		Key:           appInstalled.Key,
+		Version:       appInstalled.Version,
+		ContainerName: appInstalled.Name,
+		IpAddress:     appInstalled.IpAddress,
+		Ip6Address:    appInstalled.Ip6Address,
+		Params:        params,
+		DockerCompose: appInstalled.DockerCompose,
+		Location:      appInstalled.Location,
+		Domain:        appInstalled.Domain,
+		Services:      make([]dto.BackupService, 0, len(services)),
+		Volumes:       make([]string, 0, len(volumes)),
+		AppID:         config.EnvConfig.APP_ID,
+		AppIPPR:       config.EnvConfig.APP_IPPR,
+		CreatedAt:     time.Now(),
+	}
+	for _, service := range services {
+		manifest.Services = append(manifest.Services, dto.BackupService{
+			ServiceName:   service.ServiceName,
+			ContainerName: service.ContainerName,
+			IpAddress:     service.IpAddress,
+			Image:         service.Image,
+		})
+	}
+	for key := range volumes {
+		manifest.Volumes = append(manifest.Volumes, key)
+	}
+	sort.Strings(manifest.Volumes)
+	manifestJson, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// 导出卷数据时停止插件以保证数据一致，写入完成后恢复运行
+	// 停止前将插件标记为已停止，避免容器退出被当作异常退出触发自动重启
+	if withVolumes && appInstalled.IsRunning() {
+		restore, err := m.stopForBackup(appInstalled, composeFile)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	if err := writer.AddBytes(path.Join(prefix, dto.BackupManifestFile), manifestJson); err != nil {
+		return err
+	}
+	// docker-compose.yml 与 .env 会在恢复时重新生成
+	workspaceDir := path.Join(constant.AppInstallDir, appKey)
+	if err := writer.AddDir(path.Join(prefix, dto.BackupWorkspaceDir), workspaceDir, "docker-compose.yml", ".env"); err != nil {
+		return err
+	}
+	if len(manifest.Volumes) == 0 {
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "doo-store-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	for _, key := range manifest.Volumes {
+		volumeFile := path.Join(tmpDir, key+".tar.gz")
+		if err := docker.ExportVolume(volumes[key], volumeFile); err != nil {
+			return err
+		}
+		if err := writer.AddFile(path.Join(prefix, dto.BackupVolumesDir, key+".tar.gz"), volumeFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopForBackup 将插件标记为已停止并停止容器，返回启动容器并恢复原状态的函数
+func (m AppBackupManager) stopForBackup(appInstalled *model.AppInstalled, composeFile string) (func(), error) {
+	previous := appInstalled.Status
+	_, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusStopped)
+	if err != nil {
+		return nil, err
+	}
+	recordAppStatus(appInstalled, model.PluginStatusStopped, "")
+	if stdout, err := compose.Stop(composeFile); err != nil {
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, previous)
+		recordAppStatus(appInstalled, previous, "")
+		return nil, fmt.Errorf("stop plugin failed: %s %w", stdout, err)
+	}
+
+	return func() {
+		if stdout, err := compose.Start(composeFile); err != nil {
+			log.Error("备份后启动插件失败:", stdout, err)
+			insertLog(appInstalled.ID, "插件备份", fmt.Sprintf("备份后启动插件失败: %s", stdout))
+			return
+		}
+		// 备份期间状态被其他操作修改时不再恢复
+		result, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID), repo.AppInstalled.Status.Eq(model.PluginStatusStopped)).
+			Update(repo.AppInstalled.Status, previous)
+		if err != nil {
+			log.Error("恢复插件状态失败:", err)
+			return
+		}
+		if result.RowsAffected > 0 {
+			recordAppStatus(appInstalled, previous, "")
+		}
+	}, nil
+}
+
+// List 获取插件的备份文件列表，按时间倒序
+func (m AppBackupManager) List(key string) ([]*response.AppBackupFile, error) {
+	result := []*response.AppBackupFile{}
+	entries, err := os.ReadDir(m.GetBackupDir(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		result = append(result, &response.AppBackupFile{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			Scheduled: m.IsScheduled(key, entry.Name()),
+			CreatedAt: info.ModTime(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].Name > result[j].Name
+		}
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Prune 按照保留数量清理旧的定时备份，手动创建的备份不会被清理，keep 小于等于0时不清理，返回被删除的文件名
+func (m AppBackupManager) Prune(key string, keep int) ([]string, error) {
+	removed := []string{}
+	if keep <= 0 {
+		return removed, nil
+	}
+	files, err := m.List(key)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := 0
+	for _, file := range files {
+		if !file.Scheduled {
+			continue
+		}
+		scheduled++
+		if scheduled <= keep {
+			continue
+		}
+		if err := os.Remove(path.Join(m.GetBackupDir(key), file.Name)); err != nil {
+			log.Error("删除过期备份失败:", err)
+			continue
+		}
+		removed = append(removed, file.Name)
+	}
+	return removed, nil
+}
+
+// ScheduledBackup 执行定时备份并按照保留数量清理旧的备份
+func ScheduledBackup(schedule *model.AppBackupSchedule) error {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(schedule.AppInstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	if _, err := appBackupManager.Backup(appInstalled, true); err != nil {
+		insertLog(appInstalled.ID, "定时备份", err.Error())
+		return err
+	}
+	removed, err := appBackupManager.Prune(appInstalled.Key, schedule.Retention)
+	if err != nil {
+		log.Error("清理过期备份失败:", err)
+		return nil
+	}
+	if len(removed) > 0 {
+		insertLog(appInstalled.ID, "清理过期备份", strings.Join(removed, ","))
+	}
+	return nil
+}
@@ -3,13 +3,28 @@ package service
 import (
 	"doo-store/backend/config"
 	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
 	schemasReq "doo-store/backend/core/schemas/req"
+	"doo-store/backend/utils/common"
 	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/docker"
+	e "doo-store/backend/utils/error"
+	"doo-store/backend/utils/nginx"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -43,10 +58,12 @@ func (h PluginHelper) GenEnv(genEnvReq schemasReq.GenEnvReq) (envContent, envJso
 	envFile := fmt.Sprintf("%s/%s/.env", constant.AppInstallDir, genEnvReq.AppKey)
 	envContent = fmt.Sprintf("%s=%s\n", "CONTAINER_NAME", genEnvReq.ContainerName)
 	envContent += fmt.Sprintf("%s=%s\n", "IP_ADDRESS", genEnvReq.IPAddress)
+	envContent += fmt.Sprintf("%s=%s\n", "IP6_ADDRESS", genEnvReq.IP6Address)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DIR", config.EnvConfig.DooTask().DIR)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_APP_ID", config.EnvConfig.DooTask().APP_ID)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_APP_IPPR", config.EnvConfig.DooTask().APP_IPPR)
-	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_APP_KEY", config.EnvConfig.DooTask().APP_KEY)
+	// 密钥与密码中可能包含 $ 等字符，需要转义
+	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_APP_KEY", compose.EscapeEnvValue(config.EnvConfig.DooTask().APP_KEY))
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_NETWORK_NAME", config.EnvConfig.App().NETWORK_NAME)
 
 	// 数据库相关配置
@@ -54,24 +71,29 @@ func (h PluginHelper) GenEnv(genEnvReq schemasReq.GenEnvReq) (envContent, envJso
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_PORT", config.EnvConfig.DooTaskDB().PORT)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_DATABASE", config.EnvConfig.DooTaskDB().DATABASE)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_USERNAME", config.EnvConfig.DooTaskDB().USERNAME)
-	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_PASSWORD", config.EnvConfig.DooTaskDB().PASSWORD)
+	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_PASSWORD", compose.EscapeEnvValue(config.EnvConfig.DooTaskDB().PASSWORD))
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_DB_PREFIX", config.EnvConfig.DooTaskDB().PREFIX)
 
 	// Redis相关
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_REDIS_HOST", config.EnvConfig.DooTaskRedis().HOST)
 	envContent += fmt.Sprintf("%s=%s\n", "DOOTASK_REDIS_PORT", config.EnvConfig.DooTaskRedis().PORT)
 
+	fields := dto.FlattenFields(genEnvReq.Fields)
 	for key, value := range genEnvReq.Envs {
 		var envValue string
-		switch v := value.(type) {
-		case float64:
-			envValue = fmt.Sprintf("%f", v)
-		case string:
-			envValue = v
-		default:
-			envValue = fmt.Sprintf("%v", v)
+		if field, ok := fields[key]; ok {
+			envValue = field.EnvValue(value)
+		} else {
+			switch v := value.(type) {
+			case float64:
+				envValue = fmt.Sprintf("%f", v)
+			case string:
+				envValue = v
+			default:
+				envValue = fmt.Sprintf("%v", v)
+			}
 		}
-		envContent += fmt.Sprintf("%s=%s\n", key, envValue)
+		envContent += fmt.Sprintf("%s=%s\n", key, compose.EscapeEnvValue(envValue))
 	}
 	if genEnvReq.WriteFile {
 		err = os.WriteFile(envFile, []byte(envContent), 0644)
@@ -83,11 +105,16 @@ func (h PluginHelper) GenEnv(genEnvReq schemasReq.GenEnvReq) (envContent, envJso
 	envMap := map[string]string{}
 	envContentLine := strings.Split(envContent, "\n")
 	for _, line := range envContentLine {
-		env := strings.Split(line, "=")
+		env := strings.SplitN(line, "=", 2)
 		if len(env) != 2 {
 			continue
 		}
-		envMap[env[0]] = env[1]
+		envMap[env[0]] = compose.UnescapeEnvValue(env[1])
+	}
+	// 敏感信息加密后保存，.env 文件中仍为明文
+	secretKeys := append(append([]string{}, constant.StoreSecretEnvKeys...), genEnvReq.SecretKeys...)
+	if err = secretManager.EncryptEnv(envMap, secretKeys); err != nil {
+		return
 	}
 	jsonData, err := json.Marshal(envMap)
 	if err != nil {
@@ -97,6 +124,81 @@ func (h PluginHelper) GenEnv(genEnvReq schemasReq.GenEnvReq) (envContent, envJso
 	return
 }
 
+// CheckParamKeys 检查参数名，只允许插件清单中声明的字段与资源限制参数，拒绝商店注入的保留变量
+func (h PluginHelper) CheckParamKeys(ctx *gin.Context, fields []*dto.FormField, params map[string]interface{}) error {
+	declared := dto.FlattenFields(fields)
+	for key := range params {
+		if slices.Contains(constant.ReservedEnvKeys, key) || strings.HasPrefix(key, constant.ReservedEnvPrefix) {
+			log.Warn("参数为系统保留变量:", key)
+			return e.NewErrorWithDetail(ctx, constant.ErrPluginReservedParam, key, nil)
+		}
+		if key == constant.CPUS || key == constant.MemoryLimit {
+			continue
+		}
+		if _, ok := declared[key]; !ok {
+			log.Warn("未声明的插件参数:", key)
+			return e.NewErrorWithDetail(ctx, constant.ErrPluginUnknownParam, key, nil)
+		}
+	}
+	return nil
+}
+
+// KeepProtectedValues 只读与隐藏的字段不允许修改，沿用已保存的值，未保存过时使用默认值
+func (h PluginHelper) KeepProtectedValues(fields []*dto.FormField, params map[string]interface{}, storedParams string) error {
+	stored := map[string]interface{}{}
+	if storedParams != "" {
+		if err := json.Unmarshal([]byte(storedParams), &stored); err != nil {
+			log.Info("解析已保存的参数失败", err)
+			return errors.New(constant.ErrPluginParamParseFailed)
+		}
+	}
+	if err := secretManager.DecryptParams(stored); err != nil {
+		return err
+	}
+	for envKey, field := range dto.FlattenFields(fields) {
+		if !field.ReadOnly && !field.Hidden {
+			continue
+		}
+		if value, exists := stored[envKey]; exists {
+			params[envKey] = value
+		} else if field.Default != nil {
+			params[envKey] = field.Default
+		} else {
+			delete(params, envKey)
+		}
+	}
+	return nil
+}
+
+// WriteFieldFiles 将文件类型字段的内容写入插件工作目录的 files 目录，.env 中对应的值为该文件的相对路径
+func (h PluginHelper) WriteFieldFiles(appKey string, fields []*dto.FormField, params map[string]interface{}) error {
+	filesDir := path.Join(constant.AppInstallDir, appKey, dto.FieldFilesDir)
+	for envKey, field := range dto.FlattenFields(fields) {
+		if field.Type != dto.FieldTypeFile {
+			continue
+		}
+		value, ok := params[envKey].(string)
+		if !ok || value == "" {
+			continue
+		}
+		// 参数名作为文件名，不允许包含路径
+		if envKey == "" || envKey == "." || envKey == ".." || strings.ContainsAny(envKey, `/\`) {
+			return fmt.Errorf("invalid file field name: %s", envKey)
+		}
+		content, err := dto.DecodeFileValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid file content for %s: %w", envKey, err)
+		}
+		if err = os.MkdirAll(filesDir, 0755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(path.Join(filesDir, envKey), content, 0644); err != nil {
+			return fmt.Errorf("failed to write file for %s: %w", envKey, err)
+		}
+	}
+	return nil
+}
+
 // 写环境变量文件
 func (h PluginHelper) WriteEnvFile(appKey, envContent string) (string, error) {
 	envFile := h.GetEnvFile(appKey)
@@ -123,3 +225,244 @@ func (h PluginHelper) GetEnvFile(appKey string) string {
 	envFile := fmt.Sprintf("%s/%s/.env", constant.AppInstallDir, appKey)
 	return envFile
 }
+
+// AddNginxLocation 为插件添加Nginx location配置，添加失败时会将插件停止
+func (h PluginHelper) AddNginxLocation(client docker.Client, appInstalled *model.AppInstalled, appDetail *model.AppDetail) error {
+	if appDetail.NginxConfig == "" {
+		return nil
+	}
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return err
+	}
+
+	locationConfig, err := h.NginxLocationConfig(client, appInstalled, appDetail)
+	if err != nil {
+		return err
+	}
+
+	log.Info("添加Nginx location配置")
+	err = nm.AddLocation(locationConfig)
+	if err != nil {
+		log.Error("添加Nginx配置失败:", err)
+
+		std, err := compose.Operate(h.GetComposeFile(appInstalled.Key), "stop")
+		if err != nil {
+			log.Error("停止容器失败:", std, err)
+		}
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusUpErr)
+		recordAppStatus(appInstalled, model.PluginStatusUpErr, err.Error())
+		return err
+	}
+
+	if _, err := saveNginxVersion(nm, appInstalled, locationConfig, "安装插件"); err != nil {
+		log.Error("保存Nginx配置版本失败:", err)
+	}
+
+	// 提取location
+	locations, _ := nm.ExtractLocationsByKey(appInstalled.Key)
+
+	if len(locations) > 0 {
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Location, locations[0])
+	}
+	return nil
+}
+
+// NginxLocationConfig 根据插件的Nginx模板与镜像暴露的端口生成location配置
+func (h PluginHelper) NginxLocationConfig(client docker.Client, appInstalled *model.AppInstalled, appDetail *model.AppDetail) (*nginx.LocationConfig, error) {
+	port, err := client.GetImageFirstExposedPortByName(fmt.Sprintf("%s:%s", appDetail.Repo, appDetail.Version))
+	if err != nil {
+		log.Error("获取镜像端口失败:", err)
+		return nil, err
+	}
+	serverName := h.PluginDomain(appInstalled.Key, appInstalled.Domain)
+	return nginx.NewLocationConfig(appInstalled.Key, appInstalled.Name).
+		WithTemplate(appDetail.NginxConfig).
+		WithPort(port).
+		WithServerName(serverName).
+		WithCertificate(h.CertificateDomain(serverName)).
+		WithOptions(h.NginxOptions(appInstalled)), nil
+}
+
+// NginxOptions 解析插件的访问控制与限流配置，未设置或无法解析时返回 nil
+func (h PluginHelper) NginxOptions(appInstalled *model.AppInstalled) *nginx.LocationOptions {
+	if appInstalled.NginxOptions == "" {
+		return nil
+	}
+	options := &nginx.LocationOptions{}
+	if err := common.StrToStruct(appInstalled.NginxOptions, options); err != nil {
+		log.Warnf("解析插件 %s 的访问控制配置失败: %v", appInstalled.Key, err)
+		return nil
+	}
+	return options
+}
+
+// CertificateDomain 获取域名可以使用的证书对应的域名，优先使用完全匹配的证书，其次使用通配符证书，没有可用证书时返回空
+func (h PluginHelper) CertificateDomain(serverName string) string {
+	if serverName == "" {
+		return ""
+	}
+	candidates := []string{serverName}
+	if _, parent, ok := strings.Cut(serverName, "."); ok {
+		candidates = append(candidates, "*."+parent)
+	}
+	certificates, err := repo.Certificate.Select(repo.Certificate.Domain).
+		Where(repo.Certificate.Domain.In(candidates...), repo.Certificate.Cert.Neq("")).Find()
+	if err != nil {
+		log.Warnf("查询域名 %s 的证书失败: %v", serverName, err)
+		return ""
+	}
+	for _, candidate := range candidates {
+		for _, certificate := range certificates {
+			if certificate.Domain == candidate {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// PluginDomain 插件使用的独立域名，未设置时使用 <key>.<基础域名>
+func (h PluginHelper) PluginDomain(key, domain string) string {
+	if domain != "" {
+		return domain
+	}
+	return config.EnvConfig.GetPluginDomain(key)
+}
+
+// domainRegexp 匹配合法的域名
+var domainRegexp = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// CheckDomain 检查域名格式以及是否已被其他插件使用，excludeID 为当前插件的安装ID
+func (h PluginHelper) CheckDomain(ctx dto.ServiceContext, key, domain string, excludeID int64) error {
+	if domain == "" {
+		return nil
+	}
+	if len(domain) > 253 || !domainRegexp.MatchString(domain) {
+		return e.NewErrorWithDetail(ctx.C, constant.ErrNginxDomainInvalid, domain, nil)
+	}
+	if slices.Contains(h.reservedDomains(ctx), domain) {
+		return e.NewErrorWithDetail(ctx.C, constant.ErrNginxDomainReserved, domain, nil)
+	}
+	appInstalledList, err := repo.AppInstalled.Select(repo.AppInstalled.ID, repo.AppInstalled.Key, repo.AppInstalled.Domain).Find()
+	if err != nil {
+		return err
+	}
+	for _, appInstalled := range appInstalledList {
+		if appInstalled.ID == excludeID || appInstalled.Key == key {
+			continue
+		}
+		if h.PluginDomain(appInstalled.Key, appInstalled.Domain) == domain {
+			return e.NewErrorWithDetail(ctx.C, constant.ErrNginxDomainInUse, appInstalled.Key, nil)
+		}
+	}
+	return nil
+}
+
+// reservedDomains 不能作为插件独立域名的域名，包括DooTask的域名与基础域名
+// DooTask的域名取自 DOOTASK_URL 以及当前请求经过Nginx转发前的域名
+func (h PluginHelper) reservedDomains(ctx dto.ServiceContext) []string {
+	hosts := []string{strings.TrimPrefix(config.EnvConfig.PLUGIN_BASE_DOMAIN, ".")}
+	if u, err := url.Parse(config.EnvConfig.DOOTASK_URL); err == nil {
+		hosts = append(hosts, u.Hostname())
+	}
+	if ctx.C != nil && ctx.C.Request != nil {
+		forwarded, _, _ := strings.Cut(ctx.C.GetHeader("X-Forwarded-Host"), ",")
+		for _, host := range []string{strings.TrimSpace(forwarded), ctx.C.Request.Host} {
+			if name, _, err := net.SplitHostPort(host); err == nil {
+				host = name
+			}
+			hosts = append(hosts, host)
+		}
+	}
+
+	domains := []string{}
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSuffix(host, ".")); host != "" {
+			domains = append(domains, host)
+		}
+	}
+	return domains
+}
+
+// NewPortAllocator 创建端口分配器，跳过所有容器（包括已停止的容器）已发布的主机端口
+// 商店运行在容器中，无法探测宿主机上非Docker进程占用的端口，这类端口需要在生成规则的端口范围中避开
+func (h PluginHelper) NewPortAllocator(client docker.Client) dto.PortAllocator {
+	reserved := map[int]bool{}
+	loaded := false
+	return func(min, max int) (int, error) {
+		if !loaded {
+			containers, err := client.ListAllContainers()
+			if err != nil {
+				log.Error("获取容器列表失败:", err)
+				return 0, err
+			}
+			for _, container := range containers {
+				for _, port := range container.Ports {
+					if port.PublicPort > 0 {
+						reserved[int(port.PublicPort)] = true
+					}
+				}
+				if container.State == "running" {
+					continue
+				}
+				// 未运行的容器不会列出端口，从容器配置中读取
+				info, err := client.InspectContainer(container.ID)
+				if err != nil || info.HostConfig == nil {
+					continue
+				}
+				for _, bindings := range info.HostConfig.PortBindings {
+					for _, binding := range bindings {
+						if port, err := strconv.Atoi(binding.HostPort); err == nil && port > 0 {
+							reserved[port] = true
+						}
+					}
+				}
+			}
+			loaded = true
+		}
+		for port := min; port <= max; port++ {
+			if reserved[port] {
+				continue
+			}
+			reserved[port] = true
+			return port, nil
+		}
+		return 0, fmt.Errorf("no free port in range %d-%d", min, max)
+	}
+}
+
+// KeepGeneratedValues 修改参数时，未填写的自动生成字段沿用已保存的值
+func (h PluginHelper) KeepGeneratedValues(fields []*dto.FormField, params map[string]interface{}, storedParams string) error {
+	stored := map[string]interface{}{}
+	if storedParams != "" {
+		if err := json.Unmarshal([]byte(storedParams), &stored); err != nil {
+			log.Info("解析已保存的参数失败", err)
+			return errors.New(constant.ErrPluginParamParseFailed)
+		}
+	}
+	if err := secretManager.DecryptParams(stored); err != nil {
+		return err
+	}
+	keep := func(field dto.FormField) {
+		if field.Generator == nil {
+			return
+		}
+		if value, exists := params[field.EnvKey]; exists && value != nil && fmt.Sprintf("%v", value) != "" {
+			return
+		}
+		if value, exists := stored[field.EnvKey]; exists {
+			params[field.EnvKey] = value
+		}
+	}
+	for _, field := range fields {
+		keep(*field)
+		for _, option := range field.Options {
+			for _, subField := range option.SubFields {
+				keep(subField)
+			}
+		}
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"context"
 	"doo-store/backend/config"
 	"doo-store/backend/constant"
 	"doo-store/backend/core/dto"
@@ -16,13 +15,11 @@ import (
 	"doo-store/backend/utils/nginx"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"path"
 	"strings"
 
 	schemasReq "doo-store/backend/core/schemas/req"
 
-	"github.com/docker/docker/api/types/container"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -39,10 +36,14 @@ type AppInstallProcess struct {
 	envJson              string
 	req                  request.AppInstall
 	ipAddress            string
+	ip6Address           string
 	client               docker.Client
 	dockerCompose        *compose.DockerComposeConfig
 	finalDockerCompose   *compose.DockerComposeConfig
-	nm                   *nginx.NginxManager
+	secretKeys           []string
+	formFields           []*dto.FormField
+	replaceID            int64    // 恢复备份时原地替换的安装记录，为0时创建新的安装记录
+	releasedIPs          []string // 替换安装记录时释放的IP，事务提交后同步内存中的分配器
 }
 
 // NewAppInstallProcess 创建新的应用安装流程实例
@@ -118,6 +119,15 @@ func (p *AppInstallProcess) ValidateInstallRequirements() error {
 		log.Error("查询应用详细信息失败:", err)
 		return errors.New(constant.ErrPluginInfoFailed)
 	}
+
+	// 使用独立域名的插件需要可用的域名
+	p.req.Domain = strings.ToLower(strings.TrimSpace(p.req.Domain))
+	if err = pluginHelper.CheckDomain(p.ctx, p.app.Key, p.req.Domain, 0); err != nil {
+		return err
+	}
+	if nginx.DetectMode(p.appDetail.NginxConfig) == nginx.LocationModeHost && pluginHelper.PluginDomain(p.app.Key, p.req.Domain) == "" {
+		return errors.New(constant.ErrNginxDomainRequired)
+	}
 	log.Info("验证安装要求完成")
 	return nil
 }
@@ -131,36 +141,52 @@ func (p *AppInstallProcess) DHCP() error {
 		log.Error("创建Docker客户端失败:", err)
 		return err
 	}
-	client := p.client.GetClient()
-	containers, err := client.ContainerList(context.Background(), container.ListOptions{All: true})
+
+	// 分配新IP，安装记录创建前IP处于预留状态
+	owner := IPOwner{
+		Type: model.IPOwnerInstallation,
+		Name: p.app.Key,
+	}
+	p.ipAddress, err = ipAllocationManager.Allocate(owner)
 	if err != nil {
-		log.Error("获取容器列表失败:", err)
-		return fmt.Errorf(constant.ErrDockerListContainers, err)
-	}
-
-	// 检查所有容器使用的IP
-	for _, container := range containers {
-		if container.NetworkSettings != nil {
-			for _, network := range container.NetworkSettings.Networks {
-				if network.IPAddress != "" {
-					if err := docker.GlobalIPAllocator.RegisterIP(network.IPAddress); err != nil {
-						log.Debugf("注册IP失败 %s: %v", network.IPAddress, err)
-					}
-				}
-			}
-		}
+		return err
 	}
-
-	// 分配新IP
-	p.ipAddress, err = docker.GlobalIPAllocator.AllocateIP()
+	// 启用IPv6时同时分配IPv6地址
+	p.ip6Address, err = ipAllocationManager.Allocate6(owner)
 	if err != nil {
-		log.Error("分配IP地址失败:", err)
+		p.ReleaseIP()
 		return err
 	}
-	log.Info("分配IP流程完成, 分配的IP:", p.ipAddress)
+	log.Info("分配IP流程完成, 分配的IP:", p.ipAddress, " ", p.ip6Address)
 	return nil
 }
 
+// ReleaseIP 安装失败时释放分配的IP
+func (p *AppInstallProcess) ReleaseIP() {
+	for _, ip := range []string{p.ipAddress, p.ip6Address} {
+		if ip != "" {
+			_ = ipAllocationManager.Release(ip)
+		}
+	}
+}
+
+// useHardcodedIP 使用 docker-compose 中写死的IP替换分配的IP，返回IP是否发生变化
+func (p *AppInstallProcess) useHardcodedIP(current *string, ip string, owner IPOwner) (bool, error) {
+	if ip == "" || ip == *current {
+		return false, nil
+	}
+	// 释放已分配的IP
+	if *current != "" {
+		_ = ipAllocationManager.Release(*current)
+	}
+	*current = ip
+	if err := ipAllocationManager.Claim(ip, owner); err != nil {
+		*current = ""
+		return false, ipamError(p.ctx, err)
+	}
+	return true, nil
+}
+
 func (p *AppInstallProcess) genEnv() error {
 	var err error
 	// 资源限制
@@ -171,7 +197,10 @@ func (p *AppInstallProcess) genEnv() error {
 		AppKey:        p.appKey,
 		ContainerName: p.defaultContainerName,
 		IPAddress:     p.ipAddress,
+		IP6Address:    p.ip6Address,
 		Envs:          p.req.Params,
+		Fields:        p.formFields,
+		SecretKeys:    p.secretKeys,
 		WriteFile:     false,
 	})
 	if err != nil {
@@ -184,19 +213,6 @@ func (p *AppInstallProcess) genEnv() error {
 	if err != nil {
 		return err
 	}
-	envChange := false
-	// 是否释放原分配的IP并注册新IP
-	ipList := p.finalDockerCompose.ExtractIpAddress()
-	if len(ipList) > 0 {
-		if ipList[0] != p.ipAddress {
-			// 释放已注册的IP
-			docker.GlobalIPAllocator.ReleaseIP(p.ipAddress)
-			p.ipAddress = ipList[0]
-			docker.GlobalIPAllocator.RegisterIP(p.ipAddress)
-			envChange = true
-		}
-	}
-
 	containerNameList := p.finalDockerCompose.ExtractContainerName()
 	if len(containerNameList) > 0 {
 		if containerNameList[0] != p.containerName {
@@ -204,13 +220,51 @@ func (p *AppInstallProcess) genEnv() error {
 		}
 	}
 
+	// 检测 docker-compose 中写死的IP是否已被其他插件或容器占用
+	ipList := []string{}
+	for _, ip := range p.finalDockerCompose.ExtractIpAddress() {
+		if ip != "" {
+			ipList = append(ipList, ip)
+		}
+	}
+	ip6List := p.finalDockerCompose.ExtractIp6Address()
+	owner := IPOwner{
+		Type:      model.IPOwnerInstallation,
+		Name:      p.app.Key,
+		InstallID: p.replaceID,
+	}
+	err = ipAllocationManager.CheckAvailable(p.client, append(append([]string{}, ipList...), ip6List...), owner, containerNameList)
+	if err != nil {
+		return ipamError(p.ctx, err)
+	}
+
+	// 是否释放原分配的IP并注册新IP
+	envChange := false
+	if len(ipList) > 0 {
+		changed, err := p.useHardcodedIP(&p.ipAddress, ipList[0], owner)
+		if err != nil {
+			return err
+		}
+		envChange = envChange || changed
+	}
+	if len(ip6List) > 0 {
+		changed, err := p.useHardcodedIP(&p.ip6Address, ip6List[0], owner)
+		if err != nil {
+			return err
+		}
+		envChange = envChange || changed
+	}
+
 	// 重新生成一下环境变量配置
 	if envChange {
 		p.envContent, p.envJson, err = pluginHelper.GenEnv(schemasReq.GenEnvReq{
 			AppKey:        p.appKey,
 			ContainerName: p.defaultContainerName,
 			IPAddress:     p.ipAddress,
+			IP6Address:    p.ip6Address,
 			Envs:          p.req.Params,
+			Fields:        p.formFields,
+			SecretKeys:    p.secretKeys,
 			WriteFile:     false,
 		})
 		if err != nil {
@@ -248,12 +302,6 @@ func (p *AppInstallProcess) ValidateParam() error {
 	p.defaultContainerName = config.EnvConfig.GetDefaultContainerName(p.app.Key)
 	p.containerName = p.defaultContainerName
 
-	paramJson, err := json.Marshal(p.req.Params)
-	if err != nil {
-		log.Error("参数序列化失败:", err)
-		return err
-	}
-
 	params := response.AppParams{}
 	err = common.StrToStruct(p.appDetail.Params, &params)
 	if err != nil {
@@ -270,10 +318,49 @@ func (p *AppInstallProcess) ValidateParam() error {
 	// 		}
 	// 	}
 	// }
-	vErr := dto.ValidateFormData(params.FormFields, p.req.Params)
-	if len(vErr) > 0 {
-		log.Warn("参数验证失败:", vErr)
-		return vErr[0]
+	// 只允许设置清单中声明的参数
+	if p.req.Params == nil {
+		p.req.Params = map[string]interface{}{}
+	}
+	if err = pluginHelper.CheckParamKeys(p.ctx.C, params.FormFields, p.req.Params); err != nil {
+		return err
+	}
+
+	// 为带有生成规则且未填写的字段生成值
+	err = dto.GenerateFieldValues(params.FormFields, p.req.Params, nil, pluginHelper.NewPortAllocator(p.client))
+	if err != nil {
+		log.Error("生成参数失败:", err)
+		return errors.New(constant.ErrPluginParamGenerateFailed)
+	}
+
+	// 字段名称按请求语言翻译后再验证，验证错误中的字段名称与界面一致
+	dto.LocalizeFormFields(params.FormFields, p.ctx.Language)
+	vErrs := dto.ValidateFormData(params.FormFields, p.req.Params, params.Rules)
+	if len(vErrs) > 0 {
+		log.Warn("参数验证失败:", vErrs)
+		return vErrs
+	}
+	p.formFields = params.FormFields
+
+	// 文件类型的参数写入工作目录
+	if err = pluginHelper.WriteFieldFiles(p.appKey, params.FormFields, p.req.Params); err != nil {
+		log.Error("写入文件参数失败:", err)
+		return err
+	}
+
+	// 密码类型的参数加密后保存
+	p.secretKeys = secretManager.SecretKeys(p.appDetail)
+	storedParams := make(map[string]interface{}, len(p.req.Params))
+	for key, value := range p.req.Params {
+		storedParams[key] = value
+	}
+	if err = secretManager.EncryptParams(storedParams, p.secretKeys); err != nil {
+		return err
+	}
+	paramJson, err := json.Marshal(storedParams)
+	if err != nil {
+		log.Error("参数序列化失败:", err)
+		return err
 	}
 
 	if err = p.genEnv(); err != nil {
@@ -293,6 +380,8 @@ func (p *AppInstallProcess) ValidateParam() error {
 		Key:           p.app.Key,
 		Status:        model.PluginStatusInstalling,
 		IpAddress:     p.ipAddress,
+		Ip6Address:    p.ip6Address,
+		Domain:        p.req.Domain,
 	}
 	// 更新插件状态
 	err = repo.DB.Transaction(func(tx *gorm.DB) error {
@@ -300,20 +389,90 @@ func (p *AppInstallProcess) ValidateParam() error {
 		if err != nil {
 			return err
 		}
+		if p.replaceID != 0 {
+			return p.replaceInstalled(tx)
+		}
 		err = repo.Use(tx).AppInstalled.Create(p.appInstalled)
 		if err != nil {
 			return err
 		}
+		owner := IPOwner{
+			Type:      model.IPOwnerInstallation,
+			ID:        p.appInstalled.ID,
+			Name:      p.containerName,
+			InstallID: p.appInstalled.ID,
+		}
+		for _, ip := range []string{p.ipAddress, p.ip6Address} {
+			if ip == "" {
+				continue
+			}
+			if err = ipAllocationManager.Bind(tx, ip, owner); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		log.Error("更新应用状态失败:", err)
 		return err
 	}
+	if len(p.releasedIPs) > 0 {
+		ipAllocationManager.ReleaseMemory(p.releasedIPs)
+	}
+	recordAppStatus(p.appInstalled, model.PluginStatusInstalling, "")
 	log.Info("参数验证完成")
 	return nil
 }
 
+// replaceInstalled 在事务中用新的参数更新原安装记录，安装记录ID不变，
+// 备份计划、重启策略、状态变化记录、Nginx配置版本与访问控制配置都会保留
+func (p *AppInstallProcess) replaceInstalled(tx *gorm.DB) error {
+	q := repo.Use(tx).AppInstalled
+	p.appInstalled.Message = ""
+	_, err := q.Where(q.ID.Eq(p.replaceID)).Select(
+		q.Name, q.AppDetailID, q.Class, q.Repo, q.Version, q.Params, q.Env, q.DockerCompose,
+		q.Status, q.Message, q.IpAddress, q.Ip6Address, q.Domain,
+	).Updates(p.appInstalled)
+	if err != nil {
+		return err
+	}
+	p.appInstalled, err = q.Where(q.ID.Eq(p.replaceID)).First()
+	if err != nil {
+		return err
+	}
+	// 服务信息在安装时重新创建
+	_, err = repo.Use(tx).AppServiceStatus.Where(repo.AppServiceStatus.InstallID.Eq(p.replaceID)).Delete()
+	if err != nil {
+		return err
+	}
+	// 释放原安装占用且恢复后不再使用的IP，继续使用的IP重新绑定
+	released, err := ipAllocationManager.ReleaseByInstall(tx, p.replaceID)
+	if err != nil {
+		return err
+	}
+	p.releasedIPs = p.releasedIPs[:0]
+	for _, ip := range released {
+		if ip != p.ipAddress && ip != p.ip6Address {
+			p.releasedIPs = append(p.releasedIPs, ip)
+		}
+	}
+	owner := IPOwner{
+		Type:      model.IPOwnerInstallation,
+		ID:        p.replaceID,
+		Name:      p.containerName,
+		InstallID: p.replaceID,
+	}
+	for _, ip := range []string{p.ipAddress, p.ip6Address} {
+		if ip == "" {
+			continue
+		}
+		if err = ipAllocationManager.Bind(tx, ip, owner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Install 执行安装
 func (p *AppInstallProcess) Install() error {
 	log.Info("开始安装应用:", p.app.Name)
@@ -328,6 +487,9 @@ func (p *AppInstallProcess) Install() error {
 		IPAddress := []string{}
 		for _, network := range service.Networks {
 			IPAddress = append(IPAddress, network.IPAddress)
+			if network.IPv6Address != "" {
+				IPAddress = append(IPAddress, network.IPv6Address)
+			}
 		}
 		appService := model.AppServiceStatus{
 			ServiceName:   name,
@@ -340,6 +502,23 @@ func (p *AppInstallProcess) Install() error {
 		appServiceList = append(appServiceList, &appService)
 	}
 	repo.AppServiceStatus.Create(appServiceList...)
+	// 记录其他服务使用的IP
+	for _, appService := range appServiceList {
+		for _, ip := range splitIPs(appService.IpAddress) {
+			if ip == p.ipAddress || ip == p.ip6Address {
+				continue
+			}
+			err = ipAllocationManager.Claim(ip, IPOwner{
+				Type:      model.IPOwnerService,
+				ID:        appService.ID,
+				Name:      appService.ContainerName,
+				InstallID: p.appInstalled.ID,
+			})
+			if err != nil {
+				log.Warn("占用IP失败:", err)
+			}
+		}
+	}
 
 	err = pluginActionManager.Up(p.appInstalled, p.envContent)
 	if err != nil {
@@ -354,41 +533,9 @@ func (p *AppInstallProcess) Install() error {
 // 插件安装的时候，需要向Nginx添加一个配置，如果添加配置失败，会将插件停止
 func (p *AppInstallProcess) AddNginx() error {
 	log.Info("开始配置Nginx")
-	var err error
-	p.nm, err = nginx.NewNginxManager()
-	if err != nil {
-		log.Error("创建Nginx管理器失败:", err)
+	if err := pluginHelper.AddNginxLocation(p.client, p.appInstalled, p.appDetail); err != nil {
 		return err
 	}
-
-	port, err := p.client.GetImageFirstExposedPortByName(fmt.Sprintf("%s:%s", p.appDetail.Repo, p.appDetail.Version))
-	if err != nil {
-		log.Error("获取镜像端口失败:", err)
-		return err
-	}
-
-	if p.appDetail.NginxConfig != "" {
-		log.Info("添加Nginx location配置")
-		err = p.nm.AddLocation(nginx.NewLocationConfig(p.app.Key, p.containerName).WithTemplate(p.appDetail.NginxConfig).WithPort(port))
-
-		if err != nil {
-			log.Error("添加Nginx配置失败:", err)
-
-			std, err := compose.Operate(pluginHelper.GetComposeFile(p.appKey), "stop")
-			if err != nil {
-				log.Error("停止容器失败:", std, err)
-			}
-			_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(p.appInstalled.ID)).Update(repo.AppInstalled.Status, model.PluginStatusUpErr)
-			return err
-		}
-
-		// 提取location
-		locations, _ := p.nm.ExtractLocationsByKey(p.app.Key)
-
-		if len(locations) > 0 {
-			_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(p.appInstalled.ID)).Update(repo.AppInstalled.Location, locations[0])
-		}
-	}
 	log.Info("Nginx配置完成")
 	return nil
 }
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/archive"
+	"doo-store/backend/utils/common"
+	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/docker"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AppRestoreProcess 从备份归档恢复插件
+// 恢复复用安装流程：重新生成环境变量、创建安装记录、启动容器并添加Nginx配置
+type AppRestoreProcess struct {
+	archiveFile string
+	prefix      string // 备份内容在归档中的目录，整站导出的归档中每个插件位于单独的目录
+	manifest    *dto.BackupManifest
+	app         *model.App
+	appDetail   *model.AppDetail
+	install     *AppInstallProcess
+	replace     *model.AppInstalled // 原地恢复时被替换的安装记录
+}
+
+// NewAppRestoreProcess 创建新的插件恢复流程实例
+func NewAppRestoreProcess(archiveFile string) *AppRestoreProcess {
+	return &AppRestoreProcess{
+		archiveFile: archiveFile,
+	}
+}
+
+// WithPrefix 设置备份内容在归档中的目录
+func (p *AppRestoreProcess) WithPrefix(prefix string) *AppRestoreProcess {
+	p.prefix = prefix
+	return p
+}
+
+// ReplaceInstalled 在已有的安装记录上原地恢复，需要在 AllocateIP 之前调用
+// 安装记录在恢复完成前保留，备份计划、重启策略、状态变化记录、Nginx配置版本、独立域名与访问控制配置不会丢失
+func (p *AppRestoreProcess) ReplaceInstalled(appInstalled *model.AppInstalled) *AppRestoreProcess {
+	p.replace = appInstalled
+	return p
+}
+
+// Manifest 获取备份清单
+func (p *AppRestoreProcess) Manifest() *dto.BackupManifest {
+	return p.manifest
+}
+
+// LoadManifest 读取备份清单，并与当前插件目录中的版本进行校验
+func (p *AppRestoreProcess) LoadManifest() error {
+	log.Info("读取备份清单:", p.archiveFile)
+	data, err := archive.ReadFile(p.archiveFile, path.Join(p.prefix, dto.BackupManifestFile))
+	if err != nil {
+		log.Error("读取备份清单失败:", err)
+		return errors.New(constant.ErrBackupManifestInvalid)
+	}
+	manifest := &dto.BackupManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		log.Error("解析备份清单失败:", err)
+		return errors.New(constant.ErrBackupManifestInvalid)
+	}
+	if manifest.FormatVersion <= 0 || manifest.FormatVersion > dto.BackupFormatVersion || manifest.Key == "" || manifest.DockerCompose == "" {
+		log.Error("备份清单格式不支持:", manifest.FormatVersion, manifest.Key)
+		return errors.New(constant.ErrBackupManifestInvalid)
+	}
+	p.manifest = manifest
+
+	p.app, err = repo.App.Where(repo.App.Key.Eq(manifest.Key)).First()
+	if err != nil {
+		log.Error("查询应用信息失败:", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	p.appDetail, err = repo.AppDetail.Where(repo.AppDetail.AppID.Eq(p.app.ID)).First()
+	if err != nil {
+		log.Error("查询应用详细信息失败:", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	if p.appDetail.Version != manifest.Version {
+		log.Warnf("备份版本 %s 与当前版本 %s 不一致", manifest.Version, p.appDetail.Version)
+		return errors.New(constant.ErrBackupVersionMismatch)
+	}
+	return nil
+}
+
+// ManifestRemapper 替换备份清单中与主机相关的值
+type ManifestRemapper interface {
+	RemapIP(ip string) string
+	RemapParams(params string) (string, error)
+	RemapDockerCompose(content string) (string, error)
+}
+
+// RemapManifest 替换备份清单中与主机相关的值（IP地址、参数、docker-compose），需要在 AllocateIP 之前调用
+func (p *AppRestoreProcess) RemapManifest(r ManifestRemapper) error {
+	params, err := r.RemapParams(p.manifest.Params)
+	if err != nil {
+		log.Error("替换备份参数失败:", err)
+		return errors.New(constant.ErrBackupManifestInvalid)
+	}
+	dockerCompose, err := r.RemapDockerCompose(p.manifest.DockerCompose)
+	if err != nil {
+		log.Error("替换 docker-compose 失败:", err)
+		return errors.New(constant.ErrBackupManifestInvalid)
+	}
+	p.manifest.IpAddress = r.RemapIP(p.manifest.IpAddress)
+	p.manifest.Params = params
+	p.manifest.DockerCompose = dockerCompose
+	return nil
+}
+
+// AppInstalled 获取恢复后的安装记录，需要在 Restore 之后调用
+func (p *AppRestoreProcess) AppInstalled() *model.AppInstalled {
+	if p.install == nil {
+		return nil
+	}
+	return p.install.appInstalled
+}
+
+// CheckNotInstalled 检查插件是否已安装，已安装的插件需要先卸载
+func (p *AppRestoreProcess) CheckNotInstalled() error {
+	count, err := repo.AppInstalled.Where(repo.AppInstalled.AppID.Eq(p.app.ID)).Count()
+	if err != nil {
+		log.Error("查询已安装应用失败:", err)
+		return errors.New(constant.ErrRestoreFailed)
+	}
+	if count > 0 {
+		return errors.New(constant.ErrBackupPluginInstalled)
+	}
+	return nil
+}
+
+// AllocateIP 分配IP地址，优先复用备份时的IP
+func (p *AppRestoreProcess) AllocateIP() error {
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return err
+	}
+
+	params := map[string]interface{}{}
+	if p.manifest.Params != "" {
+		if err := json.Unmarshal([]byte(p.manifest.Params), &params); err != nil {
+			log.Error("解析备份参数失败:", err)
+			return errors.New(constant.ErrBackupManifestInvalid)
+		}
+	}
+	// 安装流程会重新加密敏感参数
+	if err := secretManager.DecryptParams(params); err != nil {
+		return err
+	}
+
+	// 原地恢复时原安装占用的IP可以直接复用
+	owner := IPOwner{Type: model.IPOwnerInstallation, Name: p.manifest.Key}
+	domain := p.manifest.Domain
+	if p.replace != nil {
+		owner.InstallID = p.replace.ID
+		domain = p.replace.Domain
+	}
+	ipAddress, err := reuseIP(p.manifest.IpAddress, owner, ipAllocationManager.Allocate)
+	if err != nil {
+		return err
+	}
+	ip6Address, err := reuseIP(p.manifest.Ip6Address, owner, ipAllocationManager.Allocate6)
+	if err != nil {
+		p.releaseReservedIP(ipAddress)
+		return err
+	}
+	log.Info("恢复使用的IP:", ipAddress, " ", ip6Address)
+
+	p.install = &AppInstallProcess{
+		ctx: dto.ServiceContext{},
+		req: request.AppInstall{
+			Key:           p.manifest.Key,
+			DockerCompose: p.manifest.DockerCompose,
+			CPUS:          fmt.Sprintf("%v", params[constant.CPUS]),
+			MemoryLimit:   fmt.Sprintf("%v", params[constant.MemoryLimit]),
+			Params:        params,
+			Domain:        domain,
+		},
+		app:        p.app,
+		appDetail:  p.appDetail,
+		ipAddress:  ipAddress,
+		ip6Address: ip6Address,
+		client:     client,
+	}
+	if p.replace != nil {
+		p.install.replaceID = p.replace.ID
+	}
+	return nil
+}
+
+// releaseReservedIP 恢复失败时释放为恢复分配的IP，原安装仍在使用的IP不会被释放
+func (p *AppRestoreProcess) releaseReservedIP(ips ...string) {
+	for _, ip := range ips {
+		if ip == "" || (p.replace != nil && (ip == p.replace.IpAddress || ip == p.replace.Ip6Address)) {
+			continue
+		}
+		_ = ipAllocationManager.Release(ip)
+	}
+}
+
+// reuseIP 优先复用备份时的IP，IP不属于当前网段或已被占用时重新分配
+func reuseIP(ip string, owner IPOwner, allocate func(IPOwner) (string, error)) (string, error) {
+	if ip != "" && docker.AllocatorFor(ip) != nil {
+		if err := ipAllocationManager.Claim(ip, owner); err != nil {
+			log.Info("无法复用备份时的IP, 重新分配:", err)
+		} else {
+			return ip, nil
+		}
+	}
+	return allocate(owner)
+}
+
+// Restore 执行恢复，需要先调用 LoadManifest 与 AllocateIP，调用方需要在整个恢复期间持有插件的备份锁
+func (p *AppRestoreProcess) Restore() error {
+	log.Info("开始恢复插件:", p.manifest.Key)
+	if p.replace != nil {
+		if err := p.stopReplaced(); err != nil {
+			log.Error("停止原插件失败:", err)
+			p.releaseReservedIP(p.install.ipAddress, p.install.ip6Address)
+			insertLog(p.replace.ID, "插件恢复", err.Error())
+			return errors.New(constant.ErrRestoreFailed)
+		}
+	}
+	if err := p.install.ValidateParam(); err != nil {
+		p.releaseReservedIP(p.install.ipAddress, p.install.ip6Address)
+		if p.replace != nil {
+			p.markFailed(p.replace, err)
+		}
+		return err
+	}
+	appInstalled := p.install.appInstalled
+
+	if err := p.restoreFiles(); err != nil {
+		log.Error("恢复插件数据失败:", err)
+		p.markFailed(appInstalled, err)
+		return errors.New(constant.ErrRestoreFailed)
+	}
+
+	if err := p.install.Install(); err != nil {
+		return err
+	}
+	if err := p.install.AddNginx(); err != nil {
+		return err
+	}
+	insertLog(appInstalled.ID, "插件恢复", filepath.Base(p.archiveFile))
+	log.Info("插件恢复完成")
+	return nil
+}
+
+// Run 同步执行完整的恢复流程
+func (p *AppRestoreProcess) Run() error {
+	if err := p.LoadManifest(); err != nil {
+		return err
+	}
+	if err := p.CheckNotInstalled(); err != nil {
+		return err
+	}
+	if err := p.AllocateIP(); err != nil {
+		return err
+	}
+	return p.Restore()
+}
+
+// stopReplaced 停止原安装的容器并清空工作目录，命名卷中的数据在导入前清空
+// 停止期间插件处于安装中状态，容器退出不会触发自动重启
+func (p *AppRestoreProcess) stopReplaced() error {
+	_, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(p.replace.ID)).Update(repo.AppInstalled.Status, model.PluginStatusInstalling)
+	if err != nil {
+		return err
+	}
+	appKey, composeFile := pluginHelper.GetAppKeyAndComposeFile(p.replace.Key)
+	if p.replace.Status != model.PluginStatusUpErr {
+		if stdout, err := compose.Down(composeFile); err != nil {
+			_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(p.replace.ID)).Update(repo.AppInstalled.Status, p.replace.Status)
+			return fmt.Errorf("docker compose down failed: %s %w", stdout, err)
+		}
+	}
+	recordAppStatus(p.replace, model.PluginStatusInstalling, "")
+	return os.RemoveAll(path.Join(constant.AppInstallDir, appKey))
+}
+
+// markFailed 将插件标记为启动失败并记录日志
+func (p *AppRestoreProcess) markFailed(appInstalled *model.AppInstalled, err error) {
+	_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Updates(
+		model.AppInstalled{
+			Status:  model.PluginStatusUpErr,
+			Message: err.Error(),
+		},
+	)
+	recordAppStatus(appInstalled, model.PluginStatusUpErr, err.Error())
+	insertLog(appInstalled.ID, "插件恢复", err.Error())
+}
+
+// restoreFiles 恢复插件工作目录与命名卷
+func (p *AppRestoreProcess) restoreFiles() error {
+	workspaceDir := path.Join(constant.AppInstallDir, p.install.appKey)
+	if err := archive.Extract(p.archiveFile, path.Join(p.prefix, dto.BackupWorkspaceDir), workspaceDir); err != nil {
+		return err
+	}
+	if len(p.manifest.Volumes) == 0 {
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "doo-store-restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := archive.Extract(p.archiveFile, path.Join(p.prefix, dto.BackupVolumesDir), tmpDir); err != nil {
+		return err
+	}
+
+	projectName := compose.ProjectName(pluginHelper.GetComposeFile(p.manifest.Key))
+	volumes := p.install.finalDockerCompose.ExtractVolumes(projectName)
+	for _, key := range p.manifest.Volumes {
+		volumeName, ok := volumes[key]
+		if !ok {
+			log.Warnf("卷 %s 不在当前的 docker-compose 文件中，跳过恢复", key)
+			continue
+		}
+		volumeFile := path.Join(tmpDir, key+".tar.gz")
+		if !common.FileExists(volumeFile) {
+			log.Warnf("备份中缺少卷 %s 的数据，跳过恢复", key)
+			continue
+		}
+		log.Info("恢复卷数据:", volumeName)
+		err := docker.ImportVolume(volumeName, volumeFile, map[string]string{
+			"com.docker.compose.project": projectName,
+			"com.docker.compose.volume":  key,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
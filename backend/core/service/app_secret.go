@@ -0,0 +1,267 @@
+package service
+
+import (
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/response"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/common"
+	"doo-store/backend/utils/crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// SecretManager 插件敏感信息的加密、解密与掩码处理
+// 密码类型的表单字段与商店注入的敏感环境变量在数据库中加密保存，只在写入 .env 文件时解密
+type SecretManager struct {
+}
+
+var secretManager = SecretManager{}
+
+// NewSecretManager 创建敏感信息管理实例
+func NewSecretManager() SecretManager {
+	return secretManager
+}
+
+// SecretKeys 获取插件需要加密的参数名，包括密码类型的表单字段与商店注入的敏感环境变量
+func (m SecretManager) SecretKeys(appDetail *model.AppDetail) []string {
+	keys := append([]string{}, constant.StoreSecretEnvKeys...)
+	params := response.AppParams{}
+	if err := common.StrToStruct(appDetail.Params, &params); err != nil {
+		log.Info("错误解析Json", err)
+		return keys
+	}
+	return append(keys, dto.SecretEnvKeys(params.FormFields)...)
+}
+
+// EncryptParams 加密参数中 keys 对应的非空字符串值，params 中的值需要为明文
+// 以加密前缀开头的明文即使不是敏感信息也会被加密，保证保存的值中带有前缀的都可以解密
+func (m SecretManager) EncryptParams(params map[string]interface{}, keys []string) error {
+	for key, v := range params {
+		value, ok := v.(string)
+		if !ok || value == "" || (!common.InArray(key, keys) && !crypto.IsEncrypted(value)) {
+			continue
+		}
+		encrypted, err := crypto.Encrypt(value, config.EnvConfig.SecretKey())
+		if err != nil {
+			log.Error("加密参数失败:", err)
+			return errors.New(constant.ErrSecretEncryptFailed)
+		}
+		params[key] = encrypted
+	}
+	return nil
+}
+
+// EncryptEnv 加密环境变量中 keys 对应的非空值，env 中的值需要为明文，以加密前缀开头的值同样会被加密
+func (m SecretManager) EncryptEnv(env map[string]string, keys []string) error {
+	for key, value := range env {
+		if value == "" || (!common.InArray(key, keys) && !crypto.IsEncrypted(value)) {
+			continue
+		}
+		encrypted, err := crypto.Encrypt(value, config.EnvConfig.SecretKey())
+		if err != nil {
+			log.Error("加密环境变量失败:", err)
+			return errors.New(constant.ErrSecretEncryptFailed)
+		}
+		env[key] = encrypted
+	}
+	return nil
+}
+
+// DecryptParams 解密参数中所有已加密的值
+func (m SecretManager) DecryptParams(params map[string]interface{}) error {
+	for key, value := range params {
+		str, ok := value.(string)
+		if !ok || !crypto.IsEncrypted(str) {
+			continue
+		}
+		plain, err := crypto.Decrypt(str, config.EnvConfig.SecretKey())
+		if err != nil {
+			log.Errorf("解密参数 %s 失败: %v", key, err)
+			return errors.New(constant.ErrSecretDecryptFailed)
+		}
+		params[key] = plain
+	}
+	return nil
+}
+
+// DecryptJson 解密 JSON 对象字符串中所有已加密的值
+func (m SecretManager) DecryptJson(data string) (string, error) {
+	if data == "" {
+		return data, nil
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		log.Info("错误解析Json", err)
+		return "", errors.New(constant.ErrPluginParamParseFailed)
+	}
+	if err := m.DecryptParams(values); err != nil {
+		return "", err
+	}
+	result, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// MaskParams 将参数中 keys 对应的非空值替换为掩码，keys 取自 SecretKeys
+// 按字段类型而不是按密文前缀判断，尚未加密的敏感信息（升级前安装的插件）同样不会返回给前端
+func (m SecretManager) MaskParams(params map[string]interface{}, keys []string) {
+	for key, value := range params {
+		str, ok := value.(string)
+		if !ok || str == "" {
+			continue
+		}
+		if common.InArray(key, keys) || crypto.IsEncrypted(str) {
+			params[key] = constant.SecretMask
+		}
+	}
+}
+
+// MaskJson 将 JSON 对象字符串中 keys 对应的非空值替换为掩码
+func (m SecretManager) MaskJson(data string, keys []string) string {
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return data
+	}
+	m.MaskParams(values, keys)
+	masked, err := json.Marshal(values)
+	if err != nil {
+		return data
+	}
+	return string(masked)
+}
+
+// KeepUnchanged 将请求参数中值为掩码的项替换为已保存的原值（解密后）
+func (m SecretManager) KeepUnchanged(params map[string]interface{}, storedParams string) error {
+	stored := map[string]interface{}{}
+	if storedParams != "" {
+		if err := json.Unmarshal([]byte(storedParams), &stored); err != nil {
+			log.Info("解析已保存的参数失败", err)
+			return errors.New(constant.ErrPluginParamParseFailed)
+		}
+	}
+	for key, value := range params {
+		if str, ok := value.(string); !ok || str != constant.SecretMask {
+			continue
+		}
+		storedValue, ok := stored[key].(string)
+		if !ok {
+			delete(params, key)
+			continue
+		}
+		plain, err := crypto.Decrypt(storedValue, config.EnvConfig.SecretKey())
+		if err != nil {
+			log.Errorf("解密参数 %s 失败: %v", key, err)
+			return errors.New(constant.ErrSecretDecryptFailed)
+		}
+		params[key] = plain
+	}
+	return nil
+}
+
+// RotateKey 使用新密钥重新加密所有已安装插件的敏感信息以及证书私钥
+// 尚未加密的敏感信息（升级前安装的插件）也会一并加密
+func (m SecretManager) RotateKey(oldKey, newKey string) error {
+	if newKey == "" {
+		return crypto.ErrEmptyKey
+	}
+	installedList, err := repo.AppInstalled.Find()
+	if err != nil {
+		return err
+	}
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		for _, appInstalled := range installedList {
+			appDetail, err := repo.Use(tx).AppDetail.Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First()
+			if err != nil {
+				return fmt.Errorf("query app detail of %s failed: %w", appInstalled.Key, err)
+			}
+			keys := m.SecretKeys(appDetail)
+
+			params, err := m.rotateJson(appInstalled.Params, keys, oldKey, newKey)
+			if err != nil {
+				return fmt.Errorf("rotate params of %s failed: %w", appInstalled.Key, err)
+			}
+			env, err := m.rotateJson(appInstalled.Env, keys, oldKey, newKey)
+			if err != nil {
+				return fmt.Errorf("rotate env of %s failed: %w", appInstalled.Key, err)
+			}
+			_, err = repo.Use(tx).AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Updates(
+				map[string]interface{}{
+					repo.AppInstalled.Params.ColumnName().String(): params,
+					repo.AppInstalled.Env.ColumnName().String():    env,
+				},
+			)
+			if err != nil {
+				return err
+			}
+			log.Info("已重新加密插件敏感信息:", appInstalled.Key)
+		}
+
+		certificates, err := repo.Use(tx).Certificate.Where(repo.Certificate.Key.Neq("")).Find()
+		if err != nil {
+			return err
+		}
+		for _, certificate := range certificates {
+			key, err := crypto.Decrypt(certificate.Key, oldKey)
+			if err != nil {
+				return fmt.Errorf("decrypt key of certificate %s failed: %w", certificate.Domain, err)
+			}
+			if key, err = crypto.Encrypt(key, newKey); err != nil {
+				return fmt.Errorf("encrypt key of certificate %s failed: %w", certificate.Domain, err)
+			}
+			_, err = repo.Use(tx).Certificate.Where(repo.Certificate.ID.Eq(certificate.ID)).Update(repo.Certificate.Key, key)
+			if err != nil {
+				return err
+			}
+			log.Info("已重新加密证书私钥:", certificate.Domain)
+		}
+		return nil
+	})
+}
+
+// rotateJson 使用旧密钥解密 JSON 对象中已加密的值，再使用新密钥加密 keys 对应的值
+func (m SecretManager) rotateJson(data string, keys []string, oldKey, newKey string) (string, error) {
+	if data == "" {
+		return data, nil
+	}
+	values := map[string]interface{}{}
+	err := json.Unmarshal([]byte(data), &values)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		encrypted := crypto.IsEncrypted(str)
+		if !encrypted && !common.InArray(key, keys) {
+			continue
+		}
+		if encrypted {
+			if str, err = crypto.Decrypt(str, oldKey); err != nil {
+				return "", err
+			}
+		}
+		if str == "" {
+			values[key] = str
+			continue
+		}
+		if values[key], err = crypto.Encrypt(str, newKey); err != nil {
+			return "", err
+		}
+	}
+	result, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
@@ -12,6 +12,7 @@ import (
 	"doo-store/backend/task"
 	"doo-store/backend/utils/common"
 	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/cron"
 	"doo-store/backend/utils/docker"
 	"doo-store/backend/utils/nginx"
 	"doo-store/backend/utils/redis"
@@ -20,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -48,6 +50,13 @@ type IAppService interface {
 	UploadApp(ctx dto.ServiceContext, req request.PluginUpload) error
 	GetInstalledAppInfo(ctx dto.ServiceContext, req request.GetInstalledPluginInfo) (*response.GetInstalledPluginInfoResp, error)
 	ListRunningAppKeys(ctx dto.ServiceContext) (any, error)
+	ListAppBackups(ctx dto.ServiceContext, id int64) ([]*response.AppBackupFile, error)
+	RestoreApp(ctx dto.ServiceContext, req request.AppRestore) error
+	GetBackupSchedule(ctx dto.ServiceContext, id int64) (*model.AppBackupSchedule, error)
+	UpdateBackupSchedule(ctx dto.ServiceContext, req request.AppBackupSchedule) (*model.AppBackupSchedule, error)
+	GetAppHealth(ctx dto.ServiceContext, id int64) (*response.AppHealth, error)
+	GetRestartPolicy(ctx dto.ServiceContext, id int64) (*model.AppRestartPolicy, error)
+	UpdateRestartPolicy(ctx dto.ServiceContext, req request.AppRestartPolicy) (*model.AppRestartPolicy, error)
 }
 
 func NewIAppService() IAppService {
@@ -79,6 +88,9 @@ func (*AppService) ListApps(ctx dto.ServiceContext, req request.AppSearch) (*dto
 	if err != nil {
 		return nil, err
 	}
+	for _, app := range result {
+		app.Localize(ctx.Language)
+	}
 
 	pageResult := &dto.PageResult{
 		Total: count,
@@ -102,6 +114,7 @@ func (*AppService) GetAppDetail(ctx dto.ServiceContext, key string) (*response.A
 	if err != nil {
 		return nil, err
 	}
+	dto.LocalizeFormFields(params.FormFields, ctx.Language)
 	resp := &response.AppDetail{
 		AppDetail: *appDetail,
 		Params:    params,
@@ -121,6 +134,7 @@ func (*AppService) InstallApp(ctx dto.ServiceContext, req request.AppInstall) er
 		return err
 	}
 	if err := appInstallProcess.ValidateParam(); err != nil {
+		appInstallProcess.ReleaseIP()
 		return err
 	}
 	// 异步处理
@@ -144,7 +158,7 @@ func (*AppService) UpdateAppInstall(ctx dto.ServiceContext, req request.AppInsta
 		return err
 	}
 
-	supportActions := []string{"start", "stop"}
+	supportActions := []string{"start", "stop", "backup"}
 	if !common.InArray(req.Action, supportActions) {
 		return errors.New(constant.ErrPluginUnsupportedAction)
 	}
@@ -156,6 +170,9 @@ func (*AppService) UpdateAppInstall(ctx dto.ServiceContext, req request.AppInsta
 	case model.PluginActionStart:
 		err = pluginActionManager.Start(appInstalled)
 		return err
+	case model.PluginActionBackup:
+		_, err = appBackupManager.Backup(appInstalled, false)
+		return err
 	default:
 		return errors.New(constant.ErrPluginUnsupportedAction)
 	}
@@ -181,14 +198,21 @@ func (*AppService) UninstallApp(ctx dto.ServiceContext, req request.AppUnInstall
 			log.Info("更新插件状态失败", err)
 			return err
 		}
-		// TODO 获取使用的IP信息
-		ipAddress, err := repo.Use(tx).AppServiceStatus.Select(repo.AppServiceStatus.IpAddress).Where((repo.AppServiceStatus.InstallID.Eq(appInstalled.ID))).Find()
+		// 释放插件占用的IP
+		usedIPAddress, err = ipAllocationManager.ReleaseByInstall(tx, appInstalled.ID)
+		if err != nil {
+			log.Info("释放IP失败", err)
+			return err
+		}
+		_, err = repo.Use(tx).AppBackupSchedule.Where(repo.AppBackupSchedule.AppInstalledId.Eq(appInstalled.ID)).Delete()
 		if err != nil {
-			log.Info("获取使用的IP信息失败", err)
+			log.Info("删除备份计划失败", err)
 			return err
 		}
-		for _, ip := range ipAddress {
-			usedIPAddress = append(usedIPAddress, strings.Split(ip.IpAddress, ",")...)
+		_, err = repo.Use(tx).NginxLocationVersion.Where(repo.NginxLocationVersion.AppInstalledId.Eq(appInstalled.ID)).Delete()
+		if err != nil {
+			log.Info("删除Nginx配置版本失败", err)
+			return err
 		}
 		// TODO 删除服务信息
 		_, err = repo.Use(tx).AppServiceStatus.Where((repo.AppServiceStatus.InstallID.Eq(appInstalled.ID))).Delete()
@@ -196,6 +220,16 @@ func (*AppService) UninstallApp(ctx dto.ServiceContext, req request.AppUnInstall
 			log.Info("删除服务信息失败", err)
 			return err
 		}
+		_, err = repo.Use(tx).AppRestartPolicy.Where(repo.AppRestartPolicy.AppInstalledId.Eq(appInstalled.ID)).Delete()
+		if err != nil {
+			log.Info("删除重启策略失败", err)
+			return err
+		}
+		_, err = repo.Use(tx).AppStatusTransition.Where(repo.AppStatusTransition.InstallID.Eq(appInstalled.ID)).Delete()
+		if err != nil {
+			log.Info("删除状态变化记录失败", err)
+			return err
+		}
 		if appInstalled.Status != model.PluginStatusUpErr {
 			stdout, err := compose.Down(composeFile)
 			if err != nil {
@@ -211,9 +245,7 @@ func (*AppService) UninstallApp(ctx dto.ServiceContext, req request.AppUnInstall
 		return errors.New(constant.ErrPluginUninstallFailed)
 	}
 	// 释放IP
-	for _, ip := range usedIPAddress {
-		docker.GlobalIPAllocator.ReleaseIP(ip)
-	}
+	ipAllocationManager.ReleaseMemory(usedIPAddress)
 	nm, err := nginx.NewNginxManager()
 	if err != nil {
 		return err
@@ -248,6 +280,24 @@ func (*AppService) ListInstalledApps(ctx dto.ServiceContext, req request.AppInst
 		log.Info("查询已安装插件失败", err)
 		return nil, errors.New(constant.ErrPluginInfoFailed)
 	}
+	// 敏感信息不返回给前端
+	secretKeys := map[int64][]string{}
+	for _, item := range result {
+		detailID, _ := strconv.ParseInt(fmt.Sprint(item[repo.AppInstalled.AppDetailID.ColumnName().String()]), 10, 64)
+		keys, ok := secretKeys[detailID]
+		if !ok {
+			keys = constant.StoreSecretEnvKeys
+			if appDetail, err := repo.AppDetail.Where(repo.AppDetail.ID.Eq(detailID)).First(); err == nil {
+				keys = secretManager.SecretKeys(appDetail)
+			}
+			secretKeys[detailID] = keys
+		}
+		for _, column := range []string{repo.AppInstalled.Params.ColumnName().String(), repo.AppInstalled.Env.ColumnName().String()} {
+			if value, ok := item[column].(string); ok {
+				item[column] = secretManager.MaskJson(value, keys)
+			}
+		}
+	}
 
 	pageResult := &dto.PageResult{
 		Total: count,
@@ -281,11 +331,13 @@ func (*AppService) GetAppParams(ctx dto.ServiceContext, id int64) (any, error) {
 		log.Info("解析环境变量失败", err)
 		return nil, err
 	}
+	secretManager.MaskParams(env, secretManager.SecretKeys(appDetail))
 	// for _, formField := range params.FormFields {
 	// 	formField.Value = env[formField.EnvKey]
 	// 	formField.Key = formField.EnvKey
 	// }
 	params.FormFields = dto.FillAndValidateForm(params.FormFields, env)
+	dto.LocalizeFormFields(params.FormFields, ctx.Language)
 	// 构建插件参数
 	aParams := response.AppInstalledParamsResp{
 		Params:        params.FormFields,
@@ -315,7 +367,13 @@ func (*AppService) UpdateAppParams(ctx dto.ServiceContext, req request.AppInstal
 		log.Info("错误解析Json", err)
 		return nil, err
 	}
-	// TODO 参数校验
+	// 只允许修改清单中声明的参数
+	if req.Params == nil {
+		req.Params = map[string]interface{}{}
+	}
+	if err := pluginHelper.CheckParamKeys(ctx.C, params.FormFields, req.Params); err != nil {
+		return nil, err
+	}
 	appKey := pluginHelper.GetAppKey(appInstalled.Key)
 	containerName := appInstalled.Name
 	ipAddress := appInstalled.IpAddress
@@ -331,21 +389,64 @@ func (*AppService) UpdateAppParams(ctx dto.ServiceContext, req request.AppInstal
 		req.Params[constant.MemoryLimit] = req.MemoryLimit + req.MemoryUnit
 	}
 
-	// TODO 参数更新
+	// 值为掩码的敏感参数保持原值不变
+	if err := secretManager.KeepUnchanged(req.Params, appInstalled.Params); err != nil {
+		return nil, err
+	}
+	secretKeys := secretManager.SecretKeys(appDetail)
+
+	// 只读与隐藏的字段保持原值不变
+	if err := pluginHelper.KeepProtectedValues(params.FormFields, req.Params, appInstalled.Params); err != nil {
+		return nil, err
+	}
+
+	// 自动生成的字段沿用已保存的值，只有显式要求时才重新生成
+	if err := pluginHelper.KeepGeneratedValues(params.FormFields, req.Params, appInstalled.Params); err != nil {
+		return nil, err
+	}
+	if len(req.Regenerate) > 0 {
+		client, err := docker.NewClient()
+		if err != nil {
+			log.Error("创建Docker客户端失败:", err)
+			return nil, err
+		}
+		defer client.Close()
+		err = dto.GenerateFieldValues(params.FormFields, req.Params, req.Regenerate, pluginHelper.NewPortAllocator(client))
+		if err != nil {
+			log.Info("生成参数失败", err)
+			return nil, errors.New(constant.ErrPluginParamGenerateFailed)
+		}
+	}
+
+	dto.LocalizeFormFields(params.FormFields, ctx.Language)
+	if vErrs := dto.ValidateFormData(params.FormFields, req.Params, params.Rules); len(vErrs) > 0 {
+		log.Warn("参数验证失败:", vErrs)
+		return nil, vErrs
+	}
 
 	envContent, envJson, err := pluginHelper.GenEnv(schemasReq.GenEnvReq{
 		AppKey:        appKey,
 		ContainerName: containerName,
 		IPAddress:     ipAddress,
+		IP6Address:    appInstalled.Ip6Address,
 		Envs:          req.Params,
+		Fields:        params.FormFields,
+		SecretKeys:    secretKeys,
 		WriteFile:     false,
 	})
 	if err != nil {
 		log.Info("错误生成环境变量文件", err)
 		return nil, errors.New(constant.ErrPluginModifyParamFailed)
 	}
+	if err = pluginHelper.WriteFieldFiles(appKey, params.FormFields, req.Params); err != nil {
+		log.Info("写入文件参数失败", err)
+		return nil, errors.New(constant.ErrPluginModifyParamFailed)
+	}
 
 	appInstalled.Env = envJson
+	if err := secretManager.EncryptParams(req.Params, secretKeys); err != nil {
+		return nil, err
+	}
 	paramJson, err := json.Marshal(req.Params)
 	if err != nil {
 		return nil, errors.New(constant.ErrPluginParamParseFailed)
@@ -365,12 +466,14 @@ func (*AppService) UpdateAppParams(ctx dto.ServiceContext, req request.AppInstal
 		log.Info("解析环境变量失败", err)
 		return nil, err
 	}
+	secretManager.MaskParams(env, secretKeys)
 	// for _, formField := range params.FormFields {
 	// 	formField.Value = env[formField.EnvKey]
 	// 	formField.Key = formField.EnvKey
 	// }
 
 	params.FormFields = dto.FillAndValidateForm(params.FormFields, env)
+	dto.LocalizeFormFields(params.FormFields, ctx.Language)
 	aParams := response.AppInstalledParamsResp{
 		Params:        params.FormFields,
 		DockerCompose: appInstalled.DockerCompose,
@@ -411,7 +514,7 @@ func (*AppService) GetAppLogs(ctx dto.ServiceContext, req request.AppLogsSearch)
 	}
 
 	// 校验插件状态
-	if appInstalled.Status != model.PluginStatusRunning {
+	if !appInstalled.IsRunning() {
 		return nil, errors.New(constant.ErrPluginNotRunning)
 	}
 
@@ -480,13 +583,15 @@ func (AppService) UploadApp(ctx dto.ServiceContext, req request.PluginUpload) er
 	err = repo.DB.Transaction(func(tx *gorm.DB) error {
 
 		app := &model.App{
-			Name:           req.Plugin.Name,
-			Key:            req.Plugin.Key,
-			Icon:           req.Plugin.Icon,
-			Class:          req.Plugin.Class,
-			Description:    req.Plugin.Description,
-			DependsVersion: req.Plugin.DependsVersion,
-			Status:         model.AppUnused,
+			Name:            req.Plugin.Name,
+			Key:             req.Plugin.Key,
+			Icon:            req.Plugin.Icon,
+			Class:           req.Plugin.Class,
+			Description:     req.Plugin.Description,
+			NameI18n:        req.Plugin.NameI18n,
+			DescriptionI18n: req.Plugin.DescriptionI18n,
+			DependsVersion:  req.Plugin.DependsVersion,
+			Status:          model.AppUnused,
 		}
 		err := repo.Use(tx).App.Create(app)
 		if err != nil {
@@ -521,6 +626,7 @@ func (AppService) UploadApp(ctx dto.ServiceContext, req request.PluginUpload) er
 			Params:         req.Plugin.GenParams(),
 			DockerCompose:  dockerCompose,
 			NginxConfig:    nginxConfig,
+			HealthCheck:    req.Plugin.HealthCheck,
 			Status:         model.AppNormal,
 		}
 		err = repo.Use(tx).AppDetail.Create(appDetail)
@@ -537,8 +643,8 @@ func (AppService) UploadApp(ctx dto.ServiceContext, req request.PluginUpload) er
 }
 
 func (AppService) GetInstalledAppInfo(ctx dto.ServiceContext, req request.GetInstalledPluginInfo) (*response.GetInstalledPluginInfoResp, error) {
-	// 获取已安装并正常运行的插件信息
-	info, err := repo.AppInstalled.Where(repo.AppInstalled.Key.Eq(req.Key), repo.AppInstalled.Status.Eq(model.PluginStatusRunning)).First()
+	// 获取已安装且容器正在运行的插件信息，健康检查失败的插件同样在运行
+	info, err := repo.AppInstalled.Where(repo.AppInstalled.Key.Eq(req.Key), repo.AppInstalled.Status.In(model.PluginStatusRunning, model.PluginStatusUnHealthy)).First()
 	if err != nil {
 		log.Info("查询插件安装信息失败", err)
 		return nil, err
@@ -549,6 +655,9 @@ func (AppService) GetInstalledAppInfo(ctx dto.ServiceContext, req request.GetIns
 		Status:   info.Status,
 		Location: info.Location,
 	}
+	if nginx.DetectMode(currentNginxTemplate(info)) == nginx.LocationModeHost {
+		resp.Domain = pluginHelper.PluginDomain(info.Key, info.Domain)
+	}
 
 	// 获取云盘的provider
 	if req.Key == "doocloudisk" {
@@ -566,7 +675,7 @@ func (AppService) GetInstalledAppInfo(ctx dto.ServiceContext, req request.GetIns
 
 func (AppService) ListRunningAppKeys(ctx dto.ServiceContext) (any, error) {
 	result := []string{}
-	err := repo.AppInstalled.Select(repo.AppInstalled.Key).Where(repo.AppInstalled.Status.Eq(model.PluginStatusRunning)).Pluck(repo.AppInstalled.Key, &result)
+	err := repo.AppInstalled.Select(repo.AppInstalled.Key).Where(repo.AppInstalled.Status.In(model.PluginStatusRunning, model.PluginStatusUnHealthy)).Pluck(repo.AppInstalled.Key, &result)
 	if err != nil {
 		return []string{}, err
 	}
@@ -579,6 +688,152 @@ func (AppService) ListRunningAppKeys(ctx dto.ServiceContext) (any, error) {
 	return result, nil
 }
 
+// ListAppBackups 获取插件的备份文件列表
+func (*AppService) ListAppBackups(ctx dto.ServiceContext, id int64) ([]*response.AppBackupFile, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	return appBackupManager.List(appInstalled.Key)
+}
+
+// RestoreApp 从备份文件恢复插件，恢复在当前的安装记录上进行
+// 备份清单校验与IP分配在停止当前插件之前完成，任一步骤失败时当前插件保持不变
+// 恢复期间持有备份锁，备份、导出与其他恢复需要等待恢复完成
+func (s *AppService) RestoreApp(ctx dto.ServiceContext, req request.AppRestore) (err error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	archiveFile, err := appBackupManager.GetBackupFile(appInstalled.Key, req.File)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := appBackupManager.Lock(appInstalled.Key)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			unlock()
+		}
+	}()
+	// 加锁后重新读取状态，安装中的插件不能恢复
+	appInstalled, err = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	if appInstalled.Status == model.PluginStatusInstalling {
+		return errors.New(constant.ErrBackupPluginInstalling)
+	}
+
+	restoreProcess := NewAppRestoreProcess(archiveFile).ReplaceInstalled(appInstalled)
+	if err := restoreProcess.LoadManifest(); err != nil {
+		return err
+	}
+	if restoreProcess.Manifest().Key != appInstalled.Key {
+		return errors.New(constant.ErrBackupKeyMismatch)
+	}
+
+	if err := restoreProcess.AllocateIP(); err != nil {
+		return err
+	}
+
+	// 异步处理
+	manager := task.GetAsyncTaskManager()
+	manager.AddTask(func() error {
+		defer unlock()
+		return restoreProcess.Restore()
+	})
+	return nil
+}
+
+// GetAppHealth 获取插件的健康状态与最近的健康检查记录
+func (*AppService) GetAppHealth(ctx dto.ServiceContext, id int64) (*response.AppHealth, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	resp := &response.AppHealth{
+		Status:  appInstalled.Status,
+		Health:  appInstalled.Health,
+		Message: appInstalled.Message,
+		Log:     appInstalled.HealthLog,
+	}
+	if resp.Log == nil {
+		resp.Log = model.HealthLog{}
+	}
+	if appDetail, err := repo.AppDetail.Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First(); err == nil {
+		resp.HealthCheck = appDetail.HealthCheck
+	}
+	return resp, nil
+}
+
+// GetBackupSchedule 获取插件的定时备份计划，未配置时返回未启用的空计划
+func (*AppService) GetBackupSchedule(ctx dto.ServiceContext, id int64) (*model.AppBackupSchedule, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	schedule, err := repo.AppBackupSchedule.Where(repo.AppBackupSchedule.AppInstalledId.Eq(appInstalled.ID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.AppBackupSchedule{AppInstalledId: appInstalled.ID}, nil
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// UpdateBackupSchedule 更新插件的定时备份计划
+func (*AppService) UpdateBackupSchedule(ctx dto.ServiceContext, req request.AppBackupSchedule) (*model.AppBackupSchedule, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+
+	var nextRunAt *time.Time
+	if req.Enabled || req.Cron != "" {
+		cronSchedule, err := cron.Parse(req.Cron)
+		if err != nil {
+			log.Info("cron表达式解析失败", err)
+			return nil, errors.New(constant.ErrBackupCronInvalid)
+		}
+		next := cronSchedule.Next(time.Now())
+		if next.IsZero() {
+			return nil, errors.New(constant.ErrBackupCronInvalid)
+		}
+		if req.Enabled {
+			nextRunAt = &next
+		}
+	}
+
+	schedule, err := repo.AppBackupSchedule.Where(repo.AppBackupSchedule.AppInstalledId.Eq(appInstalled.ID)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if schedule == nil {
+		schedule = &model.AppBackupSchedule{AppInstalledId: appInstalled.ID}
+	}
+	schedule.Cron = req.Cron
+	schedule.Retention = req.Retention
+	schedule.Enabled = req.Enabled
+	schedule.NextRunAt = nextRunAt
+	if err := repo.AppBackupSchedule.Save(schedule); err != nil {
+		log.Info("保存备份计划失败", err)
+		return nil, err
+	}
+	insertLog(appInstalled.ID, "备份计划修改", fmt.Sprintf("%s, 保留%d个, 启用: %v", req.Cron, req.Retention, req.Enabled))
+	return schedule, nil
+}
+
 func createDir(dirPath string) error {
 	err := os.Mkdir(dirPath, 0755)
 	if err != nil {
@@ -591,6 +846,63 @@ func createDir(dirPath string) error {
 	return nil
 }
 
+// GetRestartPolicy 获取插件的自动重启策略，未配置时返回使用默认值且未启用的策略
+func (*AppService) GetRestartPolicy(ctx dto.ServiceContext, id int64) (*model.AppRestartPolicy, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	policy, err := repo.AppRestartPolicy.Where(repo.AppRestartPolicy.AppInstalledId.Eq(appInstalled.ID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.NewAppRestartPolicy(appInstalled.ID), nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateRestartPolicy 修改插件的自动重启策略
+func (*AppService) UpdateRestartPolicy(ctx dto.ServiceContext, req request.AppRestartPolicy) (*model.AppRestartPolicy, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+
+	policy, err := repo.AppRestartPolicy.Where(repo.AppRestartPolicy.AppInstalledId.Eq(appInstalled.ID)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if policy == nil {
+		policy = model.NewAppRestartPolicy(appInstalled.ID)
+	}
+	policy.Enabled = req.Enabled
+	// 启用时参数均为必填，关闭时未传的参数保持原值
+	if req.MaxRestarts > 0 {
+		policy.MaxRestarts = req.MaxRestarts
+	}
+	if req.Window > 0 {
+		policy.Window = req.Window
+	}
+	if req.Backoff > 0 {
+		policy.Backoff = req.Backoff
+	}
+	if req.MaxBackoff > 0 {
+		policy.MaxBackoff = req.MaxBackoff
+	}
+	if err := repo.AppRestartPolicy.Save(policy); err != nil {
+		log.Info("保存重启策略失败", err)
+		return nil, err
+	}
+	if !policy.Enabled {
+		task.CancelRestart(appInstalled.ID)
+	}
+	insertLog(appInstalled.ID, "重启策略修改", fmt.Sprintf("%d分钟内最多重启%d次, 等待%d~%d秒, 启用: %v", policy.Window, policy.MaxRestarts, policy.Backoff, policy.MaxBackoff, policy.Enabled))
+	return policy, nil
+}
+
 func insertLog(appInstalledId int64, prefix, content string) {
 	if prefix == "" && content == "" {
 		log.Info("log content is empty")
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/task"
+	"doo-store/backend/utils/acme"
+	"doo-store/backend/utils/crypto"
+	e "doo-store/backend/utils/error"
+	"doo-store/backend/utils/nginx"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// certificateRenewBefore 证书在过期前多久开始自动续期
+const certificateRenewBefore = 30 * 24 * time.Hour
+
+// certificateIssueTimeout 一次ACME申请的超时时间
+const certificateIssueTimeout = 5 * time.Minute
+
+// certificateIssuing 正在申请中的证书，避免同一证书同时申请
+var certificateIssuing sync.Map
+
+// CertificateService 插件独立域名的证书管理
+type CertificateService struct {
+}
+
+type ICertificateService interface {
+	List(ctx dto.ServiceContext) ([]*model.Certificate, error)
+	Upload(ctx dto.ServiceContext, req request.CertificateUpload) (*model.Certificate, error)
+	Issue(ctx dto.ServiceContext, req request.CertificateIssue) (*model.Certificate, error)
+	Renew(ctx dto.ServiceContext, id int64) (*model.Certificate, error)
+	Delete(ctx dto.ServiceContext, id int64) error
+}
+
+func NewICertificateService() ICertificateService {
+	return &CertificateService{}
+}
+
+// List 获取所有证书，按过期时间排序
+func (*CertificateService) List(ctx dto.ServiceContext) ([]*model.Certificate, error) {
+	return repo.Certificate.Order(repo.Certificate.NotAfter).Find()
+}
+
+// Upload 上传证书与私钥，域名已有证书时替换原证书
+func (*CertificateService) Upload(ctx dto.ServiceContext, req request.CertificateUpload) (*model.Certificate, error) {
+	leaf, err := acme.ParseCertificate([]byte(req.Cert), []byte(req.Key))
+	if err != nil {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrCertificateInvalid, err.Error(), nil)
+	}
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	if domain == "" {
+		domain = acme.CertificateDomain(leaf)
+	}
+	if !validCertificateDomain(domain) {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrNginxDomainInvalid, domain, nil)
+	}
+	if err := leaf.VerifyHostname(domain); err != nil {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrCertificateInvalid, err.Error(), nil)
+	}
+
+	certificate, err := repo.Certificate.Where(repo.Certificate.Domain.Eq(domain)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if certificate == nil {
+		certificate = &model.Certificate{Domain: domain}
+	}
+	if _, issuing := certificateIssuing.Load(domain); issuing {
+		return nil, errors.New(constant.ErrCertificatePending)
+	}
+	certificate.Source = model.CertificateSourceUpload
+	certificate.AutoRenew = false
+	if err := installCertificate(certificate, []byte(req.Cert), []byte(req.Key), leaf); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// Issue 通过ACME的 HTTP-01 验证为插件的独立域名申请证书，申请在后台进行
+func (*CertificateService) Issue(ctx dto.ServiceContext, req request.CertificateIssue) (*model.Certificate, error) {
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	if strings.HasPrefix(domain, "*.") {
+		return nil, errors.New(constant.ErrCertificateWildcardACME)
+	}
+	if !validCertificateDomain(domain) {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrNginxDomainInvalid, domain, nil)
+	}
+	if !domainServedByPlugin(domain) {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrCertificateDomainUnused, domain, nil)
+	}
+
+	certificate, err := repo.Certificate.Where(repo.Certificate.Domain.Eq(domain)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if certificate == nil {
+		certificate = &model.Certificate{Domain: domain}
+	}
+	if certificate.Cert == "" {
+		certificate.Status = model.CertificateStatusPending
+	}
+	certificate.Source = model.CertificateSourceACME
+	certificate.AutoRenew = true
+	if err := repo.Certificate.Save(certificate); err != nil {
+		return nil, err
+	}
+	if err := issueCertificateAsync(certificate); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// Renew 立即续期通过ACME申请的证书
+func (*CertificateService) Renew(ctx dto.ServiceContext, id int64) (*model.Certificate, error) {
+	certificate, err := repo.Certificate.Where(repo.Certificate.ID.Eq(id)).First()
+	if err != nil {
+		return nil, errors.New(constant.ErrCertificateNotFound)
+	}
+	if certificate.Source != model.CertificateSourceACME {
+		return nil, errors.New(constant.ErrCertificateNotACME)
+	}
+	if err := issueCertificateAsync(certificate); err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// Delete 删除证书，先移除Nginx配置中对证书的引用，再删除Nginx容器中的证书文件
+func (*CertificateService) Delete(ctx dto.ServiceContext, id int64) error {
+	certificate, err := repo.Certificate.Where(repo.Certificate.ID.Eq(id)).First()
+	if err != nil {
+		return errors.New(constant.ErrCertificateNotFound)
+	}
+	if _, issuing := certificateIssuing.Load(certificate.Domain); issuing {
+		return errors.New(constant.ErrCertificatePending)
+	}
+	if _, err := repo.Certificate.Where(repo.Certificate.ID.Eq(id)).Delete(); err != nil {
+		return err
+	}
+	if certificate.Cert == "" {
+		return nil
+	}
+	if _, err := ReconcileNginx(); err != nil {
+		// 仍有配置引用证书时保留证书文件，避免Nginx配置检测失败
+		log.Warnf("删除证书 %s 后核对Nginx配置失败，保留证书文件: %v", certificate.Domain, err)
+		return nil
+	}
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return nil
+	}
+	if err := nm.RemoveCertificate(certificate.Domain); err != nil {
+		log.Warnf("删除证书 %s 的文件失败: %v", certificate.Domain, err)
+	}
+	return nil
+}
+
+// RenewCertificates 更新证书状态并续期即将过期的证书，由定时任务调用
+func RenewCertificates() error {
+	certificates, err := repo.Certificate.Where(repo.Certificate.Cert.Neq("")).Find()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, certificate := range certificates {
+		if certificate.NotAfter == nil {
+			continue
+		}
+		if certificate.NotAfter.Before(now) && certificate.Status != model.CertificateStatusExpired {
+			log.Warnf("域名 %s 的证书已于 %s 过期", certificate.Domain, certificate.NotAfter.Format(time.DateTime))
+			updateCertificate(certificate.ID, map[string]interface{}{
+				repo.Certificate.Status.ColumnName().String(): model.CertificateStatusExpired,
+			})
+		}
+		if certificate.NotAfter.Sub(now) > certificateRenewBefore {
+			continue
+		}
+		if !certificate.AutoRenew || certificate.Source != model.CertificateSourceACME {
+			log.Warnf("域名 %s 的证书将于 %s 过期，请重新上传", certificate.Domain, certificate.NotAfter.Format(time.DateTime))
+			continue
+		}
+		if _, issuing := certificateIssuing.LoadOrStore(certificate.Domain, struct{}{}); issuing {
+			continue
+		}
+		log.Infof("域名 %s 的证书将于 %s 过期，开始续期", certificate.Domain, certificate.NotAfter.Format(time.DateTime))
+		err := issueCertificate(certificate)
+		certificateIssuing.Delete(certificate.Domain)
+		if err != nil {
+			log.Errorf("续期域名 %s 的证书失败: %v", certificate.Domain, err)
+		}
+	}
+	return nil
+}
+
+// syncCertificates 将缺失的证书重新写入Nginx容器，Nginx容器重建后证书文件会丢失
+func syncCertificates(nm *nginx.NginxManager) {
+	certificates, err := repo.Certificate.Where(repo.Certificate.Cert.Neq("")).Find()
+	if err != nil {
+		log.Warnf("查询证书失败: %v", err)
+		return
+	}
+	for _, certificate := range certificates {
+		exists, err := nm.HasCertificate(certificate.Domain)
+		if err != nil || exists {
+			continue
+		}
+		key, err := crypto.Decrypt(certificate.Key, config.EnvConfig.SecretKey())
+		if err != nil {
+			log.Warnf("解密域名 %s 的私钥失败: %v", certificate.Domain, err)
+			continue
+		}
+		if err := nm.InstallCertificate(certificate.Domain, []byte(certificate.Cert), []byte(key), false); err != nil {
+			log.Warnf("写入域名 %s 的证书失败: %v", certificate.Domain, err)
+		}
+	}
+}
+
+// issueCertificateAsync 将证书申请加入异步队列，证书已在申请中时返回错误
+func issueCertificateAsync(certificate *model.Certificate) error {
+	if _, issuing := certificateIssuing.LoadOrStore(certificate.Domain, struct{}{}); issuing {
+		return errors.New(constant.ErrCertificatePending)
+	}
+	task.GetAsyncTaskManager().AddTask(func() error {
+		defer certificateIssuing.Delete(certificate.Domain)
+		return issueCertificate(certificate)
+	})
+	return nil
+}
+
+// issueCertificate 通过ACME申请证书并写入Nginx容器，失败时记录原因，已有的证书继续使用
+func issueCertificate(certificate *model.Certificate) error {
+	err := func() error {
+		nm, err := nginx.NewNginxManager()
+		if err != nil {
+			return err
+		}
+		client, err := acme.NewClient(
+			config.EnvConfig.ACME_DIRECTORY,
+			config.EnvConfig.ACME_EMAIL,
+			config.EnvConfig.ACME_CA_FILE,
+			path.Join(constant.ACMEDir, "account.key"),
+		)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), certificateIssueTimeout)
+		defer cancel()
+		certPEM, keyPEM, err := client.Obtain(ctx, certificate.Domain, nm)
+		if err != nil {
+			return err
+		}
+		leaf, err := acme.ParseCertificate(certPEM, keyPEM)
+		if err != nil {
+			return err
+		}
+		return installCertificate(certificate, certPEM, keyPEM, leaf)
+	}()
+	if err == nil {
+		log.Infof("域名 %s 的证书已签发，有效期至 %s", certificate.Domain, certificate.NotAfter.Format(time.DateTime))
+		return nil
+	}
+
+	log.Errorf("申请域名 %s 的证书失败: %v", certificate.Domain, err)
+	values := map[string]interface{}{
+		repo.Certificate.Message.ColumnName().String(): err.Error(),
+	}
+	if certificate.Cert == "" {
+		values[repo.Certificate.Status.ColumnName().String()] = model.CertificateStatusFailed
+	}
+	updateCertificate(certificate.ID, values)
+	return err
+}
+
+// installCertificate 将证书写入Nginx容器并保存，新证书保存后重新核对Nginx配置以启用HTTPS
+func installCertificate(certificate *model.Certificate, certPEM, keyPEM []byte, leaf *x509.Certificate) error {
+	encryptedKey, err := crypto.Encrypt(string(keyPEM), config.EnvConfig.SecretKey())
+	if err != nil {
+		log.Error("加密证书私钥失败:", err)
+		return errors.New(constant.ErrSecretEncryptFailed)
+	}
+
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return errors.New(constant.ErrCertificateInstallFailed)
+	}
+	// 已有证书时Nginx配置已引用证书文件，需要重新加载才能使用新证书
+	inUse := certificate.Cert != ""
+	if err := nm.InstallCertificate(certificate.Domain, certPEM, keyPEM, inUse); err != nil {
+		log.Error("写入证书失败:", err)
+		return errors.New(constant.ErrCertificateInstallFailed)
+	}
+
+	now := time.Now()
+	status := model.CertificateStatusValid
+	if leaf.NotAfter.Before(now) {
+		status = model.CertificateStatusExpired
+	}
+	certificate.Status = status
+	certificate.Cert = string(certPEM)
+	certificate.Key = encryptedKey
+	certificate.Issuer = leaf.Issuer.CommonName
+	certificate.NotBefore = &leaf.NotBefore
+	certificate.NotAfter = &leaf.NotAfter
+	certificate.RenewedAt = &now
+	certificate.Message = ""
+	if err := repo.Certificate.Save(certificate); err != nil {
+		return err
+	}
+
+	if !inUse {
+		if _, err := ReconcileNginx(); err != nil {
+			log.Warnf("启用域名 %s 的HTTPS失败: %v", certificate.Domain, err)
+		}
+	}
+	return nil
+}
+
+// validCertificateDomain 检查证书的域名格式，支持 *. 开头的通配符域名
+func validCertificateDomain(domain string) bool {
+	name := strings.TrimPrefix(domain, "*.")
+	return len(name) <= 253 && domainRegexp.MatchString(name)
+}
+
+// domainServedByPlugin 判断域名是否被使用独立域名的插件使用，HTTP-01 验证需要通过插件的 server 块访问
+func domainServedByPlugin(domain string) bool {
+	appInstalledList, err := repo.AppInstalled.Find()
+	if err != nil {
+		log.Warnf("查询已安装插件失败: %v", err)
+		return false
+	}
+	for _, appInstalled := range appInstalledList {
+		if appInstalled.Status == model.PluginStatusInstalling {
+			continue
+		}
+		if pluginHelper.PluginDomain(appInstalled.Key, appInstalled.Domain) != domain {
+			continue
+		}
+		if nginx.DetectMode(currentNginxTemplate(appInstalled)) == nginx.LocationModeHost {
+			return true
+		}
+	}
+	return false
+}
+
+func updateCertificate(id int64, values map[string]interface{}) {
+	if _, err := repo.Certificate.Where(repo.Certificate.ID.Eq(id)).Updates(values); err != nil {
+		log.Errorf("Failed to update certificate %d: %v", id, err)
+	}
+}
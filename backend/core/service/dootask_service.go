@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -19,6 +20,7 @@ type DootaskService struct {
 
 type IDootaskService interface {
 	GetUserInfo(token string) (*dto.UserInfoResp, error)
+	GetCachedUserInfo(token string) (*dto.UserInfoResp, error)
 	GetVersoinInfo() (*dto.VersionInfoResp, error)
 }
 
@@ -51,6 +53,51 @@ func (d *DootaskService) GetUserInfo(token string) (*dto.UserInfoResp, error) {
 	return userInfo, nil
 }
 
+// userInfoCacheTTL 用户信息的缓存时间，插件的每个请求都会校验登录状态
+const userInfoCacheTTL = time.Minute
+
+// userInfoCacheLimit 缓存数量超过该值时清理过期的缓存
+const userInfoCacheLimit = 4096
+
+type userInfoCacheEntry struct {
+	info      *dto.UserInfoResp
+	expiresAt time.Time
+}
+
+// userInfoCache 以 token 为键缓存校验通过的用户信息
+var userInfoCache = struct {
+	sync.Mutex
+	entries map[string]userInfoCacheEntry
+}{entries: map[string]userInfoCacheEntry{}}
+
+// GetCachedUserInfo 获取用户信息，校验通过的结果会缓存一段时间
+func (d *DootaskService) GetCachedUserInfo(token string) (*dto.UserInfoResp, error) {
+	now := time.Now()
+	userInfoCache.Lock()
+	entry, ok := userInfoCache.entries[token]
+	userInfoCache.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.info, nil
+	}
+
+	info, err := d.GetUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	userInfoCache.Lock()
+	defer userInfoCache.Unlock()
+	if len(userInfoCache.entries) >= userInfoCacheLimit {
+		for key, entry := range userInfoCache.entries {
+			if now.After(entry.expiresAt) {
+				delete(userInfoCache.entries, key)
+			}
+		}
+	}
+	userInfoCache.entries[token] = userInfoCacheEntry{info: info, expiresAt: now.Add(userInfoCacheTTL)}
+	return info, nil
+}
+
 // GetVersionInfo 获取版本信息
 func (d *DootaskService) GetVersoinInfo() (*dto.VersionInfoResp, error) {
 	// url := fmt.Sprintf("%s%s", constant.DooTaskUrl, "/api/system/version")
@@ -0,0 +1,663 @@
+package service
+
+import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto/response"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/docker"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dockerNetwork "github.com/docker/docker/api/types/network"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// IPAllocationManager 插件网段的IP分配管理
+// 分配记录保存在 ip_allocations 表中，docker.GlobalIPAllocator 与 docker.GlobalIP6Allocator 只作为内存索引，启动时由分配记录重建
+type IPAllocationManager struct {
+	mu sync.Mutex
+}
+
+var ipAllocationManager = &IPAllocationManager{}
+
+// NewIPAllocationManager 创建IP分配管理实例
+func NewIPAllocationManager() *IPAllocationManager {
+	return ipAllocationManager
+}
+
+// IPOwner IP的使用方，InstallID 为 0 表示插件尚未完成安装，IP 处于预留状态
+type IPOwner struct {
+	Type      string
+	ID        int64
+	Name      string
+	InstallID int64
+}
+
+// IPInUseError IP已被其他使用方占用或已被排除
+type IPInUseError struct {
+	IpAddress string
+	Owner     string
+}
+
+func (e *IPInUseError) Error() string {
+	return fmt.Sprintf("IP 已被占用: %s", e.Detail())
+}
+
+// Detail 用于错误提示的详细信息
+func (e *IPInUseError) Detail() string {
+	if e.Owner == "" {
+		return e.IpAddress
+	}
+	return fmt.Sprintf("%s (%s)", e.IpAddress, e.Owner)
+}
+
+// owns 分配记录是否属于该使用方
+func (o IPOwner) owns(allocation *model.IpAllocation) bool {
+	if allocation.InstallID != o.InstallID {
+		return false
+	}
+	return o.InstallID != 0 || allocation.OwnerName == o.Name
+}
+
+// UsedIPs 获取所有处于占用状态的IP
+func (m *IPAllocationManager) UsedIPs() ([]string, error) {
+	allocations, err := m.activeAllocations(repo.DB)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]string, 0, len(allocations))
+	for _, allocation := range allocations {
+		ips = append(ips, allocation.IpAddress)
+	}
+	return ips, nil
+}
+
+// Allocations 获取未释放的分配记录，包括排除的IP
+func (m *IPAllocationManager) Allocations() ([]*model.IpAllocation, error) {
+	q := repo.IpAllocation
+	return q.Where(q.State.Neq(model.IPStateReleased)).Order(q.IpAddress).Find()
+}
+
+// ExcludedIPs 获取管理员排除的IP
+func (m *IPAllocationManager) ExcludedIPs() ([]string, error) {
+	q := repo.IpAllocation
+	allocations, err := q.Select(q.IpAddress).Where(q.State.Eq(model.IPStateExcluded)).Find()
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]string, 0, len(allocations))
+	for _, allocation := range allocations {
+		ips = append(ips, allocation.IpAddress)
+	}
+	return ips, nil
+}
+
+// Backfill 为没有分配记录的已安装插件及其服务补充分配记录
+// 升级前安装的插件只在 AppInstalled 与 AppServiceStatus 中保存了IP
+func (m *IPAllocationManager) Backfill() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		q := repo.Use(tx)
+		allocations, err := q.IpAllocation.Select(q.IpAllocation.IpAddress).Find()
+		if err != nil {
+			return err
+		}
+		recorded := make(map[string]bool, len(allocations))
+		for _, allocation := range allocations {
+			recorded[allocation.IpAddress] = true
+		}
+
+		installs, err := q.AppInstalled.Select(q.AppInstalled.ID, q.AppInstalled.Name, q.AppInstalled.IpAddress).Find()
+		if err != nil {
+			return err
+		}
+		for _, install := range installs {
+			if install.IpAddress == "" || recorded[install.IpAddress] {
+				continue
+			}
+			owner := IPOwner{Type: model.IPOwnerInstallation, ID: install.ID, Name: install.Name, InstallID: install.ID}
+			if err = m.save(tx, install.IpAddress, owner); err != nil {
+				return err
+			}
+			recorded[install.IpAddress] = true
+			log.Info("补充IP分配记录:", install.IpAddress, install.Name)
+		}
+
+		services, err := q.AppServiceStatus.Find()
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			for _, ip := range splitIPs(service.IpAddress) {
+				if recorded[ip] {
+					continue
+				}
+				owner := IPOwner{Type: model.IPOwnerService, ID: service.ID, Name: service.ContainerName, InstallID: service.InstallID}
+				if err = m.save(tx, ip, owner); err != nil {
+					return err
+				}
+				recorded[ip] = true
+				log.Info("补充IP分配记录:", ip, service.ContainerName)
+			}
+		}
+		return nil
+	})
+}
+
+// Allocate 在事务中分配一个新的IPv4地址并写入分配记录
+func (m *IPAllocationManager) Allocate(owner IPOwner) (string, error) {
+	return m.allocate(docker.GlobalIPAllocator, owner)
+}
+
+// Allocate6 在事务中分配一个新的IPv6地址并写入分配记录，未启用IPv6时返回空字符串
+func (m *IPAllocationManager) Allocate6(owner IPOwner) (string, error) {
+	if docker.GlobalIP6Allocator == nil {
+		return "", nil
+	}
+	return m.allocate(docker.GlobalIP6Allocator, owner)
+}
+
+func (m *IPAllocationManager) allocate(allocator *docker.IPAllocator, owner IPOwner) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ipAddress := ""
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		allocations, err := m.activeAllocations(tx)
+		if err != nil {
+			return err
+		}
+		// 以数据库中的分配记录为准，同步内存中的分配器
+		for _, allocation := range allocations {
+			if allocator.Contains(allocation.IpAddress) && !allocator.IsUsed(allocation.IpAddress) {
+				_ = allocator.RegisterIP(allocation.IpAddress)
+			}
+		}
+		ipAddress, err = allocator.AllocateIP()
+		if err != nil {
+			return err
+		}
+		return m.save(tx, ipAddress, owner)
+	})
+	if err != nil {
+		if ipAddress != "" {
+			_ = allocator.ReleaseIP(ipAddress)
+		}
+		log.Error("分配IP地址失败:", err)
+		return "", errors.New(constant.ErrIPAllocateFailed)
+	}
+	return ipAddress, nil
+}
+
+// Claim 占用指定的IP，用于 docker-compose 中写死的IP与恢复备份时复用原IP
+// IP 已被其他使用方占用时返回错误
+func (m *IPAllocationManager) Claim(ipAddress string, owner IPOwner) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		return m.save(tx, ipAddress, owner)
+	})
+	if err != nil {
+		return err
+	}
+	m.registerMemory([]string{ipAddress})
+	return nil
+}
+
+// CheckAvailable 检查 docker-compose 中写死的IP是否可用
+// IP 已被其他使用方占用、已被排除，或正在被不属于该插件的容器使用时返回 IPInUseError
+func (m *IPAllocationManager) CheckAvailable(client docker.Client, ips []string, owner IPOwner, containerNames []string) error {
+	if len(ips) == 0 {
+		return nil
+	}
+	q := repo.IpAllocation
+	allocations, err := q.Where(q.IpAddress.In(ips...)).Find()
+	if err != nil {
+		return err
+	}
+	for _, allocation := range allocations {
+		if allocation.State == model.IPStateExcluded || (allocation.Active() && !owner.owns(allocation)) {
+			return &IPInUseError{IpAddress: allocation.IpAddress, Owner: allocation.OwnerName}
+		}
+	}
+
+	containers, err := client.ListAllContainers()
+	if err != nil {
+		log.Error("获取容器列表失败:", err)
+		return errors.New(constant.ErrDockerListContainers)
+	}
+	checked := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		checked[ip] = true
+	}
+	own := make(map[string]bool, len(containerNames))
+	for _, name := range containerNames {
+		own[name] = true
+	}
+	for _, container := range containers {
+		if container.NetworkSettings == nil || len(container.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(container.Names[0], "/")
+		if own[name] {
+			continue
+		}
+		for _, network := range container.NetworkSettings.Networks {
+			for _, ip := range containerIPs(network) {
+				if checked[ip] {
+					return &IPInUseError{IpAddress: ip, Owner: name}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Reserve 由管理员预留IP，预留的IP不会被分配给插件
+func (m *IPAllocationManager) Reserve(ips []string, note string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := IPOwner{Type: model.IPOwnerAdmin, Name: note}
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		for _, ip := range ips {
+			if err := m.save(tx, ip, owner); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.registerMemory(ips)
+	return nil
+}
+
+// Exclude 排除IP，排除的IP不会被分配，ips 为 expandIPRange 展开的连续IP
+func (m *IPAllocationManager) Exclude(ips []string, note string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		q := repo.Use(tx).IpAllocation
+		for _, ip := range ips {
+			allocation, err := q.Where(q.IpAddress.Eq(ip)).First()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if allocation == nil {
+				err = q.Create(&model.IpAllocation{
+					IpAddress: ip,
+					OwnerType: model.IPOwnerAdmin,
+					OwnerName: note,
+					State:     model.IPStateExcluded,
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if allocation.Active() {
+				return &IPInUseError{IpAddress: ip, Owner: allocation.OwnerName}
+			}
+			_, err = q.Where(q.ID.Eq(allocation.ID)).Updates(map[string]interface{}{
+				"owner_type":  model.IPOwnerAdmin,
+				"owner_id":    0,
+				"owner_name":  note,
+				"install_id":  0,
+				"state":       model.IPStateExcluded,
+				"released_at": &now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || len(ips) == 0 {
+		return err
+	}
+	if allocator := docker.AllocatorFor(ips[0]); allocator != nil {
+		_ = allocator.AddExcludedIPRange(ips[0], ips[len(ips)-1])
+	}
+	return nil
+}
+
+// Bind 插件安装记录创建后，将预留的IP绑定到安装记录，需要在创建安装记录的事务中调用
+func (m *IPAllocationManager) Bind(tx *gorm.DB, ipAddress string, owner IPOwner) error {
+	q := repo.Use(tx).IpAllocation
+	now := time.Now()
+	_, err := q.Where(q.IpAddress.Eq(ipAddress)).Updates(map[string]interface{}{
+		"owner_type":   owner.Type,
+		"owner_id":     owner.ID,
+		"owner_name":   owner.Name,
+		"install_id":   owner.InstallID,
+		"state":        model.IPStateAllocated,
+		"allocated_at": &now,
+	})
+	return err
+}
+
+// Release 释放IP
+func (m *IPAllocationManager) Release(ips ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(ips) == 0 {
+		return nil
+	}
+	q := repo.IpAllocation
+	_, err := q.Where(q.IpAddress.In(ips...)).Updates(map[string]interface{}{
+		"state":       model.IPStateReleased,
+		"released_at": time.Now(),
+	})
+	if err != nil {
+		log.Error("释放IP失败:", err)
+		return err
+	}
+	m.releaseMemory(ips)
+	return nil
+}
+
+// ReleaseStale 释放不再使用的分配记录，仍被已安装插件使用的IP不允许释放
+func (m *IPAllocationManager) ReleaseStale(ipAddress string) error {
+	q := repo.IpAllocation
+	allocation, err := q.Where(q.IpAddress.Eq(ipAddress)).First()
+	if err != nil {
+		return err
+	}
+	if allocation.State == model.IPStateAllocated && allocation.InstallID != 0 {
+		count, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(allocation.InstallID)).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return &IPInUseError{IpAddress: ipAddress, Owner: allocation.OwnerName}
+		}
+	}
+	if err = m.Release(ipAddress); err != nil {
+		return err
+	}
+	// 起始IP之前的地址本身就不允许分配
+	allocator := docker.AllocatorFor(ipAddress)
+	if allocation.State == model.IPStateExcluded && allocator != nil && allocator.InAllocRange(ipAddress) {
+		_ = allocator.RemoveExcludedIP(ipAddress)
+	}
+	return nil
+}
+
+// ReleaseByInstall 在卸载插件的事务中释放插件占用的所有IP，返回释放的IP
+// 事务提交后需要调用 ReleaseMemory 同步内存中的分配器
+func (m *IPAllocationManager) ReleaseByInstall(tx *gorm.DB, installID int64) ([]string, error) {
+	q := repo.Use(tx).IpAllocation
+	allocations, err := q.Where(q.InstallID.Eq(installID), q.State.In(model.IPStateReserved, model.IPStateAllocated)).Find()
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]string, 0, len(allocations))
+	for _, allocation := range allocations {
+		ips = append(ips, allocation.IpAddress)
+	}
+	if len(ips) == 0 {
+		return ips, nil
+	}
+	_, err = q.Where(q.IpAddress.In(ips...)).Updates(map[string]interface{}{
+		"state":       model.IPStateReleased,
+		"released_at": time.Now(),
+	})
+	return ips, err
+}
+
+// ReleaseMemory 从内存中的分配器释放IP
+func (m *IPAllocationManager) ReleaseMemory(ips []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.releaseMemory(ips)
+}
+
+func (m *IPAllocationManager) releaseMemory(ips []string) {
+	for _, ip := range ips {
+		if allocator := docker.AllocatorFor(ip); allocator != nil {
+			_ = allocator.ReleaseIP(ip)
+		}
+	}
+}
+
+// registerMemory 将IP注册到内存中的分配器，不属于插件网段的IP会被忽略
+func (m *IPAllocationManager) registerMemory(ips []string) {
+	for _, ip := range ips {
+		allocator := docker.AllocatorFor(ip)
+		if allocator == nil || allocator.IsUsed(ip) {
+			continue
+		}
+		if err := allocator.RegisterIP(ip); err != nil {
+			log.Debugf("注册IP失败 %s: %v", ip, err)
+		}
+	}
+}
+
+// Reconcile 对比Docker中容器实际使用的IP与分配记录，返回发现的冲突
+// 没有分配记录的IP会注册到内存中的分配器，避免被重复分配
+func (m *IPAllocationManager) Reconcile(client docker.Client) (*response.IPReconcileReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	containers, err := client.ListAllContainers()
+	if err != nil {
+		log.Error("获取容器列表失败:", err)
+		return nil, errors.New(constant.ErrDockerListContainers)
+	}
+	allocations, err := m.activeAllocations(repo.DB)
+	if err != nil {
+		return nil, err
+	}
+	installs, err := repo.AppInstalled.Select(repo.AppInstalled.ID, repo.AppInstalled.Name).Find()
+	if err != nil {
+		return nil, err
+	}
+	services, err := repo.AppServiceStatus.Select(repo.AppServiceStatus.InstallID, repo.AppServiceStatus.ContainerName).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	// 容器名 -> 安装ID
+	containerInstall := map[string]int64{}
+	installExists := map[int64]bool{}
+	for _, install := range installs {
+		containerInstall[install.Name] = install.ID
+		installExists[install.ID] = true
+	}
+	for _, service := range services {
+		if service.ContainerName != "" {
+			containerInstall[service.ContainerName] = service.InstallID
+		}
+	}
+
+	// IP -> 使用该IP的容器
+	dockerIPs := map[string][]string{}
+	for _, container := range containers {
+		if container.NetworkSettings == nil || len(container.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(container.Names[0], "/")
+		for _, network := range container.NetworkSettings.Networks {
+			for _, ip := range containerIPs(network) {
+				if docker.AllocatorFor(ip) != nil {
+					dockerIPs[ip] = append(dockerIPs[ip], name)
+				}
+			}
+		}
+	}
+
+	report := &response.IPReconcileReport{
+		Network:     docker.GlobalIPAllocator.Network(),
+		Network6:    network6(),
+		Allocations: len(allocations),
+		Containers:  len(dockerIPs),
+		Conflicts:   []*response.IPConflict{},
+	}
+	recorded := make(map[string]*model.IpAllocation, len(allocations))
+	for _, allocation := range allocations {
+		recorded[allocation.IpAddress] = allocation
+	}
+
+	for ip, names := range dockerIPs {
+		sort.Strings(names)
+		allocation := recorded[ip]
+		conflict := &response.IPConflict{IpAddress: ip, Containers: names}
+		if allocation != nil {
+			conflict.InstallID = allocation.InstallID
+			conflict.OwnerName = allocation.OwnerName
+		}
+		switch {
+		case len(names) > 1:
+			conflict.Type = response.IPConflictDuplicate
+			conflict.Message = fmt.Sprintf("IP %s 同时被多个容器使用: %s", ip, strings.Join(names, ", "))
+		case allocation == nil:
+			conflict.Type = response.IPConflictUntracked
+			conflict.Message = fmt.Sprintf("容器 %s 使用的IP %s 没有分配记录", names[0], ip)
+			m.registerMemory([]string{ip})
+		case allocation.InstallID != 0 && containerInstall[names[0]] != allocation.InstallID:
+			conflict.Type = response.IPConflictOwnerMismatch
+			conflict.Message = fmt.Sprintf("IP %s 分配给了 %s，但被容器 %s 使用", ip, allocation.OwnerName, names[0])
+		default:
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	for _, allocation := range allocations {
+		if allocation.State != model.IPStateAllocated {
+			continue
+		}
+		conflict := &response.IPConflict{
+			IpAddress:  allocation.IpAddress,
+			Containers: []string{},
+			InstallID:  allocation.InstallID,
+			OwnerName:  allocation.OwnerName,
+		}
+		switch {
+		case !installExists[allocation.InstallID]:
+			conflict.Type = response.IPConflictOrphaned
+			conflict.Message = fmt.Sprintf("IP %s 分配给的插件已不存在", allocation.IpAddress)
+		case len(dockerIPs[allocation.IpAddress]) == 0:
+			conflict.Type = response.IPConflictMissing
+			conflict.Message = fmt.Sprintf("IP %s 已分配给 %s，但没有容器使用", allocation.IpAddress, allocation.OwnerName)
+		default:
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	sort.Slice(report.Conflicts, func(i, j int) bool {
+		return report.Conflicts[i].IpAddress < report.Conflicts[j].IpAddress
+	})
+	for _, conflict := range report.Conflicts {
+		log.Warn("IP冲突:", conflict.Message)
+	}
+	return report, nil
+}
+
+// save 写入分配记录，每个IP只有一条记录，已释放的记录会被重新使用
+func (m *IPAllocationManager) save(tx *gorm.DB, ipAddress string, owner IPOwner) error {
+	q := repo.Use(tx).IpAllocation
+	state := model.IPStateAllocated
+	if owner.InstallID == 0 {
+		state = model.IPStateReserved
+	}
+	now := time.Now()
+
+	allocation, err := q.Where(q.IpAddress.Eq(ipAddress)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if allocation == nil {
+		return q.Create(&model.IpAllocation{
+			IpAddress:   ipAddress,
+			OwnerType:   owner.Type,
+			OwnerID:     owner.ID,
+			OwnerName:   owner.Name,
+			InstallID:   owner.InstallID,
+			State:       state,
+			AllocatedAt: &now,
+		})
+	}
+	if allocation.Active() {
+		if owner.owns(allocation) {
+			return nil
+		}
+		return &IPInUseError{IpAddress: ipAddress, Owner: allocation.OwnerName}
+	}
+	if allocation.State == model.IPStateExcluded {
+		return &IPInUseError{IpAddress: ipAddress, Owner: allocation.OwnerName}
+	}
+	_, err = q.Where(q.ID.Eq(allocation.ID)).Updates(map[string]interface{}{
+		"owner_type":   owner.Type,
+		"owner_id":     owner.ID,
+		"owner_name":   owner.Name,
+		"install_id":   owner.InstallID,
+		"state":        state,
+		"allocated_at": &now,
+		"released_at":  nil,
+	})
+	return err
+}
+
+func (m *IPAllocationManager) activeAllocations(db *gorm.DB) ([]*model.IpAllocation, error) {
+	q := repo.Use(db).IpAllocation
+	return q.Where(q.State.In(model.IPStateReserved, model.IPStateAllocated)).Find()
+}
+
+// containerIPs 容器在网络中的IPv4与IPv6地址，停止的容器取其配置的静态IP
+func containerIPs(network *dockerNetwork.EndpointSettings) []string {
+	ips := []string{}
+	if network == nil {
+		return ips
+	}
+	ipv4, ipv6 := network.IPAddress, network.GlobalIPv6Address
+	if network.IPAMConfig != nil {
+		if ipv4 == "" {
+			ipv4 = network.IPAMConfig.IPv4Address
+		}
+		if ipv6 == "" {
+			ipv6 = network.IPAMConfig.IPv6Address
+		}
+	}
+	for _, ip := range []string{ipv4, ipv6} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// network6 IPv6网段，未启用IPv6时为空
+func network6() string {
+	if docker.GlobalIP6Allocator == nil {
+		return ""
+	}
+	return docker.GlobalIP6Allocator.Network()
+}
+
+// splitIPs 拆分 AppServiceStatus 中逗号分隔的IP
+func splitIPs(value string) []string {
+	ips := []string{}
+	for _, ip := range strings.Split(value, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
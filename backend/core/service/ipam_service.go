@@ -0,0 +1,223 @@
+package service
+
+import (
+	"bytes"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/dto/response"
+	"doo-store/backend/core/model"
+	"doo-store/backend/utils/docker"
+	e "doo-store/backend/utils/error"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ipRangeLimit 一次预留或排除的最大IP数量
+const ipRangeLimit = 1024
+
+// IPAMService 插件网段的IP地址管理
+type IPAMService struct {
+}
+
+type IIPAMService interface {
+	GetOverview(ctx dto.ServiceContext, req request.IPAMSearch) (*response.IPAMOverview, error)
+	ReserveRange(ctx dto.ServiceContext, req request.IPRange) ([]string, error)
+	ExcludeRange(ctx dto.ServiceContext, req request.IPRange) ([]string, error)
+	ReleaseIP(ctx dto.ServiceContext, ip string) error
+	Reconcile(ctx dto.ServiceContext) (*response.IPReconcileReport, error)
+}
+
+func NewIIPAMService() IIPAMService {
+	return &IPAMService{}
+}
+
+// GetOverview 获取网段中IP的使用情况，只返回有分配记录、被排除或没有分配记录但已被使用的IP，空闲的IP只统计数量
+// 起始IP之前的地址不逐个返回，只计入排除的数量；统计数量只包括IPv4网段
+func (*IPAMService) GetOverview(ctx dto.ServiceContext, req request.IPAMSearch) (*response.IPAMOverview, error) {
+	allocations, err := ipAllocationManager.Allocations()
+	if err != nil {
+		return nil, err
+	}
+	allocator := docker.GlobalIPAllocator
+	recorded := make(map[string]*model.IpAllocation, len(allocations))
+	for _, allocation := range allocations {
+		recorded[allocation.IpAddress] = allocation
+	}
+
+	overview := &response.IPAMOverview{
+		Network:   allocator.Network(),
+		Total:     int(allocator.HostCount()),
+		Addresses: []*response.IPAddressInfo{},
+	}
+	// 只遍历非空闲的IP，分配器中排除的IP都有分配记录
+	ips := []string{}
+	seen := map[string]bool{}
+	for _, ip := range allocator.GetUsedIPs() {
+		if !seen[ip] {
+			seen[ip] = true
+			ips = append(ips, ip)
+		}
+	}
+	for _, allocation := range allocations {
+		if !seen[allocation.IpAddress] {
+			seen[allocation.IpAddress] = true
+			ips = append(ips, allocation.IpAddress)
+		}
+	}
+	preStart := int(allocator.PreStartCount())
+	for _, ip := range ips {
+		if !allocator.IsHost(ip) {
+			continue
+		}
+		info := &response.IPAddressInfo{IpAddress: ip, State: response.IPStateFree}
+		if allocation, ok := recorded[ip]; ok {
+			info.State = allocation.State
+			info.OwnerType = allocation.OwnerType
+			info.OwnerName = allocation.OwnerName
+			info.InstallID = allocation.InstallID
+			info.AllocatedAt = allocation.AllocatedAt
+		} else if allocator.IsExcluded(ip) {
+			info.State = model.IPStateExcluded
+		} else if allocator.IsUsed(ip) {
+			info.State = response.IPStateUntracked
+		}
+		if !allocator.InAllocRange(ip) {
+			// 起始IP之前的地址已计入排除的数量
+			preStart--
+			if info.State == model.IPStateExcluded {
+				continue
+			}
+		}
+
+		switch info.State {
+		case response.IPStateFree:
+			continue
+		case model.IPStateReserved:
+			overview.Reserved++
+		case model.IPStateAllocated:
+			overview.Allocated++
+		case model.IPStateExcluded:
+			overview.Excluded++
+		case response.IPStateUntracked:
+			overview.Untracked++
+		}
+		if req.State == "" || req.State == info.State {
+			overview.Addresses = append(overview.Addresses, info)
+		}
+	}
+	overview.Excluded += preStart
+	overview.Free = overview.Total - overview.Reserved - overview.Allocated - overview.Excluded - overview.Untracked
+	sort.Slice(overview.Addresses, func(i, j int) bool {
+		return compareIP(overview.Addresses[i].IpAddress, overview.Addresses[j].IpAddress) < 0
+	})
+
+	// IPv6 网段过大，只返回有分配记录的IP
+	if docker.GlobalIP6Allocator != nil {
+		overview.Network6 = docker.GlobalIP6Allocator.Network()
+		for _, allocation := range allocations {
+			if !docker.GlobalIP6Allocator.Contains(allocation.IpAddress) {
+				continue
+			}
+			if req.State == "" || req.State == allocation.State {
+				overview.Addresses = append(overview.Addresses, &response.IPAddressInfo{
+					IpAddress:   allocation.IpAddress,
+					State:       allocation.State,
+					OwnerType:   allocation.OwnerType,
+					OwnerName:   allocation.OwnerName,
+					InstallID:   allocation.InstallID,
+					AllocatedAt: allocation.AllocatedAt,
+				})
+			}
+		}
+	}
+	return overview, nil
+}
+
+// ReserveRange 预留IP范围，范围内的IP都未被占用时才会预留
+func (*IPAMService) ReserveRange(ctx dto.ServiceContext, req request.IPRange) ([]string, error) {
+	ips, err := expandIPRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = ipAllocationManager.Reserve(ips, req.Note); err != nil {
+		return nil, ipamError(ctx, err)
+	}
+	log.Infof("预留IP %s-%s", ips[0], ips[len(ips)-1])
+	return ips, nil
+}
+
+// ExcludeRange 排除IP范围，范围内的IP都未被占用时才会排除
+func (*IPAMService) ExcludeRange(ctx dto.ServiceContext, req request.IPRange) ([]string, error) {
+	ips, err := expandIPRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = ipAllocationManager.Exclude(ips, req.Note); err != nil {
+		return nil, ipamError(ctx, err)
+	}
+	log.Infof("排除IP %s-%s", ips[0], ips[len(ips)-1])
+	return ips, nil
+}
+
+// ReleaseIP 释放预留、排除或已失效的分配记录
+func (*IPAMService) ReleaseIP(ctx dto.ServiceContext, ip string) error {
+	if err := ipAllocationManager.ReleaseStale(ip); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New(constant.ErrIPAllocationNotFound)
+		}
+		return ipamError(ctx, err)
+	}
+	log.Info("释放IP:", ip)
+	return nil
+}
+
+// Reconcile 核对分配记录与容器实际使用的IP
+func (*IPAMService) Reconcile(ctx dto.ServiceContext) (*response.IPReconcileReport, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return nil, errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+	return ipAllocationManager.Reconcile(client)
+}
+
+// compareIP 按地址大小比较两个IP
+func compareIP(a, b string) int {
+	return bytes.Compare(net.ParseIP(a).To16(), net.ParseIP(b).To16())
+}
+
+// expandIPRange 展开IP范围，未指定结束IP时只包含起始IP
+func expandIPRange(ctx dto.ServiceContext, req request.IPRange) ([]string, error) {
+	if req.End == "" {
+		req.End = req.Start
+	}
+	allocator := docker.AllocatorFor(req.Start)
+	if allocator == nil {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrIPAddressInvalid, req.Start, nil)
+	}
+	ips, err := allocator.Range(req.Start, req.End, ipRangeLimit)
+	if err != nil {
+		if errors.Is(err, docker.ErrIPRangeTooLarge) {
+			return nil, errors.New(constant.ErrIPRangeTooLarge)
+		}
+		log.Warn("无效的IP范围:", err)
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrIPAddressInvalid, fmt.Sprintf("%s-%s", req.Start, req.End), nil)
+	}
+	return ips, nil
+}
+
+// ipamError IP被占用时返回带有IP与使用方的错误提示
+func ipamError(ctx dto.ServiceContext, err error) error {
+	var inUse *IPInUseError
+	if errors.As(err, &inUse) {
+		return e.NewErrorWithDetail(ctx.C, constant.ErrIPAddressInUse, inUse.Detail(), nil)
+	}
+	return err
+}
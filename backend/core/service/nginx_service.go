@@ -0,0 +1,492 @@
+package service
+
+import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/common"
+	"doo-store/backend/utils/docker"
+	e "doo-store/backend/utils/error"
+	"doo-store/backend/utils/nginx"
+	"errors"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// NginxService 插件的Nginx配置管理
+type NginxService struct {
+}
+
+type INginxService interface {
+	Reconcile(ctx dto.ServiceContext) (*nginx.ReconcileReport, error)
+	ListVersions(ctx dto.ServiceContext, id int64) ([]*model.NginxLocationVersion, error)
+	Preview(ctx dto.ServiceContext, req request.NginxLocationApply) (*nginx.PreviewResult, error)
+	Apply(ctx dto.ServiceContext, req request.NginxLocationApply) (*model.NginxLocationVersion, error)
+	UpdateDomain(ctx dto.ServiceContext, req request.AppDomain) error
+	GetOptions(ctx dto.ServiceContext, id int64) (*nginx.LocationOptions, error)
+	UpdateOptions(ctx dto.ServiceContext, req request.NginxLocationOptions) (*nginx.LocationOptions, error)
+}
+
+func NewINginxService() INginxService {
+	return &NginxService{}
+}
+
+// Reconcile 核对并修复Nginx容器中的插件配置
+func (*NginxService) Reconcile(ctx dto.ServiceContext) (*nginx.ReconcileReport, error) {
+	report, err := ReconcileNginx()
+	if err != nil {
+		return report, errors.New(constant.ErrNginxReconcileFailed)
+	}
+	return report, nil
+}
+
+// ListVersions 获取插件Nginx配置的历史版本，按版本号倒序
+func (*NginxService) ListVersions(ctx dto.ServiceContext, id int64) ([]*model.NginxLocationVersion, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	return repo.NginxLocationVersion.Where(repo.NginxLocationVersion.AppInstalledId.Eq(appInstalled.ID)).
+		Order(repo.NginxLocationVersion.Version.Desc()).Find()
+}
+
+// Preview 渲染修改后的模板并在Nginx容器中检测，不会重新加载Nginx
+func (*NginxService) Preview(ctx dto.ServiceContext, req request.NginxLocationApply) (*nginx.PreviewResult, error) {
+	_, _, nm, locationConfig, err := prepareNginxLocation(req)
+	if err != nil {
+		return nil, err
+	}
+	return nm.Preview(locationConfig)
+}
+
+// Apply 检测通过后应用新的模板并保存为新版本，应用失败时恢复当前使用的配置
+func (*NginxService) Apply(ctx dto.ServiceContext, req request.NginxLocationApply) (*model.NginxLocationVersion, error) {
+	appInstalled, current, nm, locationConfig, err := prepareNginxLocation(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := nm.Preview(locationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrNginxConfigInvalid, result.Output, nil)
+	}
+
+	if err := nm.AddLocation(locationConfig); err != nil {
+		log.Error("应用Nginx配置失败:", err)
+		// AddLocation 失败时已通过 rollbackChanges 移除新配置，重新写入当前使用的配置
+		if current != nil {
+			if err := nm.AddLocation(current); err != nil {
+				log.Error("恢复Nginx配置失败:", err)
+			}
+		}
+		return nil, errors.New(constant.ErrNginxApplyFailed)
+	}
+
+	remark := req.Remark
+	if remark == "" && req.Template == "" {
+		remark = fmt.Sprintf("恢复到版本 %d", req.Version)
+	}
+	version, err := saveNginxVersion(nm, appInstalled, locationConfig, remark)
+	if err != nil {
+		log.Error("保存Nginx配置版本失败:", err)
+		return nil, err
+	}
+	if locations := nm.ExtractLocations(version.Content); len(locations) > 0 {
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Location, locations[0])
+	}
+	insertLog(appInstalled.ID, "更新Nginx配置", fmt.Sprintf("版本 %d", version.Version))
+	return version, nil
+}
+
+// UpdateDomain 修改插件的独立域名，插件使用独立域名时重新写入Nginx配置，失败时恢复原域名
+func (*NginxService) UpdateDomain(ctx dto.ServiceContext, req request.AppDomain) error {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	appDetail, err := repo.AppDetail.Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First()
+	if err != nil {
+		log.Info("Error query app detail", err)
+		return errors.New(constant.ErrPluginInfoFailed)
+	}
+	domain := strings.ToLower(strings.TrimSpace(req.Domain))
+	if err := pluginHelper.CheckDomain(ctx, appInstalled.Key, domain, appInstalled.ID); err != nil {
+		return err
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+
+	current, _, err := currentNginxLocation(client, appInstalled, appDetail)
+	if err != nil {
+		return err
+	}
+	if current.Mode == nginx.LocationModeHost && pluginHelper.PluginDomain(appInstalled.Key, domain) == "" {
+		return errors.New(constant.ErrNginxDomainRequired)
+	}
+
+	previousDomain := appInstalled.Domain
+	if _, err = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Domain, domain); err != nil {
+		return err
+	}
+	appInstalled.Domain = domain
+	insertLog(appInstalled.ID, "修改域名", domain)
+
+	// 路径挂载的插件只保存域名，切换为独立域名后生效
+	if current.Mode != nginx.LocationModeHost || appInstalled.Status == model.PluginStatusInstalling {
+		return nil
+	}
+
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return err
+	}
+	serverName := pluginHelper.PluginDomain(appInstalled.Key, domain)
+	locationConfig := nginx.NewLocationConfig(appInstalled.Key, appInstalled.Name).
+		WithTemplate(current.Template).
+		WithPort(current.Port).
+		WithServerName(serverName).
+		WithCertificate(pluginHelper.CertificateDomain(serverName)).
+		WithOptions(current.Options)
+	if err := nm.AddLocation(locationConfig); err != nil {
+		log.Error("应用Nginx配置失败:", err)
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.Domain, previousDomain)
+		if err := nm.AddLocation(current); err != nil {
+			log.Error("恢复Nginx配置失败:", err)
+		}
+		return errors.New(constant.ErrNginxApplyFailed)
+	}
+	if _, err := saveNginxVersion(nm, appInstalled, locationConfig, "修改域名"); err != nil {
+		log.Error("保存Nginx配置版本失败:", err)
+	}
+	return nil
+}
+
+// GetOptions 获取插件的访问控制与限流配置，不返回 Basic Auth 的密码
+func (*NginxService) GetOptions(ctx dto.ServiceContext, id int64) (*nginx.LocationOptions, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	return maskNginxOptions(pluginHelper.NginxOptions(appInstalled)), nil
+}
+
+// UpdateOptions 修改插件的访问控制与限流配置，通过检测后重新加载Nginx生效，不会重启插件容器，失败时恢复原配置。
+// Basic Auth 用户未提交密码时沿用原密码
+func (*NginxService) UpdateOptions(ctx dto.ServiceContext, req request.NginxLocationOptions) (*nginx.LocationOptions, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	appDetail, err := repo.AppDetail.Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First()
+	if err != nil {
+		log.Info("Error query app detail", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+
+	options := req.LocationOptions
+	previousHashes := map[string]string{}
+	if previous := pluginHelper.NginxOptions(appInstalled); previous != nil {
+		for _, user := range previous.BasicAuth {
+			previousHashes[user.Username] = user.Hash
+		}
+	}
+	for i := range options.BasicAuth {
+		user := &options.BasicAuth[i]
+		user.Hash = previousHashes[user.Username]
+		if user.Password != "" {
+			if user.Hash, err = nginx.HashPassword(user.Password); err != nil {
+				return nil, err
+			}
+			user.Password = ""
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, e.NewErrorWithDetail(ctx.C, constant.ErrNginxOptionsInvalid, err.Error(), nil)
+	}
+	value := ""
+	if !options.Empty() {
+		value = common.StructToJson(options)
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return nil, errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+
+	current, _, err := currentNginxLocation(client, appInstalled, appDetail)
+	if err != nil {
+		return nil, err
+	}
+
+	previousValue := appInstalled.NginxOptions
+	if _, err = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.NginxOptions, value); err != nil {
+		return nil, err
+	}
+	insertLog(appInstalled.ID, "修改访问控制", "")
+
+	// 没有Nginx配置或安装中的插件只保存配置，添加Nginx配置时生效
+	if current.Template == "" || appInstalled.Status == model.PluginStatusInstalling {
+		return maskNginxOptions(&options), nil
+	}
+
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return nil, err
+	}
+	locationConfig := *current
+	locationConfig.Options = &options
+	if err := nm.AddLocation(&locationConfig); err != nil {
+		log.Error("应用Nginx配置失败:", err)
+		_, _ = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Update(repo.AppInstalled.NginxOptions, previousValue)
+		if err := nm.AddLocation(current); err != nil {
+			log.Error("恢复Nginx配置失败:", err)
+		}
+		return nil, errors.New(constant.ErrNginxApplyFailed)
+	}
+	if _, err := saveNginxVersion(nm, appInstalled, &locationConfig, "修改访问控制"); err != nil {
+		log.Error("保存Nginx配置版本失败:", err)
+	}
+	return maskNginxOptions(&options), nil
+}
+
+// maskNginxOptions 复制访问控制配置并去掉 Basic Auth 的密码哈希
+func maskNginxOptions(options *nginx.LocationOptions) *nginx.LocationOptions {
+	masked := &nginx.LocationOptions{}
+	if options == nil {
+		return masked
+	}
+	*masked = *options
+	masked.BasicAuth = make([]nginx.BasicAuthUser, 0, len(options.BasicAuth))
+	for _, user := range options.BasicAuth {
+		masked.BasicAuth = append(masked.BasicAuth, nginx.BasicAuthUser{Username: user.Username})
+	}
+	return masked
+}
+
+// prepareNginxLocation 根据请求中的模板或历史版本生成新的location配置，同时返回当前使用的配置
+func prepareNginxLocation(req request.NginxLocationApply) (*model.AppInstalled, *nginx.LocationConfig, *nginx.NginxManager, *nginx.LocationConfig, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, nil, nil, nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	appDetail, err := repo.AppDetail.Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First()
+	if err != nil {
+		log.Info("Error query app detail", err)
+		return nil, nil, nil, nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+
+	template := req.Template
+	if template == "" {
+		if req.Version == 0 {
+			return nil, nil, nil, nil, errors.New(constant.ErrNginxTemplateRequired)
+		}
+		version, err := repo.NginxLocationVersion.Where(
+			repo.NginxLocationVersion.AppInstalledId.Eq(appInstalled.ID),
+			repo.NginxLocationVersion.Version.Eq(req.Version),
+		).First()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, nil, nil, errors.New(constant.ErrNginxVersionNotFound)
+			}
+			return nil, nil, nil, nil, err
+		}
+		template = version.Template
+	}
+
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return nil, nil, nil, nil, errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+
+	current, _, err := currentNginxLocation(client, appInstalled, appDetail)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	locationConfig := nginx.NewLocationConfig(appInstalled.Key, appInstalled.Name).
+		WithTemplate(template).
+		WithPort(current.Port).
+		WithServerName(current.ServerName).
+		WithCertificate(current.Certificate).
+		WithOptions(current.Options)
+	if locationConfig.Mode == nginx.LocationModeHost && locationConfig.ServerName == "" {
+		return nil, nil, nil, nil, errors.New(constant.ErrNginxDomainRequired)
+	}
+	if appDetail.NginxConfig == "" && current.Template == "" {
+		// 插件原本没有Nginx配置，失败时无需恢复
+		current = nil
+	}
+
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return nil, nil, nil, nil, err
+	}
+	return appInstalled, current, nm, locationConfig, nil
+}
+
+// currentNginxLocation 获取插件当前使用的location配置，有历史版本时使用当前版本的模板，否则使用插件自带的模板
+func currentNginxLocation(client docker.Client, appInstalled *model.AppInstalled, appDetail *model.AppDetail) (*nginx.LocationConfig, *model.NginxLocationVersion, error) {
+	locationConfig, err := pluginHelper.NginxLocationConfig(client, appInstalled, appDetail)
+	if err != nil {
+		return nil, nil, err
+	}
+	version, err := repo.NginxLocationVersion.Where(
+		repo.NginxLocationVersion.AppInstalledId.Eq(appInstalled.ID),
+		repo.NginxLocationVersion.Active.Is(true),
+	).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return locationConfig, nil, nil
+		}
+		return nil, nil, err
+	}
+	return locationConfig.WithTemplate(version.Template), version, nil
+}
+
+// currentNginxTemplate 获取插件当前使用的Nginx模板
+func currentNginxTemplate(appInstalled *model.AppInstalled) string {
+	version, err := repo.NginxLocationVersion.Select(repo.NginxLocationVersion.Template).Where(
+		repo.NginxLocationVersion.AppInstalledId.Eq(appInstalled.ID),
+		repo.NginxLocationVersion.Active.Is(true),
+	).First()
+	if err == nil {
+		return version.Template
+	}
+	appDetail, err := repo.AppDetail.Select(repo.AppDetail.NginxConfig).Where(repo.AppDetail.ID.Eq(appInstalled.AppDetailID)).First()
+	if err != nil {
+		return ""
+	}
+	return appDetail.NginxConfig
+}
+
+// saveNginxVersion 保存渲染后的location配置为插件的新版本，并设为当前使用的版本
+func saveNginxVersion(nm *nginx.NginxManager, appInstalled *model.AppInstalled, locationConfig *nginx.LocationConfig, remark string) (*model.NginxLocationVersion, error) {
+	content, err := nm.Render(locationConfig)
+	if err != nil {
+		return nil, err
+	}
+	version := &model.NginxLocationVersion{
+		AppInstalledId: appInstalled.ID,
+		Key:            appInstalled.Key,
+		Template:       locationConfig.Template,
+		Content:        content,
+		Port:           locationConfig.Port,
+		Active:         true,
+		Remark:         remark,
+	}
+	err = repo.DB.Transaction(func(tx *gorm.DB) error {
+		query := repo.Use(tx).NginxLocationVersion
+		latest, err := query.Where(query.AppInstalledId.Eq(appInstalled.ID)).Order(query.Version.Desc()).First()
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		version.Version = 1
+		if latest != nil {
+			version.Version = latest.Version + 1
+		}
+		if _, err = query.Where(query.AppInstalledId.Eq(appInstalled.ID), query.Active.Is(true)).Update(query.Active, false); err != nil {
+			return err
+		}
+		return query.Create(version)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// ReconcileNginx 根据已安装的插件生成期望的location配置，并与Nginx容器中的配置进行核对
+func ReconcileNginx() (*nginx.ReconcileReport, error) {
+	client, err := docker.NewClient()
+	if err != nil {
+		log.Error("创建Docker客户端失败:", err)
+		return nil, errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+
+	appInstalledList, err := repo.AppInstalled.Find()
+	if err != nil {
+		return nil, err
+	}
+	details := map[int64]*model.AppDetail{}
+	appDetails, err := repo.AppDetail.Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, appDetail := range appDetails {
+		details[appDetail.ID] = appDetail
+	}
+
+	desired := []*nginx.LocationConfig{}
+	keep := []string{}
+	// 升级前安装的插件没有版本记录
+	unversioned := map[string]*model.AppInstalled{}
+	for _, appInstalled := range appInstalledList {
+		appDetail, ok := details[appInstalled.AppDetailID]
+		if !ok {
+			continue
+		}
+		// 安装中的插件会在安装完成后自行添加配置
+		if appInstalled.Status == model.PluginStatusInstalling {
+			keep = append(keep, appInstalled.Key)
+			continue
+		}
+		locationConfig, version, err := currentNginxLocation(client, appInstalled, appDetail)
+		if err != nil {
+			log.Warnf("生成插件 %s 的Nginx配置失败: %v", appInstalled.Key, err)
+			keep = append(keep, appInstalled.Key)
+			continue
+		}
+		if locationConfig.Template == "" {
+			continue
+		}
+		if version == nil {
+			unversioned[appInstalled.Key] = appInstalled
+		}
+		desired = append(desired, locationConfig)
+	}
+
+	nm, err := nginx.NewNginxManager()
+	if err != nil {
+		log.Error("创建Nginx管理器失败:", err)
+		return nil, err
+	}
+	// 先写入缺失的证书，配置中引用的证书文件不存在时Nginx配置检测会失败
+	syncCertificates(nm)
+	report, err := nm.Reconcile(desired, keep)
+	if err != nil {
+		return report, err
+	}
+	for _, locationConfig := range desired {
+		if appInstalled, ok := unversioned[locationConfig.Name]; ok {
+			if _, err := saveNginxVersion(nm, appInstalled, locationConfig, "同步已有配置"); err != nil {
+				log.Warnf("保存插件 %s 的Nginx配置版本失败: %v", appInstalled.Key, err)
+			}
+		}
+	}
+	return report, nil
+}
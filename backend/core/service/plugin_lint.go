@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/jsonschema"
+	"doo-store/backend/utils/nginx"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// LintLevel 检查结果级别
+type LintLevel string
+
+const (
+	LintError   LintLevel = "error"
+	LintWarning LintLevel = "warning"
+)
+
+// LintIssue 插件清单检查发现的问题
+type LintIssue struct {
+	Plugin  string    // 插件 key
+	Level   LintLevel // 级别
+	Path    string    // 问题位置
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s %s: %s", i.Level, i.Plugin, i.Path, i.Message)
+}
+
+// PluginLinter 插件清单检查，依次检查 JSON Schema、docker-compose、nginx 模板与表单字段
+type PluginLinter struct {
+	schema *jsonschema.Schema
+}
+
+// NewPluginLinter 创建插件清单检查器
+func NewPluginLinter() (*PluginLinter, error) {
+	schema, err := jsonschema.Compile(dto.PluginSchema)
+	if err != nil {
+		return nil, err
+	}
+	return &PluginLinter{schema: schema}, nil
+}
+
+// Lint 检查插件清单，支持单个插件或 data.json（{"plugins": [...]}）
+func (l *PluginLinter) Lint(data []byte) ([]LintIssue, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	manifests := []interface{}{document}
+	if object, ok := document.(map[string]interface{}); ok {
+		if plugins, ok := object["plugins"].([]interface{}); ok {
+			manifests = plugins
+		}
+	}
+
+	issues := []LintIssue{}
+	for i, manifest := range manifests {
+		name := fmt.Sprintf("#%d", i)
+		if object, ok := manifest.(map[string]interface{}); ok {
+			if key, ok := object["key"].(string); ok && key != "" {
+				name = key
+			}
+		}
+		issues = append(issues, l.lintPlugin(name, manifest)...)
+	}
+	return issues, nil
+}
+
+func (l *PluginLinter) lintPlugin(name string, manifest interface{}) []LintIssue {
+	issues := []LintIssue{}
+	add := func(level LintLevel, path, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Plugin: name, Level: level, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, err := range l.schema.ValidateValue(manifest) {
+		add(LintError, err.Path, "%s", err.Message)
+	}
+	if len(issues) > 0 {
+		return issues
+	}
+
+	data, _ := json.Marshal(manifest)
+	var plugin dto.Plugin
+	if err := json.Unmarshal(data, &plugin); err != nil {
+		add(LintError, "/", "%v", err)
+		return issues
+	}
+
+	// docker-compose 文件与策略检查
+	composeContent := plugin.GenComposeFile()
+	if plugin.DockerCompose == "" && plugin.Repo == "" {
+		add(LintError, "/repo", "repo is required when docker_compose is empty")
+	}
+	if _, err := compose.PreCheck(composeContent); err != nil {
+		add(LintError, "/docker_compose", "%v", err)
+	}
+	if !strings.Contains(composeContent, "${CONTAINER_NAME}") {
+		add(LintWarning, "/docker_compose", "container_name should be ${CONTAINER_NAME}")
+	}
+
+	// nginx 模板试渲染
+	nginxConfig := plugin.GenNginxConfig()
+	if plugin.NginxAuth && plugin.NginxConfig != "" && !strings.Contains(plugin.NginxConfig, ".AuthRequest") {
+		add(LintWarning, "/nginx_auth", "nginx_auth is ignored when nginx_config is set, use {{.AuthLocation}} and {{.AuthRequest}} in the template instead")
+	}
+	if plugin.NginxRootPath && nginx.DetectMode(nginxConfig) != nginx.LocationModeHost {
+		add(LintWarning, "/nginx_root_path", "nginx_root_path is ignored because nginx_config has no server block")
+	}
+	if nginx.DetectMode(nginxConfig) == nginx.LocationModeHost && (!strings.Contains(nginxConfig, ".TLS") || !strings.Contains(nginxConfig, ".ACMEChallenge")) {
+		add(LintWarning, "/nginx_config", "server block without {{.TLS}} and {{.ACMEChallenge}} cannot be served over HTTPS")
+	}
+	if strings.Contains(nginxConfig, ".AuthRequest") && !strings.Contains(nginxConfig, ".AuthLocation") {
+		add(LintError, "/nginx_config", "{{.AuthRequest}} requires {{.AuthLocation}} outside of the location block")
+	}
+	t, err := template.New("nginx").Option("missingkey=error").Parse(nginxConfig)
+	if err != nil {
+		add(LintError, "/nginx_config", "%v", err)
+	} else {
+		var buf bytes.Buffer
+		locationConfig := nginx.NewLocationConfig(plugin.Key, "plugin-container").WithPort(80).WithServerName(plugin.Key + ".example.com")
+		if err := t.Execute(&buf, locationConfig.TemplateData()); err != nil {
+			add(LintError, "/nginx_config", "%v", err)
+		} else if strings.Count(buf.String(), "{") != strings.Count(buf.String(), "}") {
+			add(LintError, "/nginx_config", "unbalanced braces in rendered config")
+		}
+	}
+
+	// 表单字段
+	formFields := make([]*dto.FormField, 0, len(plugin.Env))
+	for i := range plugin.Env {
+		formFields = append(formFields, &plugin.Env[i].FormField)
+	}
+	fields := dto.FlattenFields(formFields)
+	usesEnvFile := regexp.MustCompile(`(?m)^\s*env_file\s*:`).MatchString(composeContent)
+	seen := map[string]bool{}
+	for i, field := range formFields {
+		path := fmt.Sprintf("/env/%d", i)
+		if seen[field.EnvKey] {
+			add(LintError, path, "duplicate env_key %s", field.EnvKey)
+		}
+		seen[field.EnvKey] = true
+		if field.Dependency != nil {
+			if _, ok := fields[field.Dependency.Field]; !ok {
+				add(LintError, path+"/dependency", "depends on undeclared field %s", field.Dependency.Field)
+			}
+		}
+		if field.Validation != nil && field.Validation.Pattern != "" {
+			if _, err := regexp.Compile(field.Validation.Pattern); err != nil {
+				add(LintError, path+"/validation/pattern", "%v", err)
+			}
+		}
+	}
+	envKeys := make([]string, 0, len(fields))
+	for envKey := range fields {
+		envKeys = append(envKeys, envKey)
+	}
+	sort.Strings(envKeys)
+	for _, envKey := range envKeys {
+		if usesEnvFile {
+			break
+		}
+		if !envReferenced(composeContent, envKey) {
+			add(LintWarning, "/env", "env_key %s is not referenced by the compose file", envKey)
+		}
+	}
+	for i, rule := range plugin.Rules {
+		for _, envKey := range rule.Fields {
+			if _, ok := fields[envKey]; !ok {
+				add(LintError, fmt.Sprintf("/rules/%d", i), "rule references undeclared field %s", envKey)
+			}
+		}
+	}
+	return issues
+}
+
+// envReferenced 判断 compose 文件是否引用了环境变量，支持 $KEY、${KEY} 与 ${KEY:-default} 等形式
+func envReferenced(content, envKey string) bool {
+	re := regexp.MustCompile(`\$\{` + regexp.QuoteMeta(envKey) + `([:?}-]|$)|\$` + regexp.QuoteMeta(envKey) + `\b`)
+	return re.MatchString(content)
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/docker"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// 插件开发目录中的文件
+const (
+	ScaffoldManifestFile = "manifest.yaml"      // 插件清单，字段与 data.json 中的插件一致
+	ScaffoldComposeFile  = "docker-compose.yml" // docker-compose 文件
+	ScaffoldNginxFile    = "nginx.conf.tmpl"    // nginx location 模板
+	ScaffoldI18nDir      = "i18n"               // 多语言文件目录，文件名为语言代码，如 en.yaml
+)
+
+// 插件图标文件，清单中未设置 icon 时按顺序查找
+var scaffoldIconFiles = []string{"icon.svg", "icon.png", "icon.jpg"}
+
+// PluginScaffold 插件开发目录，用于生成模板、打包与本地试装
+type PluginScaffold struct {
+	dir string
+}
+
+// scaffoldI18n 多语言文件内容
+type scaffoldI18n struct {
+	Name        string                       `yaml:"name"`
+	Description string                       `yaml:"description"`
+	Fields      map[string]scaffoldI18nField `yaml:"fields"` // 以环境变量名为键
+}
+
+type scaffoldI18nField struct {
+	Label       string            `yaml:"label"`
+	Placeholder string            `yaml:"placeholder"`
+	Options     map[string]string `yaml:"options"` // 以选项值为键
+}
+
+// NewPluginScaffold 创建插件开发目录实例
+func NewPluginScaffold(dir string) *PluginScaffold {
+	return &PluginScaffold{dir: dir}
+}
+
+// Init 生成插件开发目录，目录已存在且不为空时返回错误
+func (s *PluginScaffold) Init(key, name string) error {
+	if entries, err := os.ReadDir(s.dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory %s is not empty", s.dir)
+	}
+	files := map[string]string{
+		ScaffoldManifestFile: fmt.Sprintf(scaffoldManifestTemplate, name, key),
+		ScaffoldComposeFile:  fmt.Sprintf(scaffoldComposeTemplate, key),
+		ScaffoldNginxFile:    scaffoldNginxTemplate,
+		"icon.svg":           scaffoldIconTemplate,
+		filepath.Join(ScaffoldI18nDir, "en.yaml"): fmt.Sprintf(scaffoldI18nTemplate, name),
+	}
+	for file, content := range files {
+		target := filepath.Join(s.dir, file)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pack 读取插件开发目录并生成可上传的插件清单，同时返回清单检查结果
+func (s *PluginScaffold) Pack() (*dto.Plugin, []LintIssue, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, ScaffoldManifestFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", ScaffoldManifestFile, err)
+	}
+	jsonData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+	plugin := &dto.Plugin{}
+	if err := json.Unmarshal(jsonData, plugin); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", ScaffoldManifestFile, err)
+	}
+
+	if content, err := os.ReadFile(filepath.Join(s.dir, ScaffoldComposeFile)); err == nil {
+		plugin.DockerCompose = string(content)
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	if content, err := os.ReadFile(filepath.Join(s.dir, ScaffoldNginxFile)); err == nil {
+		plugin.NginxConfig = string(content)
+	} else if !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	if plugin.Icon == "" {
+		if plugin.Icon, err = s.readIcon(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.applyI18n(plugin); err != nil {
+		return nil, nil, err
+	}
+
+	linter, err := NewPluginLinter()
+	if err != nil {
+		return nil, nil, err
+	}
+	packed, err := json.Marshal(plugin)
+	if err != nil {
+		return nil, nil, err
+	}
+	issues, err := linter.Lint(packed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plugin, issues, nil
+}
+
+// readIcon 读取目录中的图标，转换为 data URL
+func (s *PluginScaffold) readIcon() (string, error) {
+	for _, file := range scaffoldIconFiles {
+		content, err := os.ReadFile(filepath.Join(s.dir, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		mimeType := mime.TypeByExtension(filepath.Ext(file))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(content)), nil
+	}
+	return "", nil
+}
+
+// applyI18n 将 i18n 目录中的翻译合并到清单的多语言字段
+func (s *PluginScaffold) applyI18n(plugin *dto.Plugin) error {
+	files, err := filepath.Glob(filepath.Join(s.dir, ScaffoldI18nDir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		lang := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		translation := scaffoldI18n{}
+		if err := yaml.Unmarshal(content, &translation); err != nil {
+			return fmt.Errorf("invalid %s: %w", file, err)
+		}
+		setI18n(&plugin.NameI18n, lang, translation.Name)
+		setI18n(&plugin.DescriptionI18n, lang, translation.Description)
+		for i := range plugin.Env {
+			applyFieldI18n(&plugin.Env[i].FormField, lang, translation.Fields)
+		}
+	}
+	return nil
+}
+
+func applyFieldI18n(field *dto.FormField, lang string, fields map[string]scaffoldI18nField) {
+	translation, ok := fields[field.EnvKey]
+	if ok {
+		setI18n(&field.LabelI18n, lang, translation.Label)
+		setI18n(&field.PlaceholderI18n, lang, translation.Placeholder)
+	}
+	for i := range field.Options {
+		option := &field.Options[i]
+		if ok {
+			setI18n(&option.LabelI18n, lang, translation.Options[option.Value])
+		}
+		for j := range option.SubFields {
+			applyFieldI18n(&option.SubFields[j], lang, fields)
+		}
+	}
+}
+
+func setI18n(text *model.I18nText, lang, value string) {
+	if value == "" {
+		return
+	}
+	if *text == nil {
+		*text = model.I18nText{}
+	}
+	(*text)[lang] = value
+}
+
+// Try 将插件写入本地商店并使用安装流程同步安装，安装完成后输出容器日志
+// 商店中已存在同名插件时需要 force 为 true 才会覆盖，params 中未设置的字段使用默认值
+// 安装记录创建后的步骤失败时会卸载插件，避免留下无法再次试装的安装记录
+func (s *PluginScaffold) Try(plugin *dto.Plugin, params map[string]interface{}, follow, force bool, out io.Writer) error {
+	count, err := repo.AppInstalled.Where(repo.AppInstalled.Key.Eq(plugin.Key)).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("plugin %s is already installed, uninstall it first", plugin.Key)
+	}
+	if err := s.replaceCatalog(plugin, force); err != nil {
+		return err
+	}
+
+	installParams := map[string]interface{}{}
+	for _, env := range plugin.Env {
+		if env.Default != nil {
+			installParams[env.EnvKey] = env.Default
+		}
+	}
+	for key, value := range params {
+		installParams[key] = value
+	}
+	process := NewAppInstallProcess(dto.ServiceContext{}, request.AppInstall{
+		Key:           plugin.Key,
+		DockerCompose: plugin.GenComposeFile(),
+		CPUS:          "0",
+		MemoryLimit:   "0",
+		Params:        installParams,
+	})
+	steps := []func() error{
+		process.ValidateInstallRequirements,
+		process.DHCP,
+		process.ValidateParam,
+		process.Install,
+		process.AddNginx,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			var vErrs dto.ValidationErrors
+			if errors.As(err, &vErrs) {
+				for _, vErr := range vErrs {
+					fmt.Fprintf(out, "%s: %s %v\n", vErr.EnvKey, vErr.MessageID, vErr.Data)
+				}
+			}
+			// 安装记录未创建时释放分配的IP，已创建时卸载插件
+			if process.appInstalled == nil || process.appInstalled.ID == 0 {
+				process.ReleaseIP()
+				return err
+			}
+			if uErr := NewIAppService().UninstallApp(dto.ServiceContext{}, request.AppUnInstall{Key: plugin.Key}); uErr != nil {
+				fmt.Fprintf(out, "插件 %s 安装失败后卸载失败: %v\n", plugin.Key, uErr)
+			} else {
+				fmt.Fprintf(out, "插件 %s 安装失败，已卸载\n", plugin.Key)
+			}
+			return err
+		}
+	}
+	fmt.Fprintf(out, "插件 %s 已安装，容器 %s\n", plugin.Key, process.containerName)
+	return s.tailLogs(process.containerName, follow, out)
+}
+
+// replaceCatalog 将插件写入商店目录，商店中已存在同名插件时只有 force 为 true 才会覆盖
+func (s *PluginScaffold) replaceCatalog(plugin *dto.Plugin, force bool) error {
+	app, err := repo.App.Where(repo.App.Key.Eq(plugin.Key)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if app != nil && !force {
+		return fmt.Errorf("plugin %s already exists in the store, use --force to replace it", plugin.Key)
+	}
+	if app != nil {
+		log.Info("覆盖商店中已存在的插件:", plugin.Key)
+		err = repo.DB.Transaction(func(tx *gorm.DB) error {
+			q := repo.Use(tx)
+			if _, err := q.AppDetail.Where(q.AppDetail.AppID.Eq(app.ID)).Delete(); err != nil {
+				return err
+			}
+			if _, err := q.AppTag.Where(q.AppTag.AppID.Eq(app.ID)).Delete(); err != nil {
+				return err
+			}
+			_, err := q.App.Where(q.App.ID.Eq(app.ID)).Delete()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return NewIAppService().UploadApp(dto.ServiceContext{}, request.PluginUpload{Plugin: *plugin})
+}
+
+// tailLogs 输出容器日志，follow 为 true 时持续输出直到容器停止
+func (s *PluginScaffold) tailLogs(containerName string, follow bool, out io.Writer) error {
+	client, err := docker.NewDockerClient()
+	if err != nil {
+		return errors.New(constant.ErrDockerClientCreate)
+	}
+	defer client.Close()
+	reader, err := client.ContainerLogs(context.Background(), containerName, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       "100",
+		Follow:     follow,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = stdcopy.StdCopy(out, out, reader)
+	return err
+}
+
+const scaffoldManifestTemplate = `# 插件清单，字段与 data.json 中的插件一致
+# docker_compose、nginx_config 分别读取 docker-compose.yml 与 nginx.conf.tmpl，icon 留空时使用目录中的 icon.svg
+name: %s
+key: %s
+version: 0.1.0
+description: ""
+class: tools
+depends_version: ""
+repo: ""
+github: ""
+icon: ""
+env:
+  - label: 欢迎语
+    env_key: WELCOME_TEXT
+    type: text
+    default: Hello DooTask
+    order: 1
+    validation:
+      required: true
+      max_len: 100
+`
+
+const scaffoldComposeTemplate = `services:
+  %s:
+    image: nginx:alpine
+    restart: unless-stopped
+    container_name: ${CONTAINER_NAME}
+    environment:
+      - WELCOME_TEXT=${WELCOME_TEXT}
+    networks:
+      ${DOOTASK_NETWORK_NAME}:
+        ipv4_address: ${IP_ADDRESS}
+        # 双栈网络中可以同时使用商店分配的IPv6地址
+        # ipv6_address: ${IP6_ADDRESS}
+    cpus: "${CPUS}"
+    mem_limit: "${MEMORY_LIMIT}"
+    labels:
+      createdBy: "Apps"
+
+networks:
+  ${DOOTASK_NETWORK_NAME}:
+    external: true
+`
+
+const scaffoldNginxTemplate = `location /plugin/{{.Key}}/ {
+	proxy_http_version 1.1;
+	proxy_set_header X-Real-IP $remote_addr;
+	proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+	proxy_set_header Host $http_host;
+	proxy_set_header Upgrade $http_upgrade;
+	proxy_set_header Connection $connection_upgrade;
+	proxy_pass http://{{.ContainerName}}:80/;
+}
+`
+
+const scaffoldIconTemplate = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64"><rect width="64" height="64" rx="12" fill="#8bcf70"/></svg>
+`
+
+const scaffoldI18nTemplate = `# 英文翻译，文件名为语言代码
+name: %s
+description: ""
+fields:
+  WELCOME_TEXT:
+    label: Welcome text
+`
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/dto/request"
+	"doo-store/backend/core/dto/response"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/task"
+	"errors"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gen"
+)
+
+type StatusHistoryService struct {
+}
+
+type IStatusHistoryService interface {
+	ListStatusHistory(ctx dto.ServiceContext, req request.AppStatusHistorySearch) (*dto.PageResult, error)
+	GetUptime(ctx dto.ServiceContext, req request.AppUptimeSearch) (*response.AppUptime, error)
+}
+
+func NewIStatusHistoryService() IStatusHistoryService {
+	return &StatusHistoryService{}
+}
+
+// ListStatusHistory 获取插件及其服务的状态变化记录，按时间倒序排列
+func (*StatusHistoryService) ListStatusHistory(ctx dto.ServiceContext, req request.AppStatusHistorySearch) (*dto.PageResult, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 1000 {
+		req.PageSize = 100
+	}
+
+	query := repo.AppStatusTransition.Where(repo.AppStatusTransition.InstallID.Eq(appInstalled.ID))
+	if req.Kind != "" {
+		query = query.Where(repo.AppStatusTransition.Kind.Eq(req.Kind))
+	}
+	if req.Since > 0 {
+		query = query.Where(repo.AppStatusTransition.CreatedAt.Gte(time.Unix(req.Since, 0)))
+	}
+	if req.Until > 0 {
+		query = query.Where(repo.AppStatusTransition.CreatedAt.Lte(time.Unix(req.Until, 0)))
+	}
+	items, count, err := query.Order(repo.AppStatusTransition.ID.Desc()).FindByPage((req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		log.Info("查询状态变化记录失败", err)
+		return nil, err
+	}
+	return &dto.PageResult{
+		Total: count,
+		Items: items,
+	}, nil
+}
+
+// GetUptime 根据插件的状态变化记录统计可用率与平均故障间隔，
+// 只有 Running 计为运行正常，停止与安装中的时间不计入统计
+func (*StatusHistoryService) GetUptime(ctx dto.ServiceContext, req request.AppUptimeSearch) (*response.AppUptime, error) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(req.InstalledId)).First()
+	if err != nil {
+		log.Info("Error query app installed", err)
+		return nil, errors.New(constant.ErrPluginInfoFailed)
+	}
+	end := time.Now()
+	start := end.Add(-time.Duration(req.Hours) * time.Hour)
+	conditions := []gen.Condition{
+		repo.AppStatusTransition.InstallID.Eq(appInstalled.ID),
+		repo.AppStatusTransition.Kind.Eq(model.TransitionKindApp),
+	}
+
+	// 统计开始时的状态取自之前的最后一条记录
+	status := ""
+	since := start
+	before, err := repo.AppStatusTransition.Where(conditions...).Where(repo.AppStatusTransition.CreatedAt.Lt(start)).
+		Order(repo.AppStatusTransition.ID.Desc()).First()
+	if err == nil {
+		status = before.To
+	}
+	transitions, err := repo.AppStatusTransition.Where(conditions...).Where(repo.AppStatusTransition.CreatedAt.Gte(start)).
+		Order(repo.AppStatusTransition.ID).Find()
+	if err != nil {
+		log.Info("查询状态变化记录失败", err)
+		return nil, err
+	}
+
+	uptime := &response.AppUptime{
+		Start:         start,
+		End:           end,
+		StatusSeconds: map[string]int64{},
+	}
+	durations := map[string]time.Duration{}
+	for _, transition := range transitions {
+		if status != "" {
+			durations[status] += transition.CreatedAt.Sub(since)
+		}
+		if status == model.PluginStatusRunning && isFailureStatus(transition.To) {
+			uptime.Failures++
+		}
+		status = transition.To
+		since = transition.CreatedAt
+	}
+	if status != "" {
+		durations[status] += end.Sub(since)
+	}
+
+	var observed time.Duration
+	for status, duration := range durations {
+		uptime.StatusSeconds[status] = int64(duration.Seconds())
+		if status != model.PluginStatusStopped && status != model.PluginStatusInstalling {
+			observed += duration
+		}
+	}
+	up := durations[model.PluginStatusRunning]
+	uptime.ObservedSeconds = int64(observed.Seconds())
+	uptime.UpSeconds = int64(up.Seconds())
+	if observed > 0 {
+		uptime.UptimePercent = math.Round(float64(up)/float64(observed)*10000) / 100
+	}
+	if uptime.Failures > 0 {
+		mtbf := int64(up.Seconds()) / int64(uptime.Failures)
+		uptime.MTBFSeconds = &mtbf
+	}
+	return uptime, nil
+}
+
+// isFailureStatus 是否为故障状态
+func isFailureStatus(status string) bool {
+	return status == model.PluginStatusError || status == model.PluginStatusDead || status == model.PluginStatusUnHealthy
+}
+
+// recordAppStatus 记录插件操作引起的状态变化
+func recordAppStatus(appInstalled *model.AppInstalled, status, message string) {
+	task.RecordStatusTransition(&model.AppStatusTransition{
+		InstallID:     appInstalled.ID,
+		Kind:          model.TransitionKindApp,
+		ContainerName: appInstalled.Name,
+		To:            status,
+		Message:       message,
+	})
+}
+
+// recordServiceStatuses 记录插件操作后各服务容器的状态
+func recordServiceStatuses(appInstalled *model.AppInstalled) {
+	services, err := repo.AppServiceStatus.Where(repo.AppServiceStatus.InstallID.Eq(appInstalled.ID)).Find()
+	if err != nil {
+		log.Info("查询服务信息失败", err)
+		return
+	}
+	for _, service := range services {
+		task.RecordStatusTransition(&model.AppStatusTransition{
+			InstallID:     service.InstallID,
+			Kind:          model.TransitionKindService,
+			ContainerName: service.ContainerName,
+			ServiceName:   service.ServiceName,
+			To:            service.Status,
+			Message:       service.Message,
+		})
+	}
+}
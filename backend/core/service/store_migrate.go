@@ -0,0 +1,427 @@
+package service
+
+import (
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/archive"
+	"doo-store/backend/utils/compose"
+	"doo-store/backend/utils/cron"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// StoreMigrator 整站导出与导入，用于将插件商店迁移到新的主机
+type StoreMigrator struct {
+}
+
+var storeMigrator = StoreMigrator{}
+
+// NewStoreMigrator 创建整站迁移实例
+func NewStoreMigrator() StoreMigrator {
+	return storeMigrator
+}
+
+// Export 将插件目录、已安装插件（参数、环境变量、Nginx location、IP）以及可选的卷数据导出到 dst
+// 新主机的加密密钥与源主机不同，参数中的敏感信息解密后导出，导入时按新主机的密钥重新加密，
+// 因此导出文件中包含明文的敏感信息，文件权限为 0600
+func (m StoreMigrator) Export(dst string, withVolumes bool) error {
+	log.Info("开始整站导出:", dst)
+	manifest, err := m.buildManifest(withVolumes)
+	if err != nil {
+		log.Error("生成整站导出清单失败:", err)
+		return errors.New(constant.ErrStoreExportFailed)
+	}
+	installedList, err := repo.AppInstalled.Find()
+	if err != nil {
+		log.Error("查询已安装插件失败:", err)
+		return errors.New(constant.ErrStoreExportFailed)
+	}
+
+	writer, err := archive.NewTarGzWriter(dst)
+	if err != nil {
+		log.Error("创建导出文件失败:", err)
+		return errors.New(constant.ErrStoreExportFailed)
+	}
+	if err := os.Chmod(dst, 0600); err != nil {
+		log.Warn("修改导出文件权限失败:", err)
+	}
+	err = func() error {
+		for _, appInstalled := range installedList {
+			if appInstalled.Status == model.PluginStatusInstalling {
+				log.Warnf("插件 %s 正在安装中，跳过导出", appInstalled.Key)
+				continue
+			}
+			unlock, err := appBackupManager.Lock(appInstalled.Key)
+			if err != nil {
+				return fmt.Errorf("plugin %s is being backed up: %w", appInstalled.Key, err)
+			}
+			log.Info("导出插件:", appInstalled.Key)
+			dir := path.Join(dto.StoreInstalledDir, appInstalled.Key)
+			err = appBackupManager.WriteArchive(writer, dir, appInstalled, withVolumes)
+			unlock()
+			if err != nil {
+				return fmt.Errorf("export plugin %s failed: %w", appInstalled.Key, err)
+			}
+
+			installed := dto.StoreInstalled{
+				Key:    appInstalled.Key,
+				Dir:    dir,
+				Status: appInstalled.Status,
+			}
+			schedule, err := repo.AppBackupSchedule.Where(repo.AppBackupSchedule.AppInstalledId.Eq(appInstalled.ID)).First()
+			if err == nil {
+				installed.BackupSchedule = &dto.StoreBackupSchedule{
+					Cron:      schedule.Cron,
+					Retention: schedule.Retention,
+					Enabled:   schedule.Enabled,
+				}
+			}
+			manifest.Installed = append(manifest.Installed, installed)
+		}
+
+		manifestJson, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writer.AddBytes(dto.StoreManifestFile, manifestJson)
+	}()
+	if err != nil {
+		log.Error("整站导出失败:", err)
+		_ = writer.Close()
+		_ = os.Remove(dst)
+		return errors.New(constant.ErrStoreExportFailed)
+	}
+	if err := writer.Close(); err != nil {
+		log.Error("写入导出文件失败:", err)
+		_ = os.Remove(dst)
+		return errors.New(constant.ErrStoreExportFailed)
+	}
+	log.Infof("整站导出完成, 插件目录 %d 个, 已安装插件 %d 个", len(manifest.Apps), len(manifest.Installed))
+	return nil
+}
+
+// Import 在新主机上导入整站归档，导入插件目录后逐个恢复已安装的插件
+// 与 APP_ID、APP_IPPR 相关的值（容器名、网络名、IP地址）会被替换为当前主机的配置
+func (m StoreMigrator) Import(src string) error {
+	log.Info("开始整站导入:", src)
+	data, err := archive.ReadFile(src, dto.StoreManifestFile)
+	if err != nil {
+		log.Error("读取整站导出清单失败:", err)
+		return errors.New(constant.ErrStoreManifestInvalid)
+	}
+	manifest := &dto.StoreManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		log.Error("解析整站导出清单失败:", err)
+		return errors.New(constant.ErrStoreManifestInvalid)
+	}
+	if manifest.FormatVersion <= 0 || manifest.FormatVersion > dto.StoreFormatVersion {
+		log.Error("整站导出清单格式不支持:", manifest.FormatVersion)
+		return errors.New(constant.ErrStoreManifestInvalid)
+	}
+
+	count, err := repo.AppInstalled.Count()
+	if err != nil {
+		log.Error("查询已安装插件失败:", err)
+		return errors.New(constant.ErrStoreImportFailed)
+	}
+	if count > 0 {
+		return errors.New(constant.ErrStoreNotEmpty)
+	}
+
+	if err := m.importCatalog(manifest); err != nil {
+		log.Error("导入插件目录失败:", err)
+		return errors.New(constant.ErrStoreImportFailed)
+	}
+
+	failed := []string{}
+	for _, installed := range manifest.Installed {
+		if err := m.importInstalled(src, manifest, installed); err != nil {
+			log.Errorf("导入插件 %s 失败: %v", installed.Key, err)
+			failed = append(failed, installed.Key)
+		}
+	}
+	if len(failed) > 0 {
+		log.Error("以下插件导入失败:", strings.Join(failed, ","))
+		return errors.New(constant.ErrStoreImportFailed)
+	}
+	log.Infof("整站导入完成, 插件目录 %d 个, 已安装插件 %d 个", len(manifest.Apps), len(manifest.Installed))
+	return nil
+}
+
+// buildManifest 生成整站导出清单中的插件目录部分
+func (m StoreMigrator) buildManifest(withVolumes bool) (*dto.StoreManifest, error) {
+	manifest := &dto.StoreManifest{
+		FormatVersion:      dto.StoreFormatVersion,
+		AppID:              config.EnvConfig.APP_ID,
+		AppIPPR:            config.EnvConfig.APP_IPPR,
+		NetworkName:        config.EnvConfig.App().NETWORK_NAME,
+		NginxContainerName: config.EnvConfig.GetNginxContainerName(),
+		WithVolumes:        withVolumes,
+		Tags:               []dto.StoreTag{},
+		Apps:               []dto.StoreApp{},
+		Installed:          []dto.StoreInstalled{},
+		CreatedAt:          time.Now(),
+	}
+
+	tags, err := repo.Tag.Find()
+	if err != nil {
+		return nil, err
+	}
+	tagKeys := map[int64]string{}
+	for _, tag := range tags {
+		tagKeys[tag.ID] = tag.Key
+		manifest.Tags = append(manifest.Tags, dto.StoreTag{
+			Key:  tag.Key,
+			Name: tag.Name,
+			Sort: tag.Sort,
+		})
+	}
+	appTags, err := repo.AppTag.Find()
+	if err != nil {
+		return nil, err
+	}
+	appTagKeys := map[int64][]string{}
+	for _, appTag := range appTags {
+		if key, ok := tagKeys[appTag.TagID]; ok {
+			appTagKeys[appTag.AppID] = append(appTagKeys[appTag.AppID], key)
+		}
+	}
+
+	apps, err := repo.App.Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		appDetail, err := repo.AppDetail.Where(repo.AppDetail.AppID.Eq(app.ID)).First()
+		if err != nil {
+			log.Warnf("插件 %s 缺少详情，跳过导出", app.Key)
+			continue
+		}
+		manifest.Apps = append(manifest.Apps, dto.StoreApp{
+			Name:            app.Name,
+			Key:             app.Key,
+			Icon:            app.Icon,
+			Description:     app.Description,
+			NameI18n:        app.NameI18n,
+			DescriptionI18n: app.DescriptionI18n,
+			Github:          app.Github,
+			Class:           app.Class,
+			DependsVersion:  app.DependsVersion,
+			Sort:            app.Sort,
+			Tags:            appTagKeys[app.ID],
+			Repo:            appDetail.Repo,
+			Version:         appDetail.Version,
+			Params:          appDetail.Params,
+			DockerCompose:   appDetail.DockerCompose,
+			NginxConfig:     appDetail.NginxConfig,
+			HealthCheck:     appDetail.HealthCheck,
+			DetailStatus:    appDetail.Status,
+		})
+	}
+	return manifest, nil
+}
+
+// importCatalog 导入插件目录，已存在的插件按 key 覆盖
+func (m StoreMigrator) importCatalog(manifest *dto.StoreManifest) error {
+	return repo.DB.Transaction(func(tx *gorm.DB) error {
+		q := repo.Use(tx)
+		tagIDs := map[string]int64{}
+		for _, storeTag := range manifest.Tags {
+			tag, err := q.Tag.Where(q.Tag.Key.Eq(storeTag.Key)).First()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if tag == nil {
+				tag = &model.Tag{Key: storeTag.Key}
+			}
+			tag.Name = storeTag.Name
+			tag.Sort = storeTag.Sort
+			if err := q.Tag.Save(tag); err != nil {
+				return err
+			}
+			tagIDs[tag.Key] = tag.ID
+		}
+
+		for _, storeApp := range manifest.Apps {
+			app, err := q.App.Where(q.App.Key.Eq(storeApp.Key)).First()
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if app == nil {
+				app = &model.App{Key: storeApp.Key}
+			}
+			app.Name = storeApp.Name
+			app.Icon = storeApp.Icon
+			app.Description = storeApp.Description
+			app.NameI18n = storeApp.NameI18n
+			app.DescriptionI18n = storeApp.DescriptionI18n
+			app.Github = storeApp.Github
+			app.Class = storeApp.Class
+			app.DependsVersion = storeApp.DependsVersion
+			app.Sort = storeApp.Sort
+			app.Status = model.AppUnused
+			if err := q.App.Save(app); err != nil {
+				return err
+			}
+
+			if _, err := q.AppDetail.Where(q.AppDetail.AppID.Eq(app.ID)).Delete(); err != nil {
+				return err
+			}
+			err = q.AppDetail.Create(&model.AppDetail{
+				AppID:          app.ID,
+				Repo:           storeApp.Repo,
+				Version:        storeApp.Version,
+				DependsVersion: storeApp.DependsVersion,
+				Params:         storeApp.Params,
+				DockerCompose:  storeApp.DockerCompose,
+				NginxConfig:    storeApp.NginxConfig,
+				HealthCheck:    storeApp.HealthCheck,
+				Status:         storeApp.DetailStatus,
+			})
+			if err != nil {
+				return err
+			}
+
+			if _, err := q.AppTag.Where(q.AppTag.AppID.Eq(app.ID)).Delete(); err != nil {
+				return err
+			}
+			for _, key := range storeApp.Tags {
+				if tagID, ok := tagIDs[key]; ok {
+					if err := q.AppTag.Create(&model.AppTag{AppID: app.ID, TagID: tagID}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// importInstalled 恢复单个已安装的插件，并迁移其定时备份计划
+func (m StoreMigrator) importInstalled(src string, manifest *dto.StoreManifest, installed dto.StoreInstalled) error {
+	log.Info("导入插件:", installed.Key)
+	unlock, err := appBackupManager.Lock(installed.Key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	restoreProcess := NewAppRestoreProcess(src).WithPrefix(installed.Dir)
+	if err := restoreProcess.LoadManifest(); err != nil {
+		return err
+	}
+	if err := restoreProcess.RemapManifest(newStoreRemapper(manifest, restoreProcess.Manifest())); err != nil {
+		return err
+	}
+	if err := restoreProcess.AllocateIP(); err != nil {
+		return err
+	}
+	if err := restoreProcess.Restore(); err != nil {
+		return err
+	}
+
+	appInstalled := restoreProcess.AppInstalled()
+	if installed.BackupSchedule == nil || appInstalled == nil {
+		return nil
+	}
+	schedule := &model.AppBackupSchedule{
+		AppInstalledId: appInstalled.ID,
+		Cron:           installed.BackupSchedule.Cron,
+		Retention:      installed.BackupSchedule.Retention,
+		Enabled:        installed.BackupSchedule.Enabled,
+	}
+	if cronSchedule, err := cron.Parse(schedule.Cron); err == nil && schedule.Enabled {
+		next := cronSchedule.Next(time.Now())
+		schedule.NextRunAt = &next
+	}
+	if err := repo.AppBackupSchedule.Create(schedule); err != nil {
+		log.Error("迁移定时备份计划失败:", err)
+	}
+	return nil
+}
+
+// storeRemapper 将源主机中与 APP_ID、APP_IPPR 相关的值替换为当前主机的配置
+// 只替换指定字段中完整匹配的值，不做子串替换，避免误改包含相同片段的其他值
+type storeRemapper struct {
+	names    map[string]string // 容器名、网络名
+	oldIPPR  string
+	newIPPR  string
+	networks map[string]string
+}
+
+func newStoreRemapper(store *dto.StoreManifest, backup *dto.BackupManifest) *storeRemapper {
+	r := &storeRemapper{
+		names:    map[string]string{},
+		networks: map[string]string{},
+	}
+	add := func(values map[string]string, old, new string) {
+		if old != "" && old != new {
+			values[old] = new
+		}
+	}
+	add(r.names, backup.ContainerName, config.EnvConfig.GetDefaultContainerName(backup.Key))
+	add(r.names, store.NginxContainerName, config.EnvConfig.GetNginxContainerName())
+	add(r.networks, store.NetworkName, config.EnvConfig.App().NETWORK_NAME)
+	if store.AppIPPR != "" && config.EnvConfig.APP_IPPR != "" && store.AppIPPR != config.EnvConfig.APP_IPPR {
+		r.oldIPPR = store.AppIPPR + "."
+		r.newIPPR = config.EnvConfig.APP_IPPR + "."
+	}
+	return r
+}
+
+// RemapIP 替换源主机网段中的IPv4地址
+func (r *storeRemapper) RemapIP(ip string) string {
+	if r.oldIPPR == "" || net.ParseIP(ip) == nil || !strings.HasPrefix(ip, r.oldIPPR) {
+		return ip
+	}
+	return r.newIPPR + strings.TrimPrefix(ip, r.oldIPPR)
+}
+
+// RemapParams 替换参数中值为容器名、网络名或源主机网段IP的项
+func (r *storeRemapper) RemapParams(params string) (string, error) {
+	if params == "" {
+		return params, nil
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(params), &values); err != nil {
+		return "", err
+	}
+	for key, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if name, ok := r.names[str]; ok {
+			values[key] = name
+		} else if network, ok := r.networks[str]; ok {
+			values[key] = network
+		} else {
+			values[key] = r.RemapIP(str)
+		}
+	}
+	result, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// RemapDockerCompose 替换 docker-compose 中的 container_name、网络名与 ipv4_address
+func (r *storeRemapper) RemapDockerCompose(content string) (string, error) {
+	return compose.Remapper{
+		ContainerNames: r.names,
+		Networks:       r.networks,
+		IPAddress:      r.RemapIP,
+	}.Remap(content)
+}
@@ -13,6 +13,10 @@ import (
 )
 
 func GetMsgWithMap(ctx *gin.Context, key string, maps map[string]any) string {
+	// 命令行等没有请求上下文的场景，直接返回消息ID
+	if ctx == nil {
+		return key
+	}
 	content := ""
 	if maps == nil {
 		content = ginI18n.MustGetMessage(ctx, &i18n.LocalizeConfig{
@@ -3,13 +3,11 @@ package app
 import (
 	"doo-store/backend/config"
 	"doo-store/backend/constant"
-	"doo-store/backend/core/repo"
+	"doo-store/backend/core/service"
 	"doo-store/backend/utils/docker"
 	"fmt"
 	"os"
 	"path"
-
-	"gorm.io/gorm"
 )
 
 func Init() {
@@ -18,24 +16,43 @@ func Init() {
 	constant.DataDir = resolveDataDir(config.EnvConfig.App().DATA_DIR)
 	constant.AppInstallDir = path.Join(constant.DataDir, "apps")
 	constant.NginxDir = path.Join(constant.DataDir, "nginx")
+	constant.BackupDir = path.Join(constant.DataDir, "backups")
+	constant.ACMEDir = path.Join(constant.DataDir, "acme")
 
 	fmt.Println("数据目录: ", constant.DataDir)
 	fmt.Println("应用安装目录: ", constant.AppInstallDir)
 	fmt.Println("Nginx配置目录: ", constant.NginxDir)
+	fmt.Println("备份目录: ", constant.BackupDir)
+	fmt.Println("ACME账户目录: ", constant.ACMEDir)
 
-	plugins, err := repo.AppInstalled.Select(repo.AppInstalled.ID, repo.AppInstalled.IpAddress).Find()
-	if err != nil && err != gorm.ErrRecordNotFound {
+	// 由IP分配记录重建IP分配器，升级前安装的插件先补充分配记录
+	ipAllocationManager := service.NewIPAllocationManager()
+	if err := ipAllocationManager.Backfill(); err != nil {
 		panic(err)
 	}
-	usedIPs := []string{}
-	for _, plugin := range plugins {
-		usedIPs = append(usedIPs, plugin.IpAddress)
+	usedIPs, err := ipAllocationManager.UsedIPs()
+	if err != nil {
+		panic(err)
 	}
 	// 初始化全局IP分配器
 	if err := docker.InitIPAllocator(config.EnvConfig.PLUGIN_CIDR, usedIPs); err != nil {
 		fmt.Printf("初始化IP分配器失败: %v", err)
 		panic(err)
 	}
+	// 双栈网络时初始化IPv6分配器
+	if err := docker.InitIP6Allocator(config.EnvConfig.PLUGIN_CIDR6, usedIPs); err != nil {
+		fmt.Printf("初始化IPv6分配器失败: %v", err)
+		panic(err)
+	}
+	excludedIPs, err := ipAllocationManager.ExcludedIPs()
+	if err != nil {
+		panic(err)
+	}
+	for _, ip := range excludedIPs {
+		if allocator := docker.AllocatorFor(ip); allocator != nil {
+			_ = allocator.AddExcludedIP(ip)
+		}
+	}
 
 	// 加载默认数据
 	LoadData()
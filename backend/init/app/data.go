@@ -54,13 +54,15 @@ func LoadData() error {
 				continue
 			}
 			app := &model.App{
-				Name:           p.Name,
-				Key:            p.Key,
-				Icon:           p.Icon,
-				Class:          p.Class,
-				Description:    p.Description,
-				DependsVersion: p.DependsVersion,
-				Status:         model.AppUnused,
+				Name:            p.Name,
+				Key:             p.Key,
+				Icon:            p.Icon,
+				Class:           p.Class,
+				Description:     p.Description,
+				NameI18n:        p.NameI18n,
+				DescriptionI18n: p.DescriptionI18n,
+				DependsVersion:  p.DependsVersion,
+				Status:          model.AppUnused,
 			}
 			err := repo.Use(tx).App.Create(app)
 			if err != nil {
@@ -77,6 +79,7 @@ func LoadData() error {
 				Params:         p.GenParams(),
 				DockerCompose:  p.GenComposeFile(),
 				NginxConfig:    p.GenNginxConfig(),
+				HealthCheck:    p.HealthCheck,
 				Status:         model.AppNormal,
 			}
 			err = repo.Use(tx).AppDetail.Create(appDetail)
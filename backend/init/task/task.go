@@ -2,17 +2,61 @@ package task
 
 import (
 	"context"
+	"doo-store/backend/core/service"
 	"doo-store/backend/task"
+	"doo-store/backend/utils/docker"
 	"time"
 )
 
 func Init() {
 	task.InitializeGlobalManager(100, 3)
 
-	// 初始化Docker容器监控
+	// 初始化Docker容器监控，容器事件实时更新状态，每5分钟全量同步一次
 	monitor, err := task.NewDockerMonitor(context.Background())
 	if err != nil {
 		panic(err)
 	}
-	monitor.StartMonitoring(30 * time.Second)
+	monitor.StartMonitoring(5 * time.Minute)
+
+	// 镜像没有 HEALTHCHECK 的插件按声明的HTTP地址探测健康状态
+	prober, err := task.NewHealthProber(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	prober.StartProbing(5 * time.Second)
+
+	// 初始化定时备份调度
+	scheduler := task.NewBackupScheduler(context.Background(), service.ScheduledBackup)
+	scheduler.StartScheduling(time.Minute)
+
+	// 定时检查证书有效期并续期即将过期的证书
+	renewer := task.NewCertificateRenewer(context.Background(), service.RenewCertificates)
+	renewer.StartScheduling(12 * time.Hour)
+
+	// Nginx容器重建或重启后重新写入插件的Nginx配置
+	watcher, err := task.NewNginxWatcher(context.Background(), func() error {
+		_, err := service.ReconcileNginx()
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+	watcher.StartWatching(5 * time.Second)
+
+	// 启动时核对IP分配记录与容器实际使用的IP
+	task.GetAsyncTaskManager().AddTask(func() error {
+		client, err := docker.NewClient()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		_, err = service.NewIPAllocationManager().Reconcile(client)
+		return err
+	})
+
+	// 启动时核对插件的Nginx配置
+	task.GetAsyncTaskManager().AddTask(func() error {
+		_, err := service.ReconcileNginx()
+		return err
+	})
 }
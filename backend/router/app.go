@@ -23,6 +23,21 @@ func (a *AppRouter) InitRouter(Router *gin.RouterGroup) {
 		appRouter.GET("/installed/:id/params", baseApi.GetAppParams)
 		appRouter.PUT("/installed/:id/params", baseApi.UpdateAppParams)
 		appRouter.GET("/installed/:id/logs", baseApi.GetAppLogs)
+		appRouter.GET("/installed/:id/health", baseApi.GetAppHealth)
+		appRouter.GET("/installed/:id/status-history", baseApi.ListStatusHistory)
+		appRouter.GET("/installed/:id/uptime", baseApi.GetAppUptime)
+		appRouter.GET("/installed/:id/backups", baseApi.ListAppBackups)
+		appRouter.POST("/installed/:id/restore", baseApi.RestoreApp)
+		appRouter.GET("/installed/:id/backup-schedule", baseApi.GetBackupSchedule)
+		appRouter.PUT("/installed/:id/backup-schedule", baseApi.UpdateBackupSchedule)
+		appRouter.GET("/installed/:id/restart-policy", baseApi.GetRestartPolicy)
+		appRouter.PUT("/installed/:id/restart-policy", baseApi.UpdateRestartPolicy)
+		appRouter.GET("/installed/:id/nginx", baseApi.ListNginxVersions)
+		appRouter.POST("/installed/:id/nginx/preview", baseApi.PreviewNginxLocation)
+		appRouter.POST("/installed/:id/nginx/apply", baseApi.ApplyNginxLocation)
+		appRouter.GET("/installed/:id/nginx/options", baseApi.GetNginxOptions)
+		appRouter.PUT("/installed/:id/nginx/options", baseApi.UpdateNginxOptions)
+		appRouter.PUT("/installed/:id/domain", baseApi.UpdateAppDomain)
 		appRouter.GET("/tags", baseApi.ListAppTags)
 
 		appRouter.GET("/plugin/info", baseApi.GetInstalledAppInfo)
@@ -0,0 +1,22 @@
+package router
+
+import (
+	v1 "doo-store/backend/core/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateRouter struct {
+}
+
+func (a *CertificateRouter) InitRouter(Router *gin.RouterGroup) {
+	certificateRouter := Router.Group("certificates")
+	baseApi := v1.Api
+	{
+		certificateRouter.GET("", baseApi.ListCertificates)
+		certificateRouter.POST("", baseApi.UploadCertificate)
+		certificateRouter.POST("/acme", baseApi.IssueCertificate)
+		certificateRouter.POST("/:id/renew", baseApi.RenewCertificate)
+		certificateRouter.DELETE("/:id", baseApi.DeleteCertificate)
+	}
+}
@@ -10,6 +10,9 @@ func commonGroups() []CommonRouter {
 	return []CommonRouter{
 		&PublicRouter{},
 		&AppRouter{},
+		&IPAMRouter{},
+		&NginxRouter{},
+		&CertificateRouter{},
 	}
 }
 
@@ -0,0 +1,22 @@
+package router
+
+import (
+	v1 "doo-store/backend/core/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IPAMRouter struct {
+}
+
+func (a *IPAMRouter) InitRouter(Router *gin.RouterGroup) {
+	ipamRouter := Router.Group("ipam")
+	baseApi := v1.Api
+	{
+		ipamRouter.GET("", baseApi.GetIPAMOverview)
+		ipamRouter.GET("/reconcile", baseApi.ReconcileIPs)
+		ipamRouter.POST("/reserve", baseApi.ReserveIPRange)
+		ipamRouter.POST("/exclude", baseApi.ExcludeIPRange)
+		ipamRouter.DELETE("/:ip", baseApi.ReleaseIP)
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	v1 "doo-store/backend/core/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NginxRouter struct {
+}
+
+func (a *NginxRouter) InitRouter(Router *gin.RouterGroup) {
+	nginxRouter := Router.Group("nginx")
+	baseApi := v1.Api
+	{
+		nginxRouter.POST("/reconcile", baseApi.ReconcileNginx)
+	}
+}
@@ -14,5 +14,7 @@ func (a *PublicRouter) InitRouter(Router *gin.RouterGroup) {
 	baseApi := v1.Api
 	{
 		publicRouter.GET("/health", baseApi.HealthCheck)
+		publicRouter.GET("/plugin-schema", baseApi.PluginSchema)
+		publicRouter.GET("/auth-check", baseApi.AuthCheck)
 	}
 }
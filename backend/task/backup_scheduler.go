@@ -0,0 +1,116 @@
+package task
+
+import (
+	"context"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/cron"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BackupHandler 执行一次定时备份
+type BackupHandler func(schedule *model.AppBackupSchedule) error
+
+// BackupScheduler 定时备份任务，按照每个插件配置的 cron 表达式将备份任务加入全局异步队列
+type BackupScheduler struct {
+	ctx     context.Context
+	handler BackupHandler
+	// 已入队但尚未完成的备份，避免同一插件的备份任务堆积
+	pending sync.Map
+}
+
+// NewBackupScheduler 创建新的定时备份调度器
+func NewBackupScheduler(ctx context.Context, handler BackupHandler) *BackupScheduler {
+	return &BackupScheduler{
+		ctx:     ctx,
+		handler: handler,
+	}
+}
+
+// StartScheduling 开始调度任务
+func (bs *BackupScheduler) StartScheduling(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := bs.scheduleBackups(); err != nil {
+					log.Errorf("Error scheduling backups: %v", err)
+				}
+			case <-bs.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// 检查到期的备份计划并加入队列
+func (bs *BackupScheduler) scheduleBackups() error {
+	schedules, err := repo.AppBackupSchedule.Where(repo.AppBackupSchedule.Enabled.Is(true)).Find()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		cronSchedule, err := cron.Parse(schedule.Cron)
+		if err != nil {
+			log.Warnf("插件 %d 的备份计划无效: %v", schedule.AppInstalledId, err)
+			continue
+		}
+		next := cronSchedule.Next(now)
+		if next.IsZero() {
+			log.Warnf("插件 %d 的备份计划没有可执行的时间", schedule.AppInstalledId)
+			continue
+		}
+
+		// 首次调度只计算下次执行时间
+		if schedule.NextRunAt == nil {
+			bs.updateSchedule(schedule.ID, map[string]interface{}{
+				repo.AppBackupSchedule.NextRunAt.ColumnName().String(): next,
+			})
+			continue
+		}
+		if schedule.NextRunAt.After(now) {
+			continue
+		}
+
+		bs.updateSchedule(schedule.ID, map[string]interface{}{
+			repo.AppBackupSchedule.LastRunAt.ColumnName().String(): now,
+			repo.AppBackupSchedule.NextRunAt.ColumnName().String(): next,
+		})
+
+		if _, loaded := bs.pending.LoadOrStore(schedule.AppInstalledId, struct{}{}); loaded {
+			log.Infof("插件 %d 的备份仍在进行中，跳过本次定时备份", schedule.AppInstalledId)
+			continue
+		}
+		log.Infof("插件 %d 定时备份已加入队列，下次执行时间 %s", schedule.AppInstalledId, next.Format(time.DateTime))
+
+		schedule := schedule
+		GetAsyncTaskManager().AddTask(func() error {
+			defer bs.pending.Delete(schedule.AppInstalledId)
+			result := "success"
+			err := bs.handler(schedule)
+			if err != nil {
+				result = err.Error()
+			}
+			bs.updateSchedule(schedule.ID, map[string]interface{}{
+				repo.AppBackupSchedule.LastResult.ColumnName().String(): result,
+			})
+			return err
+		})
+	}
+	return nil
+}
+
+func (bs *BackupScheduler) updateSchedule(id int64, values map[string]interface{}) {
+	_, err := repo.AppBackupSchedule.Where(repo.AppBackupSchedule.ID.Eq(id)).Updates(values)
+	if err != nil {
+		log.Errorf("Failed to update backup schedule %d: %v", id, err)
+	}
+}
@@ -0,0 +1,54 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CertificateRenewHandler 检查一次证书的有效期并续期即将过期的证书
+type CertificateRenewHandler func() error
+
+// CertificateRenewer 定时检查证书的有效期，将续期任务加入全局异步队列
+type CertificateRenewer struct {
+	ctx     context.Context
+	handler CertificateRenewHandler
+}
+
+// NewCertificateRenewer 创建新的证书续期调度器
+func NewCertificateRenewer(ctx context.Context, handler CertificateRenewHandler) *CertificateRenewer {
+	return &CertificateRenewer{
+		ctx:     ctx,
+		handler: handler,
+	}
+}
+
+// StartScheduling 开始调度任务，启动时立即检查一次
+func (cr *CertificateRenewer) StartScheduling(interval time.Duration) {
+	go func() {
+		cr.schedule()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				cr.schedule()
+			case <-cr.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (cr *CertificateRenewer) schedule() {
+	GetAsyncTaskManager().AddTask(func() error {
+		if err := cr.handler(); err != nil {
+			log.Errorf("Error renewing certificates: %v", err)
+			return err
+		}
+		return nil
+	})
+}
@@ -2,24 +2,35 @@ package task
 
 import (
 	"context"
+	"doo-store/backend/config"
 	"doo-store/backend/constant"
 	"doo-store/backend/core/model"
 	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/compose"
 	"doo-store/backend/utils/docker"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	log "github.com/sirupsen/logrus"
 )
 
-// DockerMonitor 容器监控任务
+// pluginContainerLabel 插件容器的标签，由插件的 docker-compose 文件添加
+const pluginContainerLabel = "createdBy=Apps"
+
+// DockerMonitor 容器监控任务，订阅插件容器的事件实时更新状态，并定时全量同步一次
 type DockerMonitor struct {
 	client *client.Client
 	ctx    context.Context
+	// 收到 oom 事件的容器，随后的 die 事件使用内存不足作为原因
+	oomKilled sync.Map
 }
 
 // NewDockerMonitor 创建新的Docker监控器
@@ -35,8 +46,21 @@ func NewDockerMonitor(ctx context.Context) (*DockerMonitor, error) {
 	}, nil
 }
 
-// StartMonitoring 开始监控任务
+// StartMonitoring 开始监控任务，容器事件实时处理，interval 为全量同步的间隔，用于补上遗漏的事件
 func (dm *DockerMonitor) StartMonitoring(interval time.Duration) {
+	go func() {
+		for {
+			if err := dm.watch(); err != nil {
+				log.Warnf("插件容器事件监听中断: %v", err)
+			}
+			select {
+			case <-time.After(10 * time.Second):
+			case <-dm.ctx.Done():
+				return
+			}
+		}
+	}()
+
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
@@ -54,7 +78,145 @@ func (dm *DockerMonitor) StartMonitoring(interval time.Duration) {
 	}()
 }
 
-// 监控容器状态
+// 订阅插件容器的事件，直到连接中断
+func (dm *DockerMonitor) watch() error {
+	messages, errs := dm.client.Events(dm.ctx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("label", pluginContainerLabel),
+			filters.Arg("event", string(events.ActionStart)),
+			filters.Arg("event", string(events.ActionDie)),
+			filters.Arg("event", string(events.ActionHealthStatus)),
+			filters.Arg("event", string(events.ActionOOM)),
+			filters.Arg("event", string(events.ActionDestroy)),
+		),
+	})
+
+	// 订阅后全量同步一次，补上未订阅期间的状态变化
+	if err := dm.monitorContainers(); err != nil {
+		log.Printf("Error monitoring containers: %v", err)
+	}
+
+	for {
+		select {
+		case message := <-messages:
+			dm.handleEvent(message)
+		case err := <-errs:
+			return err
+		case <-dm.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// 处理一个容器事件
+func (dm *DockerMonitor) handleEvent(message events.Message) {
+	name := message.Actor.Attributes["name"]
+	if name == "" || !ownsProject(message.Actor.Attributes["com.docker.compose.project"]) {
+		return
+	}
+	log.Debugf("容器事件 %s [%s]", name, message.Action)
+
+	switch {
+	case message.Action == events.ActionStart:
+		// 重新启动后健康状态需要重新检查
+		_, err := repo.AppInstalled.Where(repo.AppInstalled.Name.Eq(name)).Update(repo.AppInstalled.Health, "")
+		if err != nil {
+			log.Errorf("Failed to reset app health for %s: %v", name, err)
+		}
+		dm.updateStatus(name, docker.ContainerStatusRunning, model.PluginStatusRunning, "", nil)
+	case message.Action == events.ActionOOM:
+		dm.oomKilled.Store(name, struct{}{})
+	case message.Action == events.ActionDie:
+		_, oomKilled := dm.oomKilled.LoadAndDelete(name)
+		var exitCode *int
+		if code, err := strconv.Atoi(message.Actor.Attributes["exitCode"]); err == nil {
+			exitCode = &code
+		}
+		var appInstalled *model.AppInstalled
+		switch {
+		case oomKilled:
+			appInstalled = dm.updateStatus(name, docker.ContainerStatusExited, model.PluginStatusError, fmt.Sprintf("Container was killed due to out of memory, exit code %s", message.Actor.Attributes["exitCode"]), exitCode)
+		case exitCode != nil && *exitCode == 0:
+			dm.updateStatus(name, docker.ContainerStatusExited, model.PluginStatusStopped, "Container stopped normally", exitCode)
+		default:
+			appInstalled = dm.updateStatus(name, docker.ContainerStatusExited, model.PluginStatusError, fmt.Sprintf("Container exited with code %s", message.Actor.Attributes["exitCode"]), exitCode)
+		}
+		if appInstalled != nil {
+			dm.applyRestartPolicy(appInstalled)
+		}
+	case strings.HasPrefix(string(message.Action), string(events.ActionHealthStatus)):
+		appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.Name.Eq(name)).First()
+		if err != nil {
+			// 非插件主容器
+			return
+		}
+		dm.syncHealth(appInstalled)
+	case message.Action == events.ActionDestroy:
+		dm.oomKilled.Delete(name)
+		if appInstalled := dm.updateStatus(name, docker.CustomContainerStatusRemoved, model.PluginStatusError, "Container is not existing", nil); appInstalled != nil {
+			CancelRestart(appInstalled.ID)
+		}
+	}
+}
+
+// 更新容器对应的服务状态，容器为插件主容器时同时更新插件状态并返回插件
+func (dm *DockerMonitor) updateStatus(containerName, containerStatus, pluginStatus, message string, exitCode *int) *model.AppInstalled {
+	services, err := repo.AppServiceStatus.Where(
+		repo.AppServiceStatus.ContainerName.Eq(containerName),
+		repo.AppServiceStatus.Status.Neq(model.PluginStatusInstalling),
+	).Find()
+	if err != nil {
+		log.Errorf("Failed to find service status for %s: %v", containerName, err)
+	}
+	for _, service := range services {
+		_, err = repo.AppServiceStatus.Where(repo.AppServiceStatus.ID.Eq(service.ID)).Updates(map[string]interface{}{
+			repo.AppServiceStatus.Status.ColumnName().String():  containerStatus,
+			repo.AppServiceStatus.Message.ColumnName().String(): message,
+		})
+		if err != nil {
+			log.Errorf("Failed to update service status for %s: %v", containerName, err)
+			continue
+		}
+		recordServiceTransition(service, containerStatus, message, exitCode)
+	}
+
+	appInstalled, err := repo.AppInstalled.Select(repo.AppInstalled.ID, repo.AppInstalled.Name, repo.AppInstalled.Status).
+		Where(repo.AppInstalled.Name.Eq(containerName)).First()
+	if err != nil {
+		// 非插件主容器
+		return nil
+	}
+	updateAppStatusWithExitCode(appInstalled, pluginStatus, message, exitCode)
+	return appInstalled
+}
+
+// syncHealth 从容器详情中读取健康检查结果
+func (dm *DockerMonitor) syncHealth(appInstalled *model.AppInstalled) {
+	container, err := dm.client.ContainerInspect(dm.ctx, appInstalled.Name)
+	if err != nil {
+		log.Warnf("Failed to inspect container %s: %v", appInstalled.Name, err)
+		return
+	}
+	if container.State == nil || container.State.Health == nil {
+		return
+	}
+	recordHealth(appInstalled, container.State.Health.Status, dockerHealthLog(container.State.Health))
+}
+
+// ownsProject 判断 compose 项目是否属于本商店，共享 compose 时为共享项目，否则为以插件前缀开头的项目
+func ownsProject(project string) bool {
+	if project == "" {
+		return true
+	}
+	app := config.EnvConfig.App()
+	if app.SHARED_COMPOSE {
+		return project == compose.ProjectName(app.SHARED_COMPOSE_NAME+"/docker-compose.yml")
+	}
+	return strings.HasPrefix(project, compose.ProjectName(app.PLUGIN_PREFIX+"/docker-compose.yml"))
+}
+
+// 全量同步容器状态
 func (dm *DockerMonitor) monitorContainers() error {
 	log.Debug("正在处理容器状态")
 
@@ -62,23 +224,31 @@ func (dm *DockerMonitor) monitorContainers() error {
 	if err != nil {
 		return fmt.Errorf("%s: %v", constant.ErrDockerFindApps, err)
 	}
+	services, err := repo.AppServiceStatus.Find()
+	if err != nil {
+		return fmt.Errorf("%s: %v", constant.ErrDockerFindApps, err)
+	}
 
-	appStatusMap := dm.getContainerStatuses(apps)
-	dm.updateAppStatuses(appStatusMap)
+	containers := dm.getContainers(apps, services)
+	dm.updateAppStatuses(apps, containers)
+	dm.updateServiceStatuses(services, containers)
+	pruneStatusHistory()
 	log.Debug("结束处理容器状态")
 	return nil
 }
 
-// 获取所有容器的状态
-func (dm *DockerMonitor) getContainerStatuses(apps []*model.AppInstalled) map[string]string {
+// 获取插件及其服务的容器，以容器名为键
+func (dm *DockerMonitor) getContainers(apps []*model.AppInstalled, services []*model.AppServiceStatus) map[string]types.Container {
 	filterArgs := filters.NewArgs()
-	appStatusMap := make(map[string]string)
-
-	// 将状态标记为 “init”表示这些容器是本系统的容器
 	for _, app := range apps {
 		filterArgs.Add("name", app.Name)
-
-		appStatusMap[app.Name] = docker.CustomContainerStatusInit
+	}
+	for _, service := range services {
+		filterArgs.Add("name", service.ContainerName)
+	}
+	result := map[string]types.Container{}
+	if filterArgs.Len() == 0 {
+		return result
 	}
 
 	containers, err := dm.client.ContainerList(dm.ctx, container.ListOptions{
@@ -87,81 +257,134 @@ func (dm *DockerMonitor) getContainerStatuses(apps []*model.AppInstalled) map[st
 	})
 	if err != nil {
 		log.Errorf("Failed to list containers: %v", err)
-		return appStatusMap
+		return nil
 	}
-
-	for _, container := range containers {
-		containerName := strings.TrimPrefix(container.Names[0], "/")
-		if appStatusMap[containerName] == docker.CustomContainerStatusInit {
-			appStatusMap[containerName] = container.State
-		}
+	for _, item := range containers {
+		result[strings.TrimPrefix(item.Names[0], "/")] = item
 	}
-	log.Info("容器状态", appStatusMap)
-	return appStatusMap
+	return result
 }
 
-func (dm *DockerMonitor) updateAppStatus(appName string, status string, message string) {
-	appInstalled, err := repo.AppInstalled.Select(repo.AppInstalled.ID, repo.AppInstalled.Status).Where(repo.AppInstalled.Name.Eq(appName)).First()
-	if err != nil {
-		log.Errorf("Failed to find app record for %s: %v", appName, err)
-		return
-	}
+func updateAppStatus(appInstalled *model.AppInstalled, status string, message string) {
+	updateAppStatusWithExitCode(appInstalled, status, message, nil)
+}
 
+// updateAppStatusWithExitCode 更新插件状态并记录状态变化，exitCode 为容器退出码
+func updateAppStatusWithExitCode(appInstalled *model.AppInstalled, status string, message string, exitCode *int) {
 	// 跳过处理 Installing 状态的应用
 	if strings.EqualFold(appInstalled.Status, model.PluginStatusInstalling) {
-		log.Debugf("Skipping status update for app %s as it is in Installing state", appName)
+		log.Debugf("Skipping status update for app %s as it is in Installing state", appInstalled.Name)
+		return
+	}
+
+	// 手动停止或判定为崩溃循环的插件，容器再次运行前保持原状态
+	if (appInstalled.Status == model.PluginStatusStopped || appInstalled.Status == model.PluginStatusDead) && !model.IsRunningStatus(status) {
 		return
 	}
 
 	// 只有状态发生变化时才更新
 	if appInstalled.Status != status && appInstalled.Status != model.PluginStatusUpErr {
 		log.Debugf("更新状态 %s [%s]", status, message)
-		_, err = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Updates(
+		// 只在状态未被其他操作修改时更新，避免使用过期的记录覆盖手动停止等状态
+		result, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID), repo.AppInstalled.Status.Eq(appInstalled.Status)).Updates(
 			map[string]interface{}{
 				repo.AppInstalled.Status.ColumnName().String():  status,
 				repo.AppInstalled.Message.ColumnName().String(): message,
 			},
 		)
 		if err != nil {
-			log.Errorf("Failed to update app status for %s: %v", appName, err)
+			log.Errorf("Failed to update app status for %s: %v", appInstalled.Name, err)
+			return
+		}
+		if result.RowsAffected == 0 {
+			log.Debugf("Skipping status update for app %s as its status has changed", appInstalled.Name)
 			return
 		}
+		recordAppTransition(appInstalled, status, message, exitCode)
+		appInstalled.Status = status
+		appInstalled.Message = message
 	}
 }
 
-// 根据容器状态更新应用状态
-func (dm *DockerMonitor) updateAppStatuses(appStatusMap map[string]string) {
-	for appName, status := range appStatusMap {
-		switch status {
+// 根据容器状态更新应用状态，获取容器列表失败时不更新
+func (dm *DockerMonitor) updateAppStatuses(apps []*model.AppInstalled, containers map[string]types.Container) {
+	if containers == nil {
+		return
+	}
+	for _, app := range apps {
+		item, ok := containers[app.Name]
+		if !ok {
+			updateAppStatus(app, model.PluginStatusError, "Container is not existing")
+			continue
+		}
+		switch item.State {
 		case docker.ContainerStatusRunning:
-			dm.updateAppStatus(appName, model.PluginStatusRunning, "")
+			switch {
+			case strings.Contains(item.Status, "(health"), strings.Contains(item.Status, "(unhealthy)"):
+				// 容器配置了健康检查
+				dm.syncHealth(app)
+			case app.Health == model.HealthUnhealthy:
+				// HTTP健康检查的结果
+				updateAppStatus(app, model.PluginStatusUnHealthy, healthMessage(app.HealthLog))
+			default:
+				updateAppStatus(app, model.PluginStatusRunning, "")
+			}
 		case docker.ContainerStatusExited:
-			dm.handleExitedContainer(appName)
+			dm.handleExitedContainer(app)
 		case docker.ContainerStatusRestarting:
-			dm.updateAppStatus(appName, model.PluginStatusRestarting, "Container is restarting")
+			updateAppStatus(app, model.PluginStatusRestarting, "Container is restarting")
 		case docker.ContainerStatusPaused:
-			dm.updateAppStatus(appName, model.PluginStatusPaused, "Container is paused")
+			updateAppStatus(app, model.PluginStatusPaused, "Container is paused")
 		case docker.ContainerStatusDead:
-			dm.updateAppStatus(appName, model.PluginStatusDead, "Container is in dead state")
-		case docker.CustomContainerStatusInit:
-			dm.updateAppStatus(appName, model.PluginStatusError, "Container is not existing")
+			updateAppStatus(app, model.PluginStatusDead, "Container is in dead state")
 		default:
-			dm.updateAppStatus(appName, model.PluginStatusUnknown, fmt.Sprintf("Unknown state: %s", status))
+			updateAppStatus(app, model.PluginStatusUnknown, fmt.Sprintf("Unknown state: %s", item.State))
 		}
 	}
 }
 
+// 根据容器状态更新服务状态，获取容器列表失败时不更新
+func (dm *DockerMonitor) updateServiceStatuses(services []*model.AppServiceStatus, containers map[string]types.Container) {
+	if containers == nil {
+		return
+	}
+	for _, service := range services {
+		if service.Status == model.PluginStatusInstalling {
+			continue
+		}
+		status := docker.CustomContainerStatusRemoved
+		if item, ok := containers[service.ContainerName]; ok {
+			status = item.State
+		}
+		if service.Status == status {
+			continue
+		}
+		_, err := repo.AppServiceStatus.Where(repo.AppServiceStatus.ID.Eq(service.ID)).
+			Update(repo.AppServiceStatus.Status, status)
+		if err != nil {
+			log.Errorf("Failed to update service status for %s: %v", service.ContainerName, err)
+			continue
+		}
+		recordServiceTransition(service, status, service.Message, nil)
+	}
+}
+
 // 处理退出的容器
-func (dm *DockerMonitor) handleExitedContainer(appName string) {
-	container, err := dm.client.ContainerInspect(dm.ctx, appName)
+func (dm *DockerMonitor) handleExitedContainer(appInstalled *model.AppInstalled) {
+	container, err := dm.client.ContainerInspect(dm.ctx, appInstalled.Name)
 	if err != nil {
-		log.Warnf("Failed to inspect container %s: %v", appName, err)
+		log.Warnf("Failed to inspect container %s: %v", appInstalled.Name, err)
 		return
 	}
-	if container.State.ExitCode == 0 {
-		dm.updateAppStatus(appName, model.PluginStatusStopped, "Container stopped normally")
+	exitCode := container.State.ExitCode
+	if exitCode == 0 {
+		updateAppStatusWithExitCode(appInstalled, model.PluginStatusStopped, "Container stopped normally", &exitCode)
 	} else {
 		message := fmt.Sprintf("Container exited with code %d: %s", container.State.ExitCode, container.State.Error)
-		dm.updateAppStatus(appName, model.PluginStatusError, message)
+		if container.State.OOMKilled {
+			message = fmt.Sprintf("Container was killed due to out of memory, exit code %d", container.State.ExitCode)
+		}
+		updateAppStatusWithExitCode(appInstalled, model.PluginStatusError, message, &exitCode)
+		dm.applyRestartPolicy(appInstalled)
 	}
 }
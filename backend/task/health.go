@@ -0,0 +1,74 @@
+package task
+
+import (
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// recordHealth 保存插件的健康状态与检查记录，并根据健康状态更新插件状态
+func recordHealth(appInstalled *model.AppInstalled, health string, healthLog model.HealthLog) {
+	if strings.EqualFold(appInstalled.Status, model.PluginStatusInstalling) {
+		return
+	}
+	_, err := repo.AppInstalled.Select(repo.AppInstalled.Health, repo.AppInstalled.HealthLog).
+		Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).Updates(&model.AppInstalled{
+		Health:    health,
+		HealthLog: healthLog,
+	})
+	if err != nil {
+		log.Errorf("Failed to update app health for %s: %v", appInstalled.Name, err)
+		return
+	}
+	appInstalled.Health = health
+	appInstalled.HealthLog = healthLog
+
+	switch health {
+	case model.HealthUnhealthy:
+		updateAppStatus(appInstalled, model.PluginStatusUnHealthy, healthMessage(healthLog))
+	case model.HealthHealthy:
+		updateAppStatus(appInstalled, model.PluginStatusRunning, "")
+	}
+}
+
+// healthMessage 使用最近一次检查的输出作为不健康的原因
+func healthMessage(healthLog model.HealthLog) string {
+	if len(healthLog) == 0 {
+		return "Container is unhealthy"
+	}
+	output := strings.TrimSpace(healthLog[len(healthLog)-1].Output)
+	if len(output) > 200 {
+		output = output[:200]
+	}
+	return fmt.Sprintf("Container is unhealthy: %s", output)
+}
+
+// dockerHealthLog 转换Docker保存的健康检查记录
+func dockerHealthLog(health *types.Health) model.HealthLog {
+	healthLog := model.HealthLog{}
+	for _, result := range health.Log {
+		if result == nil {
+			continue
+		}
+		healthLog = healthLog.Append(model.HealthProbe{
+			Start:    result.Start,
+			End:      result.End,
+			ExitCode: result.ExitCode,
+			Output:   result.Output,
+		})
+	}
+	return healthLog
+}
+
+// hasDockerHealthcheck 镜像或 compose 文件是否配置了 HEALTHCHECK
+func hasDockerHealthcheck(container types.ContainerJSON) bool {
+	if container.Config == nil || container.Config.Healthcheck == nil {
+		return false
+	}
+	test := container.Config.Healthcheck.Test
+	return len(test) > 0 && test[0] != "NONE"
+}
@@ -0,0 +1,209 @@
+package task
+
+import (
+	"context"
+	"doo-store/backend/constant"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/docker"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// HealthProber HTTP健康检查任务，镜像没有 HEALTHCHECK 时按插件声明的地址探测插件是否健康
+type HealthProber struct {
+	client *client.Client
+	ctx    context.Context
+	mu     sync.Mutex
+	states map[int64]*probeState
+}
+
+// probeState 插件的探测状态
+type probeState struct {
+	next     time.Time // 下次探测的时间
+	failures int       // 连续失败次数
+	running  bool
+}
+
+// NewHealthProber 创建HTTP健康检查任务
+func NewHealthProber(ctx context.Context) (*HealthProber, error) {
+	cli, err := docker.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", constant.ErrDockerClientCreate, err)
+	}
+	return &HealthProber{
+		client: cli.GetClient(),
+		ctx:    ctx,
+		states: map[int64]*probeState{},
+	}, nil
+}
+
+// StartProbing 开始探测，interval 为检查是否有插件需要探测的间隔，每个插件按自己声明的间隔探测
+func (hp *HealthProber) StartProbing(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := hp.probeApps(); err != nil {
+					log.Errorf("Error probing apps: %v", err)
+				}
+			case <-hp.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// 探测所有到期的运行中插件
+func (hp *HealthProber) probeApps() error {
+	apps, err := repo.AppInstalled.Where(repo.AppInstalled.Status.In(model.PluginStatusRunning, model.PluginStatusUnHealthy)).Find()
+	if err != nil {
+		return fmt.Errorf("%s: %v", constant.ErrDockerFindApps, err)
+	}
+	detailIds := make([]int64, 0, len(apps))
+	for _, app := range apps {
+		detailIds = append(detailIds, app.AppDetailID)
+	}
+	details, err := repo.AppDetail.Where(repo.AppDetail.ID.In(detailIds...)).Find()
+	if err != nil {
+		return err
+	}
+	healthChecks := map[int64]*model.HealthCheck{}
+	for _, detail := range details {
+		if detail.HealthCheck != nil {
+			healthChecks[detail.ID] = detail.HealthCheck
+		}
+	}
+
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+	now := time.Now()
+	active := map[int64]bool{}
+	for _, app := range apps {
+		healthCheck := healthChecks[app.AppDetailID]
+		if healthCheck == nil {
+			continue
+		}
+		active[app.ID] = true
+		state := hp.states[app.ID]
+		if state == nil {
+			state = &probeState{}
+			hp.states[app.ID] = state
+		}
+		if state.running || now.Before(state.next) {
+			continue
+		}
+		state.running = true
+		state.next = now.Add(healthCheck.IntervalDuration())
+		go hp.probeApp(app, healthCheck, state)
+	}
+	// 清理已停止或已卸载插件的状态
+	for id, state := range hp.states {
+		if !active[id] && !state.running {
+			delete(hp.states, id)
+		}
+	}
+	return nil
+}
+
+// 探测一个插件并保存结果
+func (hp *HealthProber) probeApp(appInstalled *model.AppInstalled, healthCheck *model.HealthCheck, state *probeState) {
+	defer func() {
+		hp.mu.Lock()
+		state.running = false
+		hp.mu.Unlock()
+	}()
+
+	container, err := hp.client.ContainerInspect(hp.ctx, appInstalled.Name)
+	if err != nil {
+		log.Debugf("Failed to inspect container %s: %v", appInstalled.Name, err)
+		return
+	}
+	// 容器自带健康检查时由Docker负责
+	if hasDockerHealthcheck(container) || container.State == nil || !container.State.Running {
+		return
+	}
+
+	probe := hp.probe(appInstalled, healthCheck)
+	// 探测期间插件可能已被停止或卸载，重新读取最新的状态
+	appInstalled, err = repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).First()
+	if err != nil {
+		log.Debugf("Failed to reload app %s: %v", container.Name, err)
+		return
+	}
+	if !appInstalled.IsRunning() {
+		return
+	}
+	hp.mu.Lock()
+	if probe.ExitCode == 0 {
+		state.failures = 0
+	} else {
+		state.failures++
+	}
+	failures := state.failures
+	hp.mu.Unlock()
+
+	health := appInstalled.Health
+	switch {
+	case probe.ExitCode == 0:
+		health = model.HealthHealthy
+	case failures >= healthCheck.RetryCount():
+		health = model.HealthUnhealthy
+	case health == "":
+		health = model.HealthStarting
+	}
+	if health != appInstalled.Health {
+		log.Infof("插件 %s 健康状态 %s -> %s", appInstalled.Key, appInstalled.Health, health)
+	}
+	recordHealth(appInstalled, health, appInstalled.HealthLog.Append(probe))
+}
+
+// 发送一次HTTP请求，2xx、3xx 或声明的状态码视为成功
+func (hp *HealthProber) probe(appInstalled *model.AppInstalled, healthCheck *model.HealthCheck) model.HealthProbe {
+	host := appInstalled.IpAddress
+	if host == "" {
+		host = appInstalled.Name
+	}
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(healthCheck.Port)), healthCheck.Path)
+
+	probe := model.HealthProbe{Start: time.Now(), ExitCode: 1}
+	ctx, cancel := context.WithTimeout(hp.ctx, healthCheck.TimeoutDuration())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		probe.End = time.Now()
+		probe.Output = err.Error()
+		return probe
+	}
+	client := &http.Client{
+		// 不跟随重定向，3xx 直接视为成功
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	probe.End = time.Now()
+	if err != nil {
+		probe.Output = err.Error()
+		return probe
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	probe.Output = strings.TrimSpace(fmt.Sprintf("GET %s: %s %s", healthCheck.Path, resp.Status, body))
+	if healthCheck.Accept(resp.StatusCode) {
+		probe.ExitCode = 0
+	}
+	return probe
+}
@@ -0,0 +1,93 @@
+package task
+
+import (
+	"context"
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+	"doo-store/backend/utils/docker"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	log "github.com/sirupsen/logrus"
+)
+
+// NginxReconcileHandler 核对一次Nginx配置
+type NginxReconcileHandler func() error
+
+// NginxWatcher 监听Nginx容器的启动事件，容器重建或重启后重新核对插件的Nginx配置
+type NginxWatcher struct {
+	client  *client.Client
+	ctx     context.Context
+	handler NginxReconcileHandler
+	// 已计划但尚未执行的核对，短时间内的多次事件只核对一次
+	pending atomic.Bool
+}
+
+// NewNginxWatcher 创建新的Nginx容器监听器
+func NewNginxWatcher(ctx context.Context, handler NginxReconcileHandler) (*NginxWatcher, error) {
+	cli, err := docker.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", constant.ErrDockerClientCreate, err)
+	}
+
+	return &NginxWatcher{
+		client:  cli.GetClient(),
+		ctx:     ctx,
+		handler: handler,
+	}, nil
+}
+
+// StartWatching 开始监听，收到启动事件后等待 delay 再核对，给Nginx留出启动时间
+func (nw *NginxWatcher) StartWatching(delay time.Duration) {
+	go func() {
+		for {
+			if err := nw.watch(delay); err != nil {
+				log.Warnf("Nginx容器事件监听中断: %v", err)
+			}
+			select {
+			case <-time.After(10 * time.Second):
+			case <-nw.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// 订阅Nginx容器的启动事件，直到连接中断
+func (nw *NginxWatcher) watch(delay time.Duration) error {
+	messages, errs := nw.client.Events(nw.ctx, events.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("event", string(events.ActionStart)),
+			filters.Arg("container", config.EnvConfig.GetNginxContainerName()),
+		),
+	})
+	for {
+		select {
+		case message := <-messages:
+			log.Infof("Nginx容器已启动 [%s]，%s 后核对插件配置", message.Actor.ID, delay)
+			nw.schedule(delay)
+		case err := <-errs:
+			return err
+		case <-nw.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// 计划一次核对，已有待执行的核对时忽略
+func (nw *NginxWatcher) schedule(delay time.Duration) {
+	if !nw.pending.CompareAndSwap(false, true) {
+		return
+	}
+	time.AfterFunc(delay, func() {
+		GetAsyncTaskManager().AddTask(func() error {
+			nw.pending.Store(false)
+			return nw.handler()
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package task
+
+import (
+	"doo-store/backend/constant"
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"doo-store/backend/utils/filelock"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	log "github.com/sirupsen/logrus"
+)
+
+// pendingRestarts 等待自动重启的插件，键为安装ID，值为 *time.Timer
+var pendingRestarts sync.Map
+
+// applyRestartPolicy 插件主容器异常退出后按重启策略处理，
+// 统计时间内异常退出的次数取自状态变化记录，因此 compose 中 restart: always 引起的反复重启同样会被统计
+func (dm *DockerMonitor) applyRestartPolicy(appInstalled *model.AppInstalled) {
+	policy, err := repo.AppRestartPolicy.Where(repo.AppRestartPolicy.AppInstalledId.Eq(appInstalled.ID)).First()
+	if err != nil || !policy.Enabled {
+		return
+	}
+	if _, pending := pendingRestarts.Load(appInstalled.ID); pending {
+		return
+	}
+	current, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(appInstalled.ID)).First()
+	if err != nil || current.Status != model.PluginStatusError || backupLocked(current.Key) {
+		return
+	}
+
+	// 判定为崩溃循环之前的异常退出不再统计
+	since := time.Now().Add(-policy.WindowDuration())
+	if policy.GaveUpAt != nil && policy.GaveUpAt.After(since) {
+		since = *policy.GaveUpAt
+	}
+	failures, err := repo.AppStatusTransition.Where(
+		repo.AppStatusTransition.InstallID.Eq(current.ID),
+		repo.AppStatusTransition.Kind.Eq(model.TransitionKindApp),
+		repo.AppStatusTransition.To.Eq(model.PluginStatusError),
+		repo.AppStatusTransition.CreatedAt.Gte(since),
+	).Count()
+	if err != nil {
+		log.Errorf("Failed to count failures for %s: %v", current.Name, err)
+		return
+	}
+	if failures > int64(policy.MaxRestarts) {
+		dm.giveUp(current, policy, failures)
+		return
+	}
+
+	delay := policy.BackoffDuration(int(failures))
+	content := fmt.Sprintf("%d分钟内第%d次异常退出，%s后重启", policy.Window, failures, delay)
+	if current.Message != "" {
+		content += ": " + current.Message
+	}
+	insertAppLog(current.ID, "自动重启", content)
+	timer := time.AfterFunc(delay, func() {
+		pendingRestarts.Delete(current.ID)
+		dm.restart(current.ID)
+	})
+	pendingRestarts.Store(current.ID, timer)
+}
+
+// restart 重启异常退出的插件主容器，插件已恢复、已被手动操作、已关闭自动重启或正在备份时跳过
+func (dm *DockerMonitor) restart(id int64) {
+	appInstalled, err := repo.AppInstalled.Where(repo.AppInstalled.ID.Eq(id)).First()
+	if err != nil || appInstalled.Status != model.PluginStatusError || backupLocked(appInstalled.Key) {
+		return
+	}
+	policy, err := repo.AppRestartPolicy.Where(repo.AppRestartPolicy.AppInstalledId.Eq(id)).First()
+	if err != nil || !policy.Enabled {
+		return
+	}
+	info, err := dm.client.ContainerInspect(dm.ctx, appInstalled.Name)
+	if err != nil {
+		insertAppLog(appInstalled.ID, "自动重启", fmt.Sprintf("获取容器信息失败: %v", err))
+		return
+	}
+	if info.State != nil && (info.State.Running || info.State.Restarting) {
+		// 已由Docker按 compose 的 restart 设置重启
+		return
+	}
+	if err := dm.client.ContainerStart(dm.ctx, appInstalled.Name, container.StartOptions{}); err != nil {
+		insertAppLog(appInstalled.ID, "自动重启", fmt.Sprintf("启动容器失败: %v", err))
+		return
+	}
+	now := time.Now()
+	_, err = repo.AppRestartPolicy.Where(repo.AppRestartPolicy.ID.Eq(policy.ID)).Update(repo.AppRestartPolicy.LastRestartAt, now)
+	if err != nil {
+		log.Errorf("Failed to update restart policy for %s: %v", appInstalled.Name, err)
+	}
+	insertAppLog(appInstalled.ID, "自动重启", "容器已启动")
+}
+
+// giveUp 判定为崩溃循环，停止容器避免 restart: always 继续重启，并将插件标记为 Dead
+func (dm *DockerMonitor) giveUp(appInstalled *model.AppInstalled, policy *model.AppRestartPolicy, failures int64) {
+	message := fmt.Sprintf("Crash loop detected: %d failures in %d minutes", failures, policy.Window)
+	if err := dm.client.ContainerStop(dm.ctx, appInstalled.Name, container.StopOptions{}); err != nil {
+		log.Warnf("Failed to stop container %s: %v", appInstalled.Name, err)
+	}
+	updateAppStatus(appInstalled, model.PluginStatusDead, message)
+
+	now := time.Now()
+	_, err := repo.AppRestartPolicy.Where(repo.AppRestartPolicy.ID.Eq(policy.ID)).Update(repo.AppRestartPolicy.GaveUpAt, now)
+	if err != nil {
+		log.Errorf("Failed to update restart policy for %s: %v", appInstalled.Name, err)
+	}
+	insertAppLog(appInstalled.ID, "崩溃循环", fmt.Sprintf("%d分钟内异常退出%d次，超过%d次的上限，已停止自动重启", policy.Window, failures, policy.MaxRestarts))
+}
+
+// CancelRestart 取消等待中的自动重启
+func CancelRestart(id int64) {
+	if timer, ok := pendingRestarts.LoadAndDelete(id); ok {
+		timer.(*time.Timer).Stop()
+	}
+}
+
+// backupLocked 插件是否正在备份、导出或恢复，此时容器由对应的操作停止与启动
+func backupLocked(key string) bool {
+	return filelock.IsLocked(path.Join(constant.BackupDir, key, constant.BackupLockFile))
+}
+
+// insertAppLog 写入插件操作日志
+func insertAppLog(appInstalledId int64, prefix, content string) {
+	err := repo.AppLog.Create(&model.AppLog{
+		AppInstalledId: appInstalledId,
+		Content:        fmt.Sprintf("%s-%s", prefix, content),
+	})
+	if err != nil {
+		log.Errorf("Failed to insert app log: %v", err)
+	}
+}
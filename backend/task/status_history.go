@@ -0,0 +1,69 @@
+package task
+
+import (
+	"doo-store/backend/core/model"
+	"doo-store/backend/core/repo"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statusHistoryRetention 状态变化记录的保留时间
+const statusHistoryRetention = 90 * 24 * time.Hour
+
+var statusHistoryMu sync.Mutex
+
+// RecordStatusTransition 记录插件或服务的状态变化，原状态取自上一条记录，状态未变化时不记录
+func RecordStatusTransition(transition *model.AppStatusTransition) {
+	statusHistoryMu.Lock()
+	defer statusHistoryMu.Unlock()
+
+	last, err := repo.AppStatusTransition.Where(
+		repo.AppStatusTransition.InstallID.Eq(transition.InstallID),
+		repo.AppStatusTransition.Kind.Eq(transition.Kind),
+		repo.AppStatusTransition.ContainerName.Eq(transition.ContainerName),
+	).Order(repo.AppStatusTransition.ID.Desc()).First()
+	if err == nil {
+		if last.To == transition.To {
+			return
+		}
+		transition.From = last.To
+	}
+	if err := repo.AppStatusTransition.Create(transition); err != nil {
+		log.Errorf("Failed to record status transition for %s: %v", transition.ContainerName, err)
+	}
+}
+
+// recordAppTransition 记录插件的状态变化
+func recordAppTransition(appInstalled *model.AppInstalled, status, message string, exitCode *int) {
+	RecordStatusTransition(&model.AppStatusTransition{
+		InstallID:     appInstalled.ID,
+		Kind:          model.TransitionKindApp,
+		ContainerName: appInstalled.Name,
+		To:            status,
+		Message:       message,
+		ExitCode:      exitCode,
+	})
+}
+
+// recordServiceTransition 记录服务容器的状态变化
+func recordServiceTransition(service *model.AppServiceStatus, status, message string, exitCode *int) {
+	RecordStatusTransition(&model.AppStatusTransition{
+		InstallID:     service.InstallID,
+		Kind:          model.TransitionKindService,
+		ContainerName: service.ContainerName,
+		ServiceName:   service.ServiceName,
+		To:            status,
+		Message:       message,
+		ExitCode:      exitCode,
+	})
+}
+
+// pruneStatusHistory 删除过期的状态变化记录
+func pruneStatusHistory() {
+	_, err := repo.AppStatusTransition.Where(repo.AppStatusTransition.CreatedAt.Lt(time.Now().Add(-statusHistoryRetention))).Delete()
+	if err != nil {
+		log.Errorf("Failed to prune status history: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+)
+
+// ChallengeSolver 提供 HTTP-01 验证文件，验证文件需要可以通过 http://<域名>/.well-known/acme-challenge/<token> 访问
+type ChallengeSolver interface {
+	PutChallenge(token, keyAuth string) error
+	RemoveChallenge(token string) error
+}
+
+// Client ACME客户端，使用 HTTP-01 验证申请单域名证书
+type Client struct {
+	client *acme.Client
+	email  string
+}
+
+// NewClient 创建ACME客户端，账户私钥保存在 accountKeyPath，不存在时自动生成。
+// caFile 为额外信任的CA证书，用于访问使用自签名证书的ACME服务
+func NewClient(directory, email, caFile, accountKeyPath string) (*Client, error) {
+	key, err := loadOrCreateKey(accountKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificate found in ca file")
+		}
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}
+	}
+	return &Client{
+		client: &acme.Client{
+			Key:          key,
+			DirectoryURL: directory,
+			HTTPClient:   httpClient,
+			UserAgent:    "doo-store",
+		},
+		email: email,
+	}, nil
+}
+
+// Obtain 申请 domain 的证书，返回PEM格式的证书链与私钥
+func (c *Client) Obtain(ctx context.Context, domain string, solver ChallengeSolver) ([]byte, []byte, error) {
+	if err := c.register(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(domain))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := c.authorize(ctx, authzURL, solver); err != nil {
+			return nil, nil, err
+		}
+	}
+	order, err = c.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wait order: %w", err)
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: []string{domain}}, certKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create csr: %w", err)
+	}
+	chain, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finalize order: %w", err)
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(certKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// register 注册ACME账户，账户已存在时直接使用
+func (c *Client) register(ctx context.Context) error {
+	account := &acme.Account{}
+	if c.email != "" {
+		account.Contact = []string{"mailto:" + c.email}
+	}
+	_, err := c.client.Register(ctx, account, acme.AcceptTOS)
+	if err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("failed to register account: %w", err)
+	}
+	return nil
+}
+
+// authorize 完成一个授权的 HTTP-01 验证
+func (c *Client) authorize(ctx context.Context, authzURL string, solver ChallengeSolver) error {
+	authz, err := c.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("failed to get authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, item := range authz.Challenges {
+		if item.Type == "http-01" {
+			challenge = item
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("no http-01 challenge offered for %s", authz.Identifier.Value)
+	}
+
+	keyAuth, err := c.client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+	if err := solver.PutChallenge(challenge.Token, keyAuth); err != nil {
+		return fmt.Errorf("failed to put challenge: %w", err)
+	}
+	defer func() {
+		if err := solver.RemoveChallenge(challenge.Token); err != nil {
+			log.Warnf("Failed to remove acme challenge %s: %v", challenge.Token, err)
+		}
+	}()
+
+	if _, err := c.client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("failed to accept challenge: %w", err)
+	}
+	if _, err := c.client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("failed to validate %s: %w", authz.Identifier.Value, err)
+	}
+	return nil
+}
+
+// loadOrCreateKey 读取PEM格式的账户私钥，不存在时生成新的私钥并保存
+func loadOrCreateKey(keyPath string) (crypto.Signer, error) {
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid account key")
+		}
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
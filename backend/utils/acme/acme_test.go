@@ -0,0 +1,162 @@
+package acme
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadOrCreateKey(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "acme", "account.key")
+	key, err := loadOrCreateKey(keyPath)
+	if err != nil {
+		t.Fatalf("loadOrCreateKey: %v", err)
+	}
+	info, err := os.Stat(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("account key mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// 再次读取时使用已保存的私钥
+	again, err := loadOrCreateKey(keyPath)
+	if err != nil {
+		t.Fatalf("loadOrCreateKey: %v", err)
+	}
+	if !key.(*ecdsa.PrivateKey).Equal(again) {
+		t.Fatal("loadOrCreateKey returned a different key for an existing file")
+	}
+
+	if err := os.WriteFile(keyPath, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadOrCreateKey(keyPath); err == nil {
+		t.Fatal("loadOrCreateKey succeeded for an invalid key file")
+	}
+}
+
+func TestNewClientCAFile(t *testing.T) {
+	dir := t.TempDir()
+	certPEM, _ := newTestCertificate(t, "", "ca.example.com")
+	validCA := filepath.Join(dir, "ca.pem")
+	invalidCA := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(validCA, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalidCA, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		caFile  string
+		wantErr bool
+	}{
+		{name: "no ca file", caFile: ""},
+		{name: "valid ca file", caFile: validCA},
+		{name: "missing ca file", caFile: filepath.Join(dir, "missing.pem"), wantErr: true},
+		{name: "ca file without certificate", caFile: invalidCA, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient("https://localhost:14000/dir", "", tt.caFile, filepath.Join(dir, "account.key"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// memorySolver 在测试HTTP服务中提供验证文件
+type memorySolver struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func (s *memorySolver) PutChallenge(token, keyAuth string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = keyAuth
+	return nil
+}
+
+func (s *memorySolver) RemoveChallenge(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, token)
+	return nil
+}
+
+func (s *memorySolver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	keyAuth, ok := s.tokens[strings.TrimPrefix(r.URL.Path, "/.well-known/acme-challenge/")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write([]byte(keyAuth))
+}
+
+// TestObtainPebble 使用 pebble 测试证书申请，未设置 ACME_TEST_DIRECTORY 时跳过。
+// pebble 需要将测试域名解析到本机，并向 ACME_TEST_HTTP_ADDR 发起 HTTP-01 验证，例如：
+//
+//	pebble-challtestsrv -defaultIPv4 127.0.0.1 &
+//	PEBBLE_VA_NOSLEEP=1 pebble -config test/config/pebble-config.json -dnsserver 127.0.0.1:8053 &
+//	ACME_TEST_DIRECTORY=https://localhost:14000/dir ACME_TEST_CA=test/certs/pebble.minica.pem go test ./backend/utils/acme/
+func TestObtainPebble(t *testing.T) {
+	directory := os.Getenv("ACME_TEST_DIRECTORY")
+	if directory == "" {
+		t.Skip("ACME_TEST_DIRECTORY is not set")
+	}
+	domain := os.Getenv("ACME_TEST_DOMAIN")
+	if domain == "" {
+		domain = "plugin.doo-store.test"
+	}
+	addr := os.Getenv("ACME_TEST_HTTP_ADDR")
+	if addr == "" {
+		addr = ":5002"
+	}
+
+	solver := &memorySolver{tokens: map[string]string{}}
+	server := &http.Server{Addr: addr, Handler: solver}
+	go func() { _ = server.ListenAndServe() }()
+	defer server.Close()
+
+	keyPath := filepath.Join(t.TempDir(), "account.key")
+	// 第二次申请时账户已存在
+	for i := 0; i < 2; i++ {
+		client, err := NewClient(directory, "admin@doo-store.test", os.Getenv("ACME_TEST_CA"), keyPath)
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		certPEM, keyPEM, err := client.Obtain(ctx, domain, solver)
+		cancel()
+		if err != nil {
+			t.Fatalf("Obtain #%d: %v", i, err)
+		}
+
+		leaf, err := ParseCertificate(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("ParseCertificate: %v", err)
+		}
+		if err := leaf.VerifyHostname(domain); err != nil {
+			t.Fatalf("issued certificate: %v", err)
+		}
+		solver.mu.Lock()
+		remaining := len(solver.tokens)
+		solver.mu.Unlock()
+		if remaining != 0 {
+			t.Fatalf("%d challenges not removed", remaining)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package acme
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+)
+
+// ParseCertificate 解析PEM格式的证书链与私钥，检查二者是否匹配，返回叶子证书
+func ParseCertificate(certPEM, keyPEM []byte) (*x509.Certificate, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
+// CertificateDomain 证书中的第一个域名
+func CertificateDomain(leaf *x509.Certificate) string {
+	if len(leaf.DNSNames) > 0 {
+		return strings.ToLower(leaf.DNSNames[0])
+	}
+	return strings.ToLower(leaf.Subject.CommonName)
+}
@@ -0,0 +1,101 @@
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCertificate 生成自签名证书，返回PEM格式的证书与私钥
+func newTestCertificate(t *testing.T, commonName string, dnsNames ...string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestParseCertificate(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t, "", "plugin.example.com")
+	otherCert, otherKey := newTestCertificate(t, "", "other.example.com")
+
+	tests := []struct {
+		name     string
+		cert     []byte
+		key      []byte
+		wantName string
+		wantErr  bool
+	}{
+		{name: "matching pair", cert: certPEM, key: keyPEM, wantName: "plugin.example.com"},
+		// 证书链中第一个证书为叶子证书
+		{name: "chain", cert: append(append([]byte{}, certPEM...), otherCert...), key: keyPEM, wantName: "plugin.example.com"},
+		{name: "mismatched key", cert: certPEM, key: otherKey, wantErr: true},
+		{name: "key of second certificate", cert: append(append([]byte{}, certPEM...), otherCert...), key: otherKey, wantErr: true},
+		{name: "invalid cert", cert: []byte("not a certificate"), key: keyPEM, wantErr: true},
+		{name: "invalid key", cert: certPEM, key: []byte("not a key"), wantErr: true},
+		{name: "empty", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf, err := ParseCertificate(tt.cert, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParseCertificate succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCertificate: %v", err)
+			}
+			if len(leaf.DNSNames) == 0 || leaf.DNSNames[0] != tt.wantName {
+				t.Fatalf("ParseCertificate returned %v, want %s", leaf.DNSNames, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCertificateDomain(t *testing.T) {
+	tests := []struct {
+		commonName string
+		dnsNames   []string
+		want       string
+	}{
+		{commonName: "cn.example.com", dnsNames: []string{"Plugin.Example.com", "b.example.com"}, want: "plugin.example.com"},
+		{commonName: "CN.Example.com", want: "cn.example.com"},
+		{dnsNames: []string{"*.example.com"}, want: "*.example.com"},
+		{want: ""},
+	}
+	for _, tt := range tests {
+		certPEM, keyPEM := newTestCertificate(t, tt.commonName, tt.dnsNames...)
+		leaf, err := ParseCertificate(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("ParseCertificate: %v", err)
+		}
+		if got := CertificateDomain(leaf); got != tt.want {
+			t.Errorf("CertificateDomain(cn=%q, dns=%v) = %q, want %q", tt.commonName, tt.dnsNames, got, tt.want)
+		}
+	}
+}
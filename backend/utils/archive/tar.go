@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// TarGzWriter 用于写入 tar.gz 归档文件
+type TarGzWriter struct {
+	file *os.File
+	gw   *gzip.Writer
+	tw   *tar.Writer
+}
+
+// NewTarGzWriter 创建一个新的 tar.gz 归档文件
+func NewTarGzWriter(dst string) (*TarGzWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	gw := gzip.NewWriter(file)
+	return &TarGzWriter{
+		file: file,
+		gw:   gw,
+		tw:   tar.NewWriter(gw),
+	}, nil
+}
+
+// AddBytes 将内存中的内容写入归档
+func (w *TarGzWriter) AddBytes(name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+// AddFile 将本地文件写入归档
+func (w *TarGzWriter) AddFile(name, src string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := w.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tw, file)
+	return err
+}
+
+// AddDir 将目录递归写入归档，prefix 为归档内的目录前缀，exclude 为需要跳过的相对路径
+func (w *TarGzWriter) AddDir(prefix, srcDir string, exclude ...string) error {
+	if _, err := os.Stat(srcDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return filepath.Walk(srcDir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		for _, e := range exclude {
+			if rel == e {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := w.tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w.tw, f)
+		return err
+	})
+}
+
+// Close 关闭归档文件
+func (w *TarGzWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	if err := w.gw.Close(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// ReadFile 从 tar.gz 归档中读取单个文件的内容
+func ReadFile(src, name string) ([]byte, error) {
+	var data []byte
+	found := false
+	err := walk(src, func(header *tar.Header, reader io.Reader) error {
+		if found || path.Clean(header.Name) != path.Clean(name) {
+			return nil
+		}
+		found = true
+		var err error
+		data, err = io.ReadAll(reader)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("file %s not found in archive", name)
+	}
+	return data, nil
+}
+
+// Extract 将归档中 prefix 目录下的内容解压到 dstDir，解压时会去掉 prefix
+func Extract(src, prefix, dstDir string) error {
+	prefix = strings.Trim(prefix, "/")
+	return walk(src, func(header *tar.Header, reader io.Reader) error {
+		name := path.Clean(header.Name)
+		if prefix != "" {
+			if name == prefix {
+				return nil
+			}
+			if !strings.HasPrefix(name, prefix+"/") {
+				return nil
+			}
+			name = strings.TrimPrefix(name, prefix+"/")
+		}
+
+		target := filepath.Join(dstDir, filepath.FromSlash(name))
+		// 防止路径穿越
+		if !within(dstDir, target) || target == filepath.Clean(dstDir) {
+			return fmt.Errorf("illegal file path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return mkdirWithin(dstDir, target, os.FileMode(header.Mode)|0700)
+		case tar.TypeSymlink:
+			// 链接解析后的目标必须位于 dstDir 中，否则后续条目可以通过链接写到 dstDir 之外
+			linkTarget := header.Linkname
+			if !filepath.IsAbs(linkTarget) {
+				linkTarget = filepath.Join(filepath.Dir(target), linkTarget)
+			}
+			if !within(dstDir, linkTarget) {
+				return fmt.Errorf("illegal symlink in archive: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := mkdirWithin(dstDir, filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			_ = os.Remove(target)
+			return os.Symlink(header.Linkname, target)
+		case tar.TypeReg:
+			if err := mkdirWithin(dstDir, filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			// 已存在的同名链接不跟随，直接替换为普通文件
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				_ = os.Remove(target)
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			_, err = io.Copy(file, reader)
+			return err
+		}
+		return nil
+	})
+}
+
+// within 判断 target 是否位于 dir 中（包括 dir 本身），只比较路径字符串
+func within(dir, target string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(target))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// mkdirWithin 创建目录，并检查解析链接后的实际路径仍位于 dir 中
+func mkdirWithin(dir, target string, perm os.FileMode) error {
+	if err := os.MkdirAll(target, perm); err != nil {
+		return err
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	realTarget, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return err
+	}
+	if !within(realDir, realTarget) {
+		return fmt.Errorf("illegal file path in archive: %s", target)
+	}
+	return nil
+}
+
+// walk 遍历 tar.gz 归档中的每个条目
+func walk(src string, fn func(header *tar.Header, reader io.Reader) error) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os/exec"
 	"time"
 )
@@ -75,3 +77,21 @@ func ExecWithCheck(name string, a ...string) (string, error) {
 	}
 	return stdout.String(), nil
 }
+
+// ExecWithIO 不经过 shell 直接执行命令，参数不会被 shell 解析，stdin 与 stdout 为 nil 时不使用
+func ExecWithIO(timeout time.Duration, stdin io.Reader, stdout io.Writer, name string, a ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, a...)
+	var stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("command timed out")
+		}
+		return handleErr(bytes.Buffer{}, stderr, err)
+	}
+	return "", nil
+}
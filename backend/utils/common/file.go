@@ -8,3 +8,12 @@ func CreateDir(dirPath string) error {
 	}
 	return nil
 }
+
+// FileExists 判断文件是否存在
+func FileExists(filePath string) bool {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
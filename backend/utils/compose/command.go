@@ -4,6 +4,9 @@ import (
 	"doo-store/backend/config"
 	"doo-store/backend/utils/cmd"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 func Pull(filePath string) (string, error) {
@@ -48,6 +51,16 @@ func Operate(filePath, operation string) (string, error) {
 	return stdout, err
 }
 
+// ProjectName 获取 compose 文件对应的项目名称
+func ProjectName(filePath string) string {
+	name := filepath.Base(filepath.Dir(filePath))
+	if config.EnvConfig.App().SHARED_COMPOSE {
+		name = config.EnvConfig.App().SHARED_COMPOSE_NAME
+	}
+	// 与 docker compose 保持一致，只保留小写字母、数字、下划线和短横线
+	return regexp.MustCompile(`[^a-z0-9_-]`).ReplaceAllString(strings.ToLower(name), "")
+}
+
 func getInsertPart() string {
 	if config.EnvConfig.App().SHARED_COMPOSE {
 		return fmt.Sprintf(" -p %s", config.EnvConfig.App().SHARED_COMPOSE_NAME)
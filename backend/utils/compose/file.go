@@ -48,14 +48,17 @@ type ServiceConfig struct {
 }
 
 type NetworkConfig struct {
-	External bool `yaml:"external"`
+	External   bool `yaml:"external"`
+	EnableIPv6 bool `yaml:"enable_ipv6,omitempty"`
 }
 
 type NetworkSettings struct {
-	IPAddress string `yaml:"ipv4_address,omitempty"` // 添加静态IP地址
+	IPAddress   string `yaml:"ipv4_address,omitempty"` // 添加静态IP地址
+	IPv6Address string `yaml:"ipv6_address,omitempty"` // 静态IPv6地址
 }
 
 type VolumeConfig struct {
+	Name       string            `yaml:"name,omitempty"`
 	Driver     string            `yaml:"driver,omitempty"`
 	DriverOpts map[string]string `yaml:"driver_opts,omitempty"`
 	External   bool              `yaml:"external,omitempty"`
@@ -280,7 +283,20 @@ func (dcc *DockerComposeConfig) ExtractIpAddress() []string {
 	return ipList
 }
 
-// 提取 Docker Compose 文件中的 IP 地址
+// 提取 Docker Compose 文件中的 IPv6 地址
+func (dcc *DockerComposeConfig) ExtractIp6Address() []string {
+	var ipList []string
+	for _, serviceConfig := range dcc.Services {
+		for _, networkConfig := range serviceConfig.Networks {
+			if networkConfig.IPv6Address != "" {
+				ipList = append(ipList, networkConfig.IPv6Address)
+			}
+		}
+	}
+	return ipList
+}
+
+// 提取 Docker Compose 文件中的容器名
 func (dcc *DockerComposeConfig) ExtractContainerName() []string {
 	var containerNameList []string
 	for _, serviceConfig := range dcc.Services {
@@ -288,3 +304,19 @@ func (dcc *DockerComposeConfig) ExtractContainerName() []string {
 	}
 	return containerNameList
 }
+
+// 提取 Docker Compose 文件中由 compose 管理的命名卷，返回卷的键与实际卷名的映射
+func (dcc *DockerComposeConfig) ExtractVolumes(projectName string) map[string]string {
+	volumes := make(map[string]string)
+	for key, volumeConfig := range dcc.Volumes {
+		if volumeConfig.External {
+			continue
+		}
+		name := volumeConfig.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s", projectName, key)
+		}
+		volumes[key] = name
+	}
+	return volumes
+}
@@ -0,0 +1,97 @@
+package compose
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Remapper 迁移主机时替换 docker-compose 中与主机相关的值
+// 只处理服务的 container_name、服务 networks 与顶层 networks 的网络名、ipv4_address，其余内容保持不变
+type Remapper struct {
+	ContainerNames map[string]string      // 按整个值匹配的容器名
+	Networks       map[string]string      // 按整个值匹配的网络名
+	IPAddress      func(ip string) string // 替换 ipv4_address
+}
+
+// Remap 替换 docker-compose 内容中的指定字段
+func (r Remapper) Remap(content string) (string, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(content), root); err != nil {
+		return "", fmt.Errorf("parse docker-compose failed: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return content, nil
+	}
+	doc := root.Content[0]
+	if services := mappingValue(doc, "services"); services != nil && services.Kind == yaml.MappingNode {
+		for i := 1; i < len(services.Content); i += 2 {
+			service := services.Content[i]
+			if name := mappingValue(service, "container_name"); name != nil {
+				r.replace(name, r.ContainerNames)
+			}
+			if networks := mappingValue(service, "networks"); networks != nil {
+				r.remapNetworks(networks, true)
+			}
+		}
+	}
+	if networks := mappingValue(doc, "networks"); networks != nil {
+		r.remapNetworks(networks, false)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// remapNetworks 替换网络名，服务的网络配置中同时替换 ipv4_address，顶层网络配置中同时替换 name
+func (r Remapper) remapNetworks(networks *yaml.Node, service bool) {
+	switch networks.Kind {
+	case yaml.SequenceNode:
+		for _, item := range networks.Content {
+			r.replace(item, r.Networks)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(networks.Content); i += 2 {
+			r.replace(networks.Content[i], r.Networks)
+			settings := networks.Content[i+1]
+			if service {
+				if ip := mappingValue(settings, "ipv4_address"); ip != nil && ip.Kind == yaml.ScalarNode && r.IPAddress != nil {
+					ip.Value = r.IPAddress(ip.Value)
+				}
+			} else if name := mappingValue(settings, "name"); name != nil {
+				r.replace(name, r.Networks)
+			}
+		}
+	}
+}
+
+func (r Remapper) replace(node *yaml.Node, values map[string]string) {
+	if node.Kind != yaml.ScalarNode {
+		return
+	}
+	if value, ok := values[node.Value]; ok {
+		node.Value = value
+	}
+}
+
+// mappingValue 获取映射节点中 key 对应的值节点
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
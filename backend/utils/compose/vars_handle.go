@@ -49,7 +49,7 @@ func parseEnvContent(envContent string) (map[string]string, error) {
 		}
 
 		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+		value := UnescapeEnvValue(strings.TrimSpace(parts[1]))
 
 		// 检查键是否为空
 		if key == "" {
@@ -73,3 +73,44 @@ func replaceEnvVars(content string, envMap map[string]string) string {
 
 	return content
 }
+
+// EscapeEnvValue 转义 .env 文件中的值，$ 转义为 $$ 避免被 docker compose 当作变量插值，
+// 包含换行、引号、反斜杠、#或首尾空白的值会被双引号包裹
+func EscapeEnvValue(value string) string {
+	value = strings.ReplaceAll(value, "$", "$$")
+	if value == "" || (!strings.ContainsAny(value, "\n\r\"\\#") && strings.TrimSpace(value) == value) {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// UnescapeEnvValue 还原由 EscapeEnvValue 转义的值
+func UnescapeEnvValue(value string) string {
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return strings.ReplaceAll(value, "$$", "$")
+	}
+	value = value[1 : len(value)-1]
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '$' && i+1 < len(value) && value[i+1] == '$' {
+			builder.WriteByte('$')
+			i++
+			continue
+		}
+		if value[i] != '\\' || i == len(value)-1 {
+			builder.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String()
+}
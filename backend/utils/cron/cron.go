@@ -0,0 +1,246 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule 解析后的 cron 表达式（分 时 日 月 周）
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// 日与周同时被限制时，两者满足其一即可（与标准 cron 行为一致）
+	domRestricted bool
+	dowRestricted bool
+	// 分与时都不以 * 开头的固定时间任务，夏令时切换时只执行一次
+	fixed bool
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse 解析标准的5段 cron 表达式，支持 *、*/n、a-b、a-b/n、逗号列表、月份与星期的英文缩写以及 @daily 等描述符
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expr, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d: %s", len(fields), spec)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	// 7 与 0 都表示星期日
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	// 以 * 开头的字段（包括 */n）不视为限制，与标准 cron 一致
+	s.domRestricted = !isStar(fields[2])
+	s.dowRestricted = !isStar(fields[4])
+	s.fixed = !isStar(fields[0]) && !isStar(fields[1])
+	return s, nil
+}
+
+func isStar(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+// Validate 校验 cron 表达式是否合法
+func Validate(spec string) error {
+	_, err := Parse(spec)
+	return err
+}
+
+// Next 返回晚于 t 的下一次执行时间，表达式无法匹配时返回零值
+// 夏令时开始时被跳过的固定时间任务在切换后立即执行，夏令时结束时重复的时间只执行一次
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// 最多向后查找5年
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.fixed && s.skippedMatch(t) {
+			return t
+		}
+		var next time.Time
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0, s.fixed && repeated(t):
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+		// 目标时间在夏令时开始时不存在，time.Date 可能返回更早的时间，改为前进到下一个整点
+		if !next.After(t) {
+			next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// matches 判断 t 的本地时间是否匹配
+func (s *Schedule) matches(t time.Time) bool {
+	return s.month&(1<<uint(t.Month())) != 0 && s.dayMatches(t) &&
+		s.hour&(1<<uint(t.Hour())) != 0 && s.minute&(1<<uint(t.Minute())) != 0
+}
+
+// skippedMatch 判断 t 是否为夏令时开始的时刻，并且切换时跳过的本地时间中有匹配的时间
+func (s *Schedule) skippedMatch(t time.Time) bool {
+	name, offset := t.Zone()
+	_, before := t.Add(-time.Minute).Zone()
+	if before >= offset {
+		return false
+	}
+	zone := time.FixedZone(name, offset)
+	for skipped := t.Add(-time.Duration(offset-before) * time.Second).In(zone); skipped.Before(t); skipped = skipped.Add(time.Minute) {
+		if s.matches(skipped) {
+			return true
+		}
+	}
+	return false
+}
+
+// repeated 判断 t 的本地时间是否为夏令时结束时第二次出现
+func repeated(t time.Time) bool {
+	_, offset := t.Zone()
+	_, before := t.Add(-2 * time.Hour).Zone()
+	if before <= offset {
+		return false
+	}
+	_, earlier := t.Add(-time.Duration(before-offset) * time.Second).Zone()
+	return earlier == before
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// parseField 解析单个字段，返回位图
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		r, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= r
+	}
+	return bits, nil
+}
+
+func parseRange(expr string, b bounds) (uint64, error) {
+	rangeExpr, step := expr, 1
+	if i := strings.Index(expr, "/"); i >= 0 {
+		var err error
+		rangeExpr = expr[:i]
+		step, err = strconv.Atoi(expr[i+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step: %s", expr)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+		start, end = b.min, b.max
+	case strings.Contains(rangeExpr, "-"):
+		parts := strings.SplitN(rangeExpr, "-", 2)
+		var err error
+		if start, err = parseValue(parts[0], b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(parts[1], b); err != nil {
+			return 0, err
+		}
+	default:
+		var err error
+		if start, err = parseValue(rangeExpr, b); err != nil {
+			return 0, err
+		}
+		end = start
+		// n/step 表示从 n 开始直到最大值
+		if step > 1 || strings.Contains(expr, "/") {
+			end = b.max
+		}
+	}
+	if start > end {
+		return 0, fmt.Errorf("invalid range: %s", expr)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value: %s", value)
+	}
+	if n < b.min || n > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, b.min, b.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,144 @@
+package cron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/-1 * * * *",
+		"1-2-3 * * * *",
+		"1,,2 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every 5m",
+	}
+	for _, spec := range specs {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		spec string
+		from string
+		want []string
+	}{
+		// 范围、步长与列表
+		{spec: "0 9-11 * * *", from: "2024-01-01 10:30", want: []string{"2024-01-01 11:00", "2024-01-02 09:00", "2024-01-02 10:00"}},
+		{spec: "*/15 * * * *", from: "2024-01-01 10:07", want: []string{"2024-01-01 10:15", "2024-01-01 10:30", "2024-01-01 10:45", "2024-01-01 11:00"}},
+		{spec: "5/20 * * * *", from: "2024-01-01 10:00", want: []string{"2024-01-01 10:05", "2024-01-01 10:25", "2024-01-01 10:45", "2024-01-01 11:05"}},
+		{spec: "0 1-10/3 * * *", from: "2024-01-01 00:00", want: []string{"2024-01-01 01:00", "2024-01-01 04:00", "2024-01-01 07:00", "2024-01-01 10:00", "2024-01-02 01:00"}},
+		{spec: "0 0 1,15 * *", from: "2024-01-01 00:00", want: []string{"2024-01-15 00:00", "2024-02-01 00:00", "2024-02-15 00:00"}},
+		{spec: "0 0 1 jan,JUL *", from: "2024-01-01 00:00", want: []string{"2024-07-01 00:00", "2025-01-01 00:00"}},
+		{spec: "0 0 * * mon-fri", from: "2024-01-05 00:00", want: []string{"2024-01-08 00:00", "2024-01-09 00:00"}},
+		// 月末与闰年
+		{spec: "0 0 31 * *", from: "2024-01-31 00:00", want: []string{"2024-03-31 00:00", "2024-05-31 00:00"}},
+		{spec: "0 0 29 2 *", from: "2024-03-01 00:00", want: []string{"2028-02-29 00:00"}},
+		// 7 与 0 都表示星期日
+		{spec: "0 0 * * 7", from: "2024-01-03 00:00", want: []string{"2024-01-07 00:00", "2024-01-14 00:00"}},
+		{spec: "0 0 * * 5-7", from: "2024-01-03 00:00", want: []string{"2024-01-05 00:00", "2024-01-06 00:00", "2024-01-07 00:00", "2024-01-12 00:00"}},
+		{spec: "0 0 * * sun", from: "2024-01-03 00:00", want: []string{"2024-01-07 00:00"}},
+		// 日与周同时被限制时满足其一即可
+		{spec: "0 0 13 * 5", from: "2024-01-01 00:00", want: []string{"2024-01-05 00:00", "2024-01-12 00:00", "2024-01-13 00:00", "2024-01-19 00:00"}},
+		// 以 * 开头的字段不视为限制，两者需要同时满足
+		{spec: "0 0 */2 * 1", from: "2023-12-31 00:00", want: []string{"2024-01-01 00:00", "2024-01-15 00:00", "2024-01-29 00:00"}},
+		{spec: "0 0 1 * */2", from: "2024-01-01 00:00", want: []string{"2024-02-01 00:00", "2024-06-01 00:00"}},
+		{spec: "0 0 ? * 1", from: "2024-01-01 00:00", want: []string{"2024-01-08 00:00"}},
+		// 描述符
+		{spec: "@daily", from: "2024-01-01 10:00", want: []string{"2024-01-02 00:00"}},
+		{spec: "@Weekly", from: "2024-01-01 10:00", want: []string{"2024-01-07 00:00"}},
+		{spec: "@yearly", from: "2024-01-01 00:00", want: []string{"2025-01-01 00:00"}},
+		// 无法匹配的日期返回零值
+		{spec: "0 0 30 2 *", from: "2024-01-01 00:00", want: []string{""}},
+		{spec: "0 0 31 4,6,9,11 *", from: "2024-01-01 00:00", want: []string{""}},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		from := mustParse(t, tt.from, time.UTC)
+		for _, want := range tt.want {
+			next := schedule.Next(from)
+			if got := format(next); got != want {
+				t.Errorf("%q Next(%s) = %q, want %q", tt.spec, format(from), got, want)
+				break
+			}
+			from = next
+		}
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want []string
+	}{
+		// 2024-03-10 02:00 EST 跳到 03:00 EDT
+		{name: "spring fixed time in gap", spec: "30 2 * * *", from: "2024-03-10 00:00", want: []string{"2024-03-10 03:00 EDT", "2024-03-11 02:30 EDT"}},
+		{name: "spring hour after gap", spec: "0 5 * * *", from: "2024-03-10 01:05", want: []string{"2024-03-10 05:00 EDT"}},
+		{name: "spring fixed time after gap", spec: "0 3 * * *", from: "2024-03-10 01:59", want: []string{"2024-03-10 03:00 EDT", "2024-03-11 03:00 EDT"}},
+		{name: "spring wildcard", spec: "*/30 * * * *", from: "2024-03-10 01:15", want: []string{"2024-03-10 01:30 EST", "2024-03-10 03:00 EDT", "2024-03-10 03:30 EDT"}},
+		{name: "spring other day", spec: "30 2 * * 1", from: "2024-03-10 00:00", want: []string{"2024-03-11 02:30 EDT"}},
+		// 2024-11-03 02:00 EDT 回到 01:00 EST
+		{name: "fall fixed time", spec: "30 1 * * *", from: "2024-11-03 00:00", want: []string{"2024-11-03 01:30 EDT", "2024-11-04 01:30 EST"}},
+		{name: "fall hourly", spec: "0 * * * *", from: "2024-11-03 00:30", want: []string{"2024-11-03 01:00 EDT", "2024-11-03 01:00 EST", "2024-11-03 02:00 EST"}},
+		{name: "fall wildcard minutes", spec: "*/30 1 * * *", from: "2024-11-03 00:30", want: []string{"2024-11-03 01:00 EDT", "2024-11-03 01:30 EDT", "2024-11-03 01:00 EST", "2024-11-03 01:30 EST", "2024-11-04 01:00 EST"}},
+		{name: "fall after repeated hour", spec: "0 2 * * *", from: "2024-11-03 00:00", want: []string{"2024-11-03 02:00 EST", "2024-11-04 02:00 EST"}},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("%s: Parse(%q): %v", tt.name, tt.spec, err)
+			continue
+		}
+		from := mustParse(t, tt.from, newYork)
+		for _, want := range tt.want {
+			next := schedule.Next(from)
+			if got := next.Format("2006-01-02 15:04 MST"); got != want {
+				t.Errorf("%s: %q Next(%s) = %q, want %q", tt.name, tt.spec, from.Format("2006-01-02 15:04 MST"), got, want)
+				break
+			}
+			from = next
+		}
+	}
+}
+
+func mustParse(t *testing.T, value string, loc *time.Location) time.Time {
+	t.Helper()
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func format(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
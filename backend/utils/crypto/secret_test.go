@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	for _, plain := range []string{"", "secret", "中文密码", strings.Repeat("x", 4096)} {
+		encrypted, err := Encrypt(plain, "key")
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", plain, err)
+		}
+		if !IsEncrypted(encrypted) {
+			t.Fatalf("Encrypt(%q) = %q, missing prefix", plain, encrypted)
+		}
+		decrypted, err := Decrypt(encrypted, "key")
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", encrypted, err)
+		}
+		if decrypted != plain {
+			t.Fatalf("Decrypt(Encrypt(%q)) = %q", plain, decrypted)
+		}
+	}
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	a, err := Encrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Encrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Fatalf("Encrypt returned the same ciphertext twice: %q", a)
+	}
+}
+
+func TestEncryptPrefixedPlaintext(t *testing.T) {
+	// 以加密前缀开头的明文同样需要加密，否则保存后无法解密
+	for _, plain := range []string{EncryptedPrefix, EncryptedPrefix + "abc"} {
+		encrypted, err := Encrypt(plain, "key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypted == plain {
+			t.Fatalf("Encrypt(%q) returned the plaintext", plain)
+		}
+		decrypted, err := Decrypt(encrypted, "key")
+		if err != nil {
+			t.Fatalf("Decrypt(%q): %v", encrypted, err)
+		}
+		if decrypted != plain {
+			t.Fatalf("Decrypt(Encrypt(%q)) = %q", plain, decrypted)
+		}
+	}
+}
+
+func TestDecryptPlainValue(t *testing.T) {
+	plain, err := Decrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain != "secret" {
+		t.Fatalf("Decrypt(plain) = %q", plain)
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	encrypted, err := Encrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(encrypted, "other"); err == nil {
+		t.Fatal("Decrypt with wrong key succeeded")
+	}
+}
+
+func TestDecryptInvalidValue(t *testing.T) {
+	encrypted, err := Encrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	if tampered == encrypted {
+		tampered = encrypted[:len(encrypted)-2] + "BB"
+	}
+	for name, value := range map[string]string{
+		"tampered":   tampered,
+		"short":      EncryptedPrefix + "AAAA",
+		"not base64": EncryptedPrefix + "!!!",
+	} {
+		if _, err := Decrypt(value, "key"); err == nil {
+			t.Errorf("Decrypt(%s) succeeded", name)
+		}
+	}
+}
+
+func TestEmptyKey(t *testing.T) {
+	if _, err := Encrypt("secret", ""); err != ErrEmptyKey {
+		t.Fatalf("Encrypt with empty key: %v", err)
+	}
+	encrypted, err := Encrypt("secret", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(encrypted, ""); err != ErrEmptyKey {
+		t.Fatalf("Decrypt with empty key: %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"doo-store/backend/utils/cmd"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -344,3 +347,74 @@ func (c Client) MoveFileWithCheck(containerId, srcPath, dstPath string) error {
 	// 移动文件
 	return c.MoveFileInContainer(containerId, srcPath, dstPath)
 }
+
+// ExecInContainer 在容器中执行命令，返回标准输出和退出码
+func (c Client) ExecInContainer(containerId string, command []string) (string, int, error) {
+	execConfig := container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          command,
+	}
+
+	execIDResp, err := c.cli.ContainerExecCreate(context.Background(), containerId, execConfig)
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating exec: %v", err)
+	}
+
+	execAttachResp, err := c.cli.ContainerExecAttach(context.Background(), execIDResp.ID, container.ExecStartOptions{})
+	if err != nil {
+		return "", 0, fmt.Errorf("error attaching to exec: %v", err)
+	}
+	defer execAttachResp.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err = stdcopy.StdCopy(&stdout, &stderr, execAttachResp.Reader); err != nil && err != io.EOF {
+		return "", 0, fmt.Errorf("error during command execution: %v", err)
+	}
+
+	inspectResp, err := c.cli.ContainerExecInspect(context.Background(), execIDResp.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("error inspecting exec: %v", err)
+	}
+	if inspectResp.ExitCode != 0 && stderr.Len() > 0 {
+		return stdout.String(), inspectResp.ExitCode, errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), inspectResp.ExitCode, nil
+}
+
+// ListFilesInContainer 列出容器中目录下的文件名，目录不存在时返回空列表
+func (c Client) ListFilesInContainer(containerId, dir string) ([]string, error) {
+	stdout, exitCode, err := c.ExecInContainer(containerId, []string{"ls", "-1", dir})
+	if exitCode != 0 {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, name := range strings.Split(stdout, "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// ReadFileFromContainer 读取容器中的文件内容
+func (c Client) ReadFileFromContainer(containerId, filePath string) ([]byte, error) {
+	reader, _, err := c.cli.CopyFromContainer(context.Background(), containerId, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("not a regular file: %s", filePath)
+	}
+	return io.ReadAll(tr)
+}
@@ -1,56 +1,218 @@
 package docker
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+	"math/big"
+	"math/bits"
 	"net"
+	"sort"
 	"sync"
+
 	log "github.com/sirupsen/logrus"
 )
 
 var (
 	GlobalIPAllocator *IPAllocator
+	// GlobalIP6Allocator IPv6 分配器，未配置 IPv6 网段时为 nil
+	GlobalIP6Allocator *IPAllocator
 	// 默认的网段配置
 	DefaultCIDR = "10.92.114.30/24"
+	// IP范围超过允许的数量
+	ErrIPRangeTooLarge = errors.New("IP范围过大")
 )
 
+// maxHostBits 分配器管理的最大主机位数，更大的 IPv6 网段只使用前 2^63 个地址
+const maxHostBits = 63
+
 // InitIPAllocator 初始化全局IP分配器
 func InitIPAllocator(cidr string, usedIPs []string) error {
 	if cidr == "" {
 		cidr = DefaultCIDR
 	}
 
+	allocator, err := newAllocatorWithIPs(cidr, usedIPs)
+	if err != nil {
+		return err
+	}
+	GlobalIPAllocator = allocator
+	return nil
+}
+
+// InitIP6Allocator 初始化全局IPv6分配器，cidr 为空时不启用IPv6
+func InitIP6Allocator(cidr string, usedIPs []string) error {
+	if cidr == "" {
+		GlobalIP6Allocator = nil
+		return nil
+	}
+
+	allocator, err := newAllocatorWithIPs(cidr, usedIPs)
+	if err != nil {
+		return err
+	}
+	if !allocator.IsIPv6() {
+		return fmt.Errorf("初始化IP分配器失败: %s 不是IPv6网段", cidr)
+	}
+	GlobalIP6Allocator = allocator
+	return nil
+}
+
+// AllocatorFor 获取IP所属网段的分配器，不属于任何网段时返回 nil
+func AllocatorFor(ip string) *IPAllocator {
+	for _, allocator := range []*IPAllocator{GlobalIPAllocator, GlobalIP6Allocator} {
+		if allocator != nil && allocator.Contains(ip) {
+			return allocator
+		}
+	}
+	return nil
+}
+
+func newAllocatorWithIPs(cidr string, usedIPs []string) (*IPAllocator, error) {
 	allocator, err := NewIPAllocator(cidr)
 	if err != nil {
-		return fmt.Errorf("初始化IP分配器失败: %v", err)
+		return nil, fmt.Errorf("初始化IP分配器失败: %v", err)
 	}
 
 	for _, ip := range usedIPs {
-		allocator.RegisterIP(ip)
+		if allocator.Contains(ip) {
+			allocator.RegisterIP(ip)
+			fmt.Printf("已使用的ip: %v\n", ip)
+		}
 	}
+	return allocator, nil
+}
 
-	for ip := range allocator.usedIPs {
-		fmt.Printf("已使用的ip: %v\n", ip)
+// bitmap 稀疏位图，只保存包含已设置位的 64 位块
+type bitmap map[uint64]uint64
+
+func (b bitmap) has(i uint64) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitmap) set(i uint64) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitmap) clear(i uint64) {
+	word := b[i/64] &^ (1 << (i % 64))
+	if word == 0 {
+		delete(b, i/64)
+		return
 	}
+	b[i/64] = word
+}
 
-	GlobalIPAllocator = allocator
-	return nil
+// offsets 按顺序返回所有已设置的位
+func (b bitmap) offsets() []uint64 {
+	words := make([]uint64, 0, len(b))
+	for k := range b {
+		words = append(words, k)
+	}
+	sort.Slice(words, func(i, j int) bool { return words[i] < words[j] })
+
+	offsets := []uint64{}
+	for _, k := range words {
+		for word := b[k]; word != 0; word &= word - 1 {
+			offsets = append(offsets, k*64+uint64(bits.TrailingZeros64(word)))
+		}
+	}
+	return offsets
+}
+
+// offsetRange 闭区间 [start, end] 内的偏移
+type offsetRange struct {
+	start uint64
+	end   uint64
+}
+
+// rangeSet 按起始偏移排序、互不重叠且不相邻的区间集合
+type rangeSet []offsetRange
+
+// search 返回第一个结束偏移不小于 i 的区间下标
+func (r rangeSet) search(i uint64) int {
+	return sort.Search(len(r), func(k int) bool { return r[k].end >= i })
+}
+
+// find 返回包含 i 的区间，不存在时返回 false
+func (r rangeSet) find(i uint64) (offsetRange, bool) {
+	k := r.search(i)
+	if k < len(r) && r[k].start <= i {
+		return r[k], true
+	}
+	return offsetRange{}, false
+}
+
+// has 偏移是否在集合中
+func (r rangeSet) has(i uint64) bool {
+	_, ok := r.find(i)
+	return ok
+}
+
+// next 返回第一个起始偏移大于 i 的区间的起始偏移，不存在时返回 false
+func (r rangeSet) next(i uint64) (uint64, bool) {
+	k := sort.Search(len(r), func(k int) bool { return r[k].start > i })
+	if k < len(r) {
+		return r[k].start, true
+	}
+	return 0, false
 }
 
-// IPAllocator IP地址管理器
+// add 添加区间 [start, end]，与已有的重叠或相邻区间合并
+func (r rangeSet) add(start, end uint64) rangeSet {
+	// 第一个可能与新区间重叠或相邻的区间
+	lo := r.search(start)
+	if start > 0 && lo > 0 && r[lo-1].end == start-1 {
+		lo--
+	}
+	hi := lo
+	for hi < len(r) && (r[hi].start <= end || r[hi].start-1 == end) {
+		start = min(start, r[hi].start)
+		end = max(end, r[hi].end)
+		hi++
+	}
+
+	merged := append(rangeSet{}, r[:lo]...)
+	merged = append(merged, offsetRange{start: start, end: end})
+	return append(merged, r[hi:]...)
+}
+
+// remove 从集合中移除偏移 i，所在区间被拆分
+func (r rangeSet) remove(i uint64) rangeSet {
+	k := r.search(i)
+	if k >= len(r) || r[k].start > i {
+		return r
+	}
+
+	parts := rangeSet{}
+	if cur := r[k]; cur.start < i {
+		parts = append(parts, offsetRange{start: cur.start, end: i - 1})
+	}
+	if cur := r[k]; i < cur.end {
+		parts = append(parts, offsetRange{start: i + 1, end: cur.end})
+	}
+	result := append(rangeSet{}, r[:k]...)
+	result = append(result, parts...)
+	return append(result, r[k+1:]...)
+}
+
+// IPAllocator IP地址管理器，支持IPv4与IPv6网段
+// 已使用的地址以相对网络地址的偏移保存在稀疏位图中，排除的地址保存为区间
+// 分配时从游标位置开始按块查找空闲地址，并跳过排除的区间
 type IPAllocator struct {
-	mu          sync.RWMutex
-	usedIPs     map[string]bool
-	network     *net.IPNet      // 网段信息
-	startIP     net.IP          // 起始IP
-	endIP       net.IP          // 结束IP
-	totalIPs    int             // 可分配IP总数
-	excludedIPs map[string]bool // 不允许分配的IP列表
+	mu       sync.RWMutex
+	network  *net.IPNet // 网段信息
+	base     *big.Int   // 网络地址
+	ipLen    int        // IP字节长度，IPv4 为 4，IPv6 为 16
+	start    uint64     // 起始IP的偏移，之前的地址不允许分配
+	end      uint64     // 结束IP的偏移
+	cursor   uint64     // 分配游标，start 到 cursor 之间的地址均已被使用或排除
+	used     bitmap     // 已使用的IP
+	excluded rangeSet   // 不允许分配的IP
 }
 
 // NewIPAllocator 创建新的IP分配器
-// cidr 格式为 "IP/掩码"，例如 "10.92.114.30/24"
-// IP 部分将作为起始分配点，1 到这个 IP 之前的所有 IP 都会被自动排除
+// cidr 格式为 "IP/掩码"，例如 "10.92.114.30/24" 或 "fd00:dead:beef::100/64"
+// IP 部分将作为起始分配点，1 到这个 IP 之前的所有 IP 都不会被分配
 func NewIPAllocator(cidr string) (*IPAllocator, error) {
 	// 解析CIDR
 	startIP, network, err := net.ParseCIDR(cidr)
@@ -63,35 +225,35 @@ func NewIPAllocator(cidr string) (*IPAllocator, error) {
 		return nil, fmt.Errorf("起始IP %s 不在网段 %s 内", startIP, network)
 	}
 
-	// 计算可用IP数量
-	ones, bits := network.Mask.Size()
-	totalIPs := 1 << (bits - ones)
-	// 排除网络地址和广播地址
-	totalIPs -= 2
+	ipLen := net.IPv6len
+	if network.IP.To4() != nil {
+		ipLen = net.IPv4len
+	}
+	ones, size := network.Mask.Size()
+	hostBits := size - ones
 
-	// 获取网段的结束IP
-	endIP := decrementIP(calculateBroadcastIP(network))
+	allocator := &IPAllocator{
+		network: network,
+		base:    new(big.Int).SetBytes(normalizeIP(network.IP, ipLen)),
+		ipLen:   ipLen,
+		used:    bitmap{},
+	}
+	// 排除网络地址与广播地址，超出管理范围的 IPv6 网段不需要排除最后一个地址
+	if hostBits > maxHostBits {
+		allocator.end = 1<<maxHostBits - 1
+	} else if hostBits >= 2 {
+		allocator.end = 1<<hostBits - 2
+	}
 
-	// 如果起始IP在广播地址之后，返回错误
-	if bytes.Compare(startIP, endIP) > 0 {
+	start, ok := allocator.offsetOf(startIP)
+	if !ok || start > allocator.end || allocator.end == 0 {
 		return nil, fmt.Errorf("起始IP %s 超出网段 %s 的可用范围", startIP, network)
 	}
-
-	// 创建分配器
-	allocator := &IPAllocator{
-		usedIPs:     make(map[string]bool),
-		network:     network,
-		startIP:     startIP,
-		endIP:       endIP,
-		totalIPs:    totalIPs,
-		excludedIPs: make(map[string]bool),
-	}
-	sIP := startIP.String()
-	_ = sIP
-	// 排除从网段第一个可用IP到起始IP之前的所有IP
-	firstIP := incrementIP(network.IP)
-	beforeStartIP := decrementIP(copyIP(startIP))
-	allocator.AddExcludedIPRange(firstIP.String(), beforeStartIP.String())
+	if start == 0 {
+		start = 1
+	}
+	allocator.start = start
+	allocator.cursor = start
 
 	return allocator, nil
 }
@@ -101,19 +263,29 @@ func (a *IPAllocator) AllocateIP() (string, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	currentIP := a.startIP
-	for !currentIP.Equal(a.endIP) {
-		ipStr := currentIP.String()
+	for offset := a.cursor; offset <= a.end; {
+		// 跳过排除的区间
+		if r, ok := a.excluded.find(offset); ok {
+			offset = r.end + 1
+			continue
+		}
 
-		// 跳过已使用的IP和被排除的IP
-		if !a.usedIPs[ipStr] && !a.excludedIPs[ipStr] {
-			if a.isValidIP(currentIP) {
-				a.usedIPs[ipStr] = true
-				return ipStr, nil
-			}
+		// 在当前块中查找 offset 到 limit 之间的空闲地址，limit 不超过下一个排除区间的起点
+		word := offset / 64
+		limit := min(word*64+63, a.end)
+		if next, ok := a.excluded.next(offset); ok && next <= limit {
+			limit = next - 1
+		}
+		free := ^a.used[word] & (^uint64(0) << (offset % 64)) & (^uint64(0) >> (63 - limit%64))
+		if free == 0 {
+			offset = limit + 1
+			continue
 		}
 
-		currentIP = incrementIP(currentIP)
+		offset = word*64 + uint64(bits.TrailingZeros64(free))
+		a.used.set(offset)
+		a.cursor = offset + 1
+		return a.ipAt(offset).String(), nil
 	}
 
 	return "", fmt.Errorf("网段 %v 中没有可用的IP地址", a.network)
@@ -132,21 +304,21 @@ func (a *IPAllocator) RegisterIP(ip string) error {
 	}
 
 	// 检查IP是否在网段内
-	if !a.network.Contains(parsedIP) {
+	offset, ok := a.offsetOf(parsedIP)
+	if !ok {
 		return fmt.Errorf("IP %s 不在网段 %s 内", ip, a.network)
 	}
 
 	// 检查IP是否已被使用或排除
-	if a.usedIPs[ip] {
+	if a.used.has(offset) {
 		return fmt.Errorf("IP %s 已被使用", ip)
 	}
-	if a.excludedIPs[ip] {
-		// return fmt.Errorf("IP %s 在排除列表中", ip)
+	if a.isExcluded(offset) {
 		log.Warnf("IP %s 在排除列表中，但被注册", ip)
 	}
 
 	// 注册IP
-	a.usedIPs[ip] = true
+	a.used.set(offset)
 	return nil
 }
 
@@ -155,31 +327,19 @@ func (a *IPAllocator) ReleaseIP(ip string) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if !a.usedIPs[ip] {
+	offset, ok := a.parseOffset(ip)
+	if !ok || !a.used.has(offset) {
 		return fmt.Errorf("IP %s 未被使用", ip)
 	}
 
-	delete(a.usedIPs, ip)
+	a.used.clear(offset)
+	a.rewind(offset)
 	return nil
 }
 
 // AddExcludedIP 添加不允许分配的IP
 func (a *IPAllocator) AddExcludedIP(ip string) error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	parsedIP := net.ParseIP(ip)
-	if parsedIP == nil {
-		return fmt.Errorf("无效的IP地址: %s", ip)
-	}
-
-	if !a.network.Contains(parsedIP) {
-		return fmt.Errorf("IP %s 不在网段 %v 内", ip, a.network)
-	}
-
-	a.excludedIPs[ip] = true
-	a.totalIPs--
-	return nil
+	return a.AddExcludedIPRange(ip, ip)
 }
 
 // AddExcludedIPRange 添加一个范围的不允许分配的IP
@@ -188,36 +348,11 @@ func (a *IPAllocator) AddExcludedIPRange(startIP, endIP string) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	start := net.ParseIP(startIP)
-	if start == nil {
-		return fmt.Errorf("无效的起始IP: %s", startIP)
-	}
-
-	end := net.ParseIP(endIP)
-	if end == nil {
-		return fmt.Errorf("无效的结束IP: %s", endIP)
-	}
-
-	// 确保IP在网段内
-	if !a.network.Contains(start) || !a.network.Contains(end) {
-		return fmt.Errorf("IP范围 %s-%s 不在网段 %v 内", startIP, endIP, a.network)
-	}
-
-	// 确保起始IP小于等于结束IP
-	if bytes.Compare(start, end) > 0 {
-		return fmt.Errorf("起始IP %s 大于结束IP %s", startIP, endIP)
-	}
-
-	current := start
-	for !current.Equal(incrementIP(end)) {
-		ipStr := current.String()
-		if !a.excludedIPs[ipStr] {
-			a.excludedIPs[ipStr] = true
-			a.totalIPs--
-		}
-		current = incrementIP(current)
+	start, end, err := a.parseRange(startIP, endIP)
+	if err != nil {
+		return err
 	}
-
+	a.excluded = a.excluded.add(start, end)
 	return nil
 }
 
@@ -226,77 +361,163 @@ func (a *IPAllocator) RemoveExcludedIP(ip string) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if !a.excludedIPs[ip] {
+	offset, ok := a.parseOffset(ip)
+	if !ok || !a.excluded.has(offset) {
 		return fmt.Errorf("IP %s 不在排除列表中", ip)
 	}
 
-	delete(a.excludedIPs, ip)
-	a.totalIPs++
+	a.excluded = a.excluded.remove(offset)
+	a.rewind(offset)
 	return nil
 }
 
-// GetExcludedIPs 获取所有被排除的IP
-func (a *IPAllocator) GetExcludedIPs() []string {
+// GetUsedIPs 获取所有已使用的IP
+func (a *IPAllocator) GetUsedIPs() []string {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
-	excludedList := make([]string, 0, len(a.excludedIPs))
-	for ip := range a.excludedIPs {
-		excludedList = append(excludedList, ip)
+	offsets := a.used.offsets()
+	usedList := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		usedList = append(usedList, a.ipAt(offset).String())
 	}
-	return excludedList
+	return usedList
 }
 
-// isValidIP 检查IP是否在网段内且不是网络地址或广播地址
-func (a *IPAllocator) isValidIP(ip net.IP) bool {
-	return a.network.Contains(ip) &&
-		!ip.Equal(a.network.IP) &&
-		!ip.Equal(calculateBroadcastIP(a.network))
+// HostCount 网段中除网络地址和广播地址外的IP数量，超出管理范围的IPv6网段只统计管理范围内的地址
+func (a *IPAllocator) HostCount() uint64 {
+	return a.end
 }
 
-// incrementIP 获取下一个IP地址
-func incrementIP(ip net.IP) net.IP {
-	result := make(net.IP, len(ip))
+// PreStartCount 起始IP之前不允许分配的IP数量，不包括网络地址
+func (a *IPAllocator) PreStartCount() uint64 {
+	return a.start - 1
+}
 
-	copy(result, ip)
-	for i := len(result) - 1; i >= 0; i-- {
-		result[i]++
-		if result[i] > 0 {
-			break
-		}
+// IsHost IP是否为网段中除网络地址和广播地址外的地址
+func (a *IPAllocator) IsHost(ip string) bool {
+	offset, ok := a.parseOffset(ip)
+	return ok && offset >= 1 && offset <= a.end
+}
+
+// Contains IP是否属于分配器的网段
+func (a *IPAllocator) Contains(ip string) bool {
+	_, ok := a.parseOffset(ip)
+	return ok
+}
+
+// Network 分配器的网段
+func (a *IPAllocator) Network() string {
+	return a.network.String()
+}
+
+// IsIPv6 是否为IPv6网段
+func (a *IPAllocator) IsIPv6() bool {
+	return a.ipLen == net.IPv6len
+}
+
+// IsUsed IP是否已被使用
+func (a *IPAllocator) IsUsed(ip string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	offset, ok := a.parseOffset(ip)
+	return ok && a.used.has(offset)
+}
+
+// IsExcluded IP是否不允许分配，包括起始IP之前的地址
+func (a *IPAllocator) IsExcluded(ip string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	offset, ok := a.parseOffset(ip)
+	return ok && a.isExcluded(offset)
+}
+
+// InAllocRange IP是否在可分配的范围内，即起始IP到结束IP之间
+func (a *IPAllocator) InAllocRange(ip string) bool {
+	offset, ok := a.parseOffset(ip)
+	return ok && offset >= a.start && offset <= a.end
+}
+
+// Range 获取网段内从起始IP到结束IP的所有IP，最多返回 limit 个
+func (a *IPAllocator) Range(startIP, endIP string, limit int) ([]string, error) {
+	start, end, err := a.parseRange(startIP, endIP)
+	if err != nil {
+		return nil, err
 	}
-	return result
+	if end-start >= uint64(limit) {
+		return nil, ErrIPRangeTooLarge
+	}
+
+	ips := make([]string, 0, end-start+1)
+	for offset := start; offset <= end; offset++ {
+		ips = append(ips, a.ipAt(offset).String())
+	}
+	return ips, nil
 }
 
-// decrementIP 获取前一个IP地址
-func decrementIP(ip net.IP) net.IP {
-	result := make(net.IP, len(ip))
-	copy(result, ip)
-	for i := len(result) - 1; i >= 0; i-- {
-		result[i]--
-		if result[i] < 255 {
-			break
-		}
+// isExcluded 偏移对应的IP是否不允许分配
+func (a *IPAllocator) isExcluded(offset uint64) bool {
+	return offset < a.start || a.excluded.has(offset)
+}
+
+// rewind 地址被释放后回退分配游标，保证优先分配较小的地址
+func (a *IPAllocator) rewind(offset uint64) {
+	if offset >= a.start && offset < a.cursor {
+		a.cursor = offset
+	}
+}
+
+// parseRange 解析IP范围并返回对应的偏移
+func (a *IPAllocator) parseRange(startIP, endIP string) (uint64, uint64, error) {
+	start, ok := a.parseOffset(startIP)
+	if !ok {
+		return 0, 0, fmt.Errorf("IP %s 不在网段 %v 内", startIP, a.network)
+	}
+	end, ok := a.parseOffset(endIP)
+	if !ok {
+		return 0, 0, fmt.Errorf("IP %s 不在网段 %v 内", endIP, a.network)
 	}
-	return result
+	if start > end {
+		return 0, 0, fmt.Errorf("起始IP %s 大于结束IP %s", startIP, endIP)
+	}
+	return start, end, nil
 }
 
-// calculateBroadcastIP 计算网段的广播地址
-func calculateBroadcastIP(network *net.IPNet) net.IP {
-	broadcastIP := make(net.IP, len(network.IP))
-	copy(broadcastIP, network.IP)
+// parseOffset 解析IP并返回相对网络地址的偏移
+func (a *IPAllocator) parseOffset(ip string) (uint64, bool) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return 0, false
+	}
+	return a.offsetOf(parsedIP)
+}
 
-	// 将IP与反掩码做或运算，得到广播地址
-	for i := range broadcastIP {
-		broadcastIP[i] |= ^network.Mask[i]
+// offsetOf 获取IP相对网络地址的偏移，IP不在网段或超出管理范围时返回 false
+func (a *IPAllocator) offsetOf(ip net.IP) (uint64, bool) {
+	// IPv4 分配器不接受IPv6地址，反之亦然
+	if (ip.To4() != nil) != (a.ipLen == net.IPv4len) || !a.network.Contains(ip) {
+		return 0, false
+	}
+	offset := new(big.Int).SetBytes(normalizeIP(ip, a.ipLen))
+	offset.Sub(offset, a.base)
+	if !offset.IsUint64() || offset.Uint64() > a.end+1 {
+		return 0, false
 	}
+	return offset.Uint64(), true
+}
 
-	return broadcastIP
+// ipAt 获取偏移对应的IP
+func (a *IPAllocator) ipAt(offset uint64) net.IP {
+	value := new(big.Int).Add(a.base, new(big.Int).SetUint64(offset))
+	return net.IP(value.FillBytes(make([]byte, a.ipLen)))
 }
 
-// copyIP 创建IP的副本
-func copyIP(ip net.IP) net.IP {
-	dup := make(net.IP, len(ip))
-	copy(dup, ip)
-	return dup
+// normalizeIP 将IP转换为指定长度的字节表示
+func normalizeIP(ip net.IP, ipLen int) net.IP {
+	if ipLen == net.IPv4len {
+		return ip.To4()
+	}
+	return ip.To16()
 }
@@ -0,0 +1,371 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestAllocator(t *testing.T, cidr string) *IPAllocator {
+	t.Helper()
+	allocator, err := NewIPAllocator(cidr)
+	if err != nil {
+		t.Fatalf("NewIPAllocator(%q): %v", cidr, err)
+	}
+	return allocator
+}
+
+func allocateN(t *testing.T, allocator *IPAllocator, n int) []string {
+	t.Helper()
+	ips := []string{}
+	for i := 0; i < n; i++ {
+		ip, err := allocator.AllocateIP()
+		if err != nil {
+			t.Fatalf("AllocateIP #%d: %v", i, err)
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+func TestNewIPAllocatorStart(t *testing.T) {
+	tests := []struct {
+		cidr    string
+		first   string
+		wantErr bool
+	}{
+		{cidr: "10.92.114.30/24", first: "10.92.114.30"},
+		// 起始IP为网络地址时从第一个主机地址开始
+		{cidr: "10.92.114.0/24", first: "10.92.114.1"},
+		{cidr: "10.92.114.254/24", first: "10.92.114.254"},
+		// 广播地址不能作为起始IP
+		{cidr: "10.92.114.255/24", wantErr: true},
+		{cidr: "10.92.114.1/32", wantErr: true},
+		{cidr: "10.92.114.1/31", wantErr: true},
+		{cidr: "fd00:dead:beef::100/64", first: "fd00:dead:beef::100"},
+		{cidr: "fd00:dead:beef::/64", first: "fd00:dead:beef::1"},
+		{cidr: "fd00::/120", first: "fd00::1"},
+		{cidr: "fd00::ff/120", wantErr: true},
+		{cidr: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		allocator, err := NewIPAllocator(tt.cidr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewIPAllocator(%q) succeeded, want error", tt.cidr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewIPAllocator(%q): %v", tt.cidr, err)
+			continue
+		}
+		ip, err := allocator.AllocateIP()
+		if err != nil || ip != tt.first {
+			t.Errorf("NewIPAllocator(%q).AllocateIP() = %q, %v, want %q", tt.cidr, ip, err, tt.first)
+		}
+	}
+}
+
+func TestAllocateIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidr     string
+		register []string
+		exclude  [][2]string
+		n        int
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "ipv4 from start",
+			cidr: "10.92.114.30/24",
+			n:    3,
+			want: []string{"10.92.114.30", "10.92.114.31", "10.92.114.32"},
+		},
+		{
+			name:     "ipv4 skip registered",
+			cidr:     "10.92.114.30/24",
+			register: []string{"10.92.114.30", "10.92.114.32"},
+			n:        2,
+			want:     []string{"10.92.114.31", "10.92.114.33"},
+		},
+		{
+			name:    "ipv4 skip excluded across words",
+			cidr:    "10.92.114.30/24",
+			exclude: [][2]string{{"10.92.114.31", "10.92.114.130"}},
+			n:       2,
+			want:    []string{"10.92.114.30", "10.92.114.131"},
+		},
+		{
+			name:    "ipv4 stop before broadcast",
+			cidr:    "10.92.114.250/24",
+			exclude: [][2]string{{"10.92.114.251", "10.92.114.253"}},
+			n:       2,
+			want:    []string{"10.92.114.250", "10.92.114.254"},
+		},
+		{
+			name:    "ipv4 exhausted",
+			cidr:    "10.92.114.253/24",
+			n:       3,
+			want:    []string{"10.92.114.253", "10.92.114.254"},
+			wantErr: true,
+		},
+		{
+			name:    "ipv4 all excluded",
+			cidr:    "10.92.114.30/24",
+			exclude: [][2]string{{"10.92.114.30", "10.92.114.254"}},
+			n:       1,
+			wantErr: true,
+		},
+		{
+			name:    "ipv4 excluded network and broadcast",
+			cidr:    "10.92.114.0/30",
+			exclude: [][2]string{{"10.92.114.0", "10.92.114.1"}, {"10.92.114.3", "10.92.114.3"}},
+			n:       1,
+			want:    []string{"10.92.114.2"},
+		},
+		{
+			name: "ipv6 from start",
+			cidr: "fd00:dead:beef::100/64",
+			n:    2,
+			want: []string{"fd00:dead:beef::100", "fd00:dead:beef::101"},
+		},
+		{
+			name:     "ipv6 skip registered",
+			cidr:     "fd00:dead:beef::100/64",
+			register: []string{"fd00:dead:beef::100", "fd00:dead:beef::102"},
+			n:        2,
+			want:     []string{"fd00:dead:beef::101", "fd00:dead:beef::103"},
+		},
+		{
+			name:    "ipv6 skip large excluded range",
+			cidr:    "fd00:dead:beef::100/64",
+			exclude: [][2]string{{"fd00:dead:beef::100", "fd00:dead:beef:0:7fff:ffff:ffff:fffd"}},
+			n:       1,
+			want:    []string{"fd00:dead:beef:0:7fff:ffff:ffff:fffe"},
+		},
+		{
+			name: "ipv6 excluded up to managed end",
+			cidr: "fd00:dead:beef::100/64",
+			exclude: [][2]string{
+				{"fd00:dead:beef::100", "fd00:dead:beef::1ff"},
+				{"fd00:dead:beef::201", "fd00:dead:beef:0:7fff:ffff:ffff:ffff"},
+			},
+			n:    2,
+			want: []string{"fd00:dead:beef::200"},
+			// 第二次分配时没有可用的IP
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocator := newTestAllocator(t, tt.cidr)
+			for _, ip := range tt.register {
+				if err := allocator.RegisterIP(ip); err != nil {
+					t.Fatalf("RegisterIP(%q): %v", ip, err)
+				}
+			}
+			for _, r := range tt.exclude {
+				if err := allocator.AddExcludedIPRange(r[0], r[1]); err != nil {
+					t.Fatalf("AddExcludedIPRange(%q, %q): %v", r[0], r[1], err)
+				}
+			}
+
+			got := []string{}
+			var err error
+			for i := 0; i < tt.n; i++ {
+				var ip string
+				if ip, err = allocator.AllocateIP(); err != nil {
+					break
+				}
+				got = append(got, ip)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AllocateIP error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("AllocateIP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewind(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		release func(a *IPAllocator, ips []string) error
+		want    string
+	}{
+		{
+			name:    "ipv4 release",
+			cidr:    "10.92.114.30/24",
+			release: func(a *IPAllocator, ips []string) error { return a.ReleaseIP(ips[1]) },
+			want:    "10.92.114.31",
+		},
+		{
+			name: "ipv4 remove excluded",
+			cidr: "10.92.114.30/24",
+			release: func(a *IPAllocator, ips []string) error {
+				if err := a.AddExcludedIPRange("10.92.114.33", "10.92.114.40"); err != nil {
+					return err
+				}
+				return a.RemoveExcludedIP("10.92.114.35")
+			},
+			want: "10.92.114.35",
+		},
+		{
+			name:    "ipv6 release",
+			cidr:    "fd00:dead:beef::100/64",
+			release: func(a *IPAllocator, ips []string) error { return a.ReleaseIP(ips[0]) },
+			want:    "fd00:dead:beef::100",
+		},
+		{
+			// 起始IP之前的地址释放后不会被分配
+			name: "ipv4 release before start",
+			cidr: "10.92.114.30/24",
+			release: func(a *IPAllocator, ips []string) error {
+				if err := a.RegisterIP("10.92.114.5"); err != nil {
+					return err
+				}
+				return a.ReleaseIP("10.92.114.5")
+			},
+			want: "10.92.114.33",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocator := newTestAllocator(t, tt.cidr)
+			ips := allocateN(t, allocator, 3)
+			if err := tt.release(allocator, ips); err != nil {
+				t.Fatal(err)
+			}
+			ip, err := allocator.AllocateIP()
+			if err != nil || ip != tt.want {
+				t.Fatalf("AllocateIP = %q, %v, want %q", ip, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludedRanges(t *testing.T) {
+	allocator := newTestAllocator(t, "10.92.114.30/24")
+	for _, r := range [][2]string{
+		{"10.92.114.40", "10.92.114.50"},
+		{"10.92.114.60", "10.92.114.70"},
+		// 与两个区间重叠，合并为一个
+		{"10.92.114.45", "10.92.114.65"},
+		// 与前一个区间相邻
+		{"10.92.114.71", "10.92.114.71"},
+		{"10.92.114.100", "10.92.114.100"},
+	} {
+		if err := allocator.AddExcludedIPRange(r[0], r[1]); err != nil {
+			t.Fatalf("AddExcludedIPRange(%q, %q): %v", r[0], r[1], err)
+		}
+	}
+	want := rangeSet{{start: 40, end: 71}, {start: 100, end: 100}}
+	if !reflect.DeepEqual(allocator.excluded, want) {
+		t.Fatalf("excluded = %v, want %v", allocator.excluded, want)
+	}
+
+	if err := allocator.RemoveExcludedIP("10.92.114.50"); err != nil {
+		t.Fatal(err)
+	}
+	if err := allocator.RemoveExcludedIP("10.92.114.100"); err != nil {
+		t.Fatal(err)
+	}
+	if err := allocator.RemoveExcludedIP("10.92.114.100"); err == nil {
+		t.Fatal("RemoveExcludedIP succeeded for an IP not in the list")
+	}
+	want = rangeSet{{start: 40, end: 49}, {start: 51, end: 71}}
+	if !reflect.DeepEqual(allocator.excluded, want) {
+		t.Fatalf("excluded = %v, want %v", allocator.excluded, want)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "10.92.114.1", want: true},
+		{ip: "10.92.114.29", want: true},
+		{ip: "10.92.114.30", want: false},
+		{ip: "10.92.114.40", want: true},
+		{ip: "10.92.114.50", want: false},
+		{ip: "10.92.114.71", want: true},
+		{ip: "10.92.114.72", want: false},
+		{ip: "10.92.115.1", want: false},
+	}
+	for _, tt := range tests {
+		if got := allocator.IsExcluded(tt.ip); got != tt.want {
+			t.Errorf("IsExcluded(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestRangeAndOffsets(t *testing.T) {
+	allocator := newTestAllocator(t, "10.92.114.30/24")
+	tests := []struct {
+		start, end string
+		limit      int
+		want       []string
+		wantErr    bool
+	}{
+		{start: "10.92.114.1", end: "10.92.114.3", limit: 10, want: []string{"10.92.114.1", "10.92.114.2", "10.92.114.3"}},
+		// 网络地址和广播地址也在网段内
+		{start: "10.92.114.0", end: "10.92.114.0", limit: 1, want: []string{"10.92.114.0"}},
+		{start: "10.92.114.255", end: "10.92.114.255", limit: 1, want: []string{"10.92.114.255"}},
+		{start: "10.92.114.1", end: "10.92.114.10", limit: 5, wantErr: true},
+		{start: "10.92.114.3", end: "10.92.114.1", limit: 10, wantErr: true},
+		{start: "10.92.115.1", end: "10.92.115.2", limit: 10, wantErr: true},
+		{start: "fd00::1", end: "fd00::2", limit: 10, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := allocator.Range(tt.start, tt.end, tt.limit)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("Range(%q, %q, %d) = %v, %v, want %v", tt.start, tt.end, tt.limit, got, err, tt.want)
+		}
+	}
+
+	hosts := []struct {
+		ip                     string
+		host, inRange, contain bool
+	}{
+		{ip: "10.92.114.0", contain: true},
+		{ip: "10.92.114.1", host: true, contain: true},
+		{ip: "10.92.114.30", host: true, inRange: true, contain: true},
+		{ip: "10.92.114.254", host: true, inRange: true, contain: true},
+		{ip: "10.92.114.255", contain: true},
+		{ip: "10.92.115.1"},
+	}
+	for _, tt := range hosts {
+		if got := allocator.IsHost(tt.ip); got != tt.host {
+			t.Errorf("IsHost(%q) = %v, want %v", tt.ip, got, tt.host)
+		}
+		if got := allocator.InAllocRange(tt.ip); got != tt.inRange {
+			t.Errorf("InAllocRange(%q) = %v, want %v", tt.ip, got, tt.inRange)
+		}
+		if got := allocator.Contains(tt.ip); got != tt.contain {
+			t.Errorf("Contains(%q) = %v, want %v", tt.ip, got, tt.contain)
+		}
+	}
+	if allocator.HostCount() != 254 || allocator.PreStartCount() != 29 {
+		t.Errorf("HostCount() = %d, PreStartCount() = %d, want 254, 29", allocator.HostCount(), allocator.PreStartCount())
+	}
+
+	// 超出管理范围的IPv6地址不属于分配器
+	allocator6 := newTestAllocator(t, "fd00::100/64")
+	for ip, want := range map[string]bool{
+		"fd00::":                    true,
+		"fd00::8000:0:0:0":          true,
+		"fd00::8000:0:0:1":          false,
+		"fd00::ffff:ffff:ffff:ffff": false,
+		"10.92.114.30":              false,
+	} {
+		if got := allocator6.Contains(ip); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", ip, got, want)
+		}
+	}
+}
@@ -10,5 +10,6 @@ const (
 	ContainerStatusDead       = "dead"
 
 	// 自定义状态
-	CustomContainerStatusInit = "init" // 容器未初始化
+	CustomContainerStatusInit    = "init"    // 容器未初始化
+	CustomContainerStatusRemoved = "removed" // 容器已删除
 )
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"doo-store/backend/utils/cmd"
+	"fmt"
+	"os"
+	"time"
+)
+
+// VolumeHelperImage 用于导入导出卷数据的辅助镜像
+const VolumeHelperImage = "alpine:3.19"
+
+// ExportVolume 将命名卷中的数据导出为 tar.gz 文件
+func ExportVolume(volumeName, dst string) error {
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stdout, err := cmd.ExecWithIO(30*time.Minute, nil, file,
+		"docker", "run", "--rm", "-v", volumeName+":/volume:ro", VolumeHelperImage, "tar", "czf", "-", "-C", "/volume", ".",
+	)
+	if err != nil {
+		return fmt.Errorf("export volume %s failed: %s %w", volumeName, stdout, err)
+	}
+	return nil
+}
+
+// ImportVolume 清空命名卷后将 tar.gz 文件中的数据导入，卷不存在时会按照给定标签创建
+func ImportVolume(volumeName, src string, labels map[string]string) error {
+	if _, err := cmd.ExecWithCheck("docker", "volume", "inspect", volumeName); err != nil {
+		args := []string{"volume", "create"}
+		for key, value := range labels {
+			args = append(args, "--label", key+"="+value)
+		}
+		stdout, err := cmd.ExecWithCheck("docker", append(args, volumeName)...)
+		if err != nil {
+			return fmt.Errorf("create volume %s failed: %s %w", volumeName, stdout, err)
+		}
+	}
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// 先清空卷中原有的数据，避免与备份中的数据混在一起
+	stdout, err := cmd.ExecWithIO(30*time.Minute, file, nil,
+		"docker", "run", "--rm", "-i", "-v", volumeName+":/volume", VolumeHelperImage,
+		"sh", "-c", "find /volume -mindepth 1 -delete && tar xzf - -C /volume",
+	)
+	if err != nil {
+		return fmt.Errorf("import volume %s failed: %s %w", volumeName, stdout, err)
+	}
+	return nil
+}
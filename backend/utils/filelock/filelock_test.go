@@ -0,0 +1,36 @@
+package filelock
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestTryLock(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "backups", ".lock")
+	if IsLocked(file) {
+		t.Fatal("IsLocked returned true for a missing file")
+	}
+
+	unlock, err := TryLock(file)
+	if err != nil {
+		t.Fatalf("TryLock: %v", err)
+	}
+	if !IsLocked(file) {
+		t.Fatal("IsLocked returned false while the lock is held")
+	}
+	// 同一进程中再次加锁同样失败
+	if _, err := TryLock(file); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second TryLock error = %v, want ErrLocked", err)
+	}
+
+	unlock()
+	if IsLocked(file) {
+		t.Fatal("IsLocked returned true after unlock")
+	}
+	unlock, err = TryLock(file)
+	if err != nil {
+		t.Fatalf("TryLock after unlock: %v", err)
+	}
+	unlock()
+}
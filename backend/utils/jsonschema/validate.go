@@ -0,0 +1,233 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema JSON Schema 的精简实现，支持插件清单用到的关键字：
+// type、enum、required、properties、additionalProperties、items、minItems、
+// minLength、maxLength、pattern、minimum、maximum 以及指向 $defs 的 $ref
+type Schema struct {
+	root map[string]interface{}
+}
+
+// ValidationError 单个校验错误，Path 为 JSON Pointer 形式的位置
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile 解析 JSON Schema
+func Compile(data []byte) (*Schema, error) {
+	root := map[string]interface{}{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{root: root}, nil
+}
+
+// Validate 校验 JSON 文档，返回所有错误
+func (s *Schema) Validate(data []byte) ([]ValidationError, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return s.ValidateValue(value), nil
+}
+
+// ValidateValue 校验已解析的 JSON 值
+func (s *Schema) ValidateValue(value interface{}) []ValidationError {
+	var errs []ValidationError
+	s.validate(s.root, value, "", &errs)
+	return errs
+}
+
+func (s *Schema) validate(schema map[string]interface{}, value interface{}, path string, errs *[]ValidationError) {
+	addError := func(format string, args ...interface{}) {
+		p := path
+		if p == "" {
+			p = "/"
+		}
+		*errs = append(*errs, ValidationError{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			addError("%v", err)
+			return
+		}
+		s.validate(target, value, path, errs)
+		return
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		actual := typeOf(value)
+		matched := false
+		for _, t := range types {
+			if t == actual || (t == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addError("expected %s, got %s", strings.Join(types, " or "), actual)
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, item := range enum {
+			if fmt.Sprintf("%v", item) == fmt.Sprintf("%v", value) && typeOf(item) == typeOf(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			addError("value %v is not one of %v", value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(schema, v, path, errs, addError)
+	case []interface{}:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(v)) < minItems {
+			addError("expected at least %v items", minItems)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				s.validate(items, item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case string:
+		length := float64(len([]rune(v)))
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			addError("length must be at least %v", minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			addError("length must be at most %v", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				addError("invalid pattern %s in schema", pattern)
+			} else if !re.MatchString(v) {
+				addError("%q does not match pattern %s", v, pattern)
+			}
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			addError("must be >= %v", minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			addError("must be <= %v", maximum)
+		}
+	}
+}
+
+func (s *Schema) validateObject(schema map[string]interface{}, object map[string]interface{}, path string, errs *[]ValidationError, addError func(string, ...interface{})) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, exists := object[key.(string)]; !exists {
+				addError("missing required property %q", key)
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := path + "/" + escapePointer(key)
+		if propertySchema, ok := properties[key].(map[string]interface{}); ok {
+			s.validate(propertySchema, object[key], childPath, errs)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				addError("unknown property %q", key)
+			}
+		case map[string]interface{}:
+			s.validate(additional, object[key], childPath, errs)
+		}
+	}
+}
+
+// resolve 解析本文档内的 $ref，如 #/$defs/formField
+func (s *Schema) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %s", ref)
+	}
+	var current interface{} = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %s", ref)
+		}
+		current = object[unescapePointer(part)]
+	}
+	target, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %s", ref)
+	}
+	return target, nil
+}
+
+func schemaTypes(t interface{}) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				types = append(types, str)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
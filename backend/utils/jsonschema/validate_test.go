@@ -0,0 +1,105 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["name"],
+  "additionalProperties": false,
+  "properties": {
+    "name": { "$ref": "#/$defs/name" },
+    "kind": { "enum": ["a", "b", 1] },
+    "count": { "type": "integer", "minimum": 0, "maximum": 10 },
+    "ratio": { "type": "number" },
+    "tags": { "type": "array", "minItems": 1, "items": { "$ref": "#/$defs/name" } },
+    "labels": { "type": "object", "additionalProperties": { "type": "string" } },
+    "a~b/c": { "type": "boolean" },
+    "broken": { "$ref": "#/$defs/missing" }
+  },
+  "$defs": {
+    "name": { "type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$" }
+  }
+}`
+
+func validate(t *testing.T, doc string) []ValidationError {
+	t.Helper()
+	schema, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := schema.Validate([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return errs
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []string // 期望的错误位置，为空表示校验通过
+	}{
+		{"valid", `{"name": "abc", "kind": "a", "count": 3, "ratio": 0.5, "tags": ["ab"], "labels": {"x": "y"}}`, nil},
+		{"missing required", `{}`, []string{"/"}},
+		{"root type", `[]`, []string{"/"}},
+
+		{"ref min length", `{"name": "a"}`, []string{"/name"}},
+		{"ref max length", `{"name": "abcdef"}`, []string{"/name"}},
+		{"ref pattern", `{"name": "AB"}`, []string{"/name"}},
+		{"ref type", `{"name": 12}`, []string{"/name"}},
+		{"ref in items", `{"name": "abc", "tags": ["ab", "AB"]}`, []string{"/tags/1"}},
+		{"unresolvable ref", `{"name": "abc", "broken": 1}`, []string{"/broken"}},
+
+		{"enum string", `{"name": "abc", "kind": "b"}`, nil},
+		{"enum integer", `{"name": "abc", "kind": 1}`, nil},
+		{"enum integer as float", `{"name": "abc", "kind": 1.0}`, nil},
+		{"enum type mismatch", `{"name": "abc", "kind": "1"}`, []string{"/kind"}},
+		{"enum not listed", `{"name": "abc", "kind": "c"}`, []string{"/kind"}},
+
+		{"integer", `{"name": "abc", "count": 10}`, nil},
+		{"integer written as float", `{"name": "abc", "count": 2.0}`, nil},
+		{"integer rejects fraction", `{"name": "abc", "count": 2.5}`, []string{"/count"}},
+		{"integer minimum", `{"name": "abc", "count": -1}`, []string{"/count"}},
+		{"integer maximum", `{"name": "abc", "count": 11}`, []string{"/count"}},
+		{"number accepts integer", `{"name": "abc", "ratio": 2}`, nil},
+		{"number rejects string", `{"name": "abc", "ratio": "2"}`, []string{"/ratio"}},
+
+		{"additional properties false", `{"name": "abc", "extra": 1}`, []string{"/"}},
+		{"additional properties schema", `{"name": "abc", "labels": {"x": 1}}`, []string{"/labels/x"}},
+		{"min items", `{"name": "abc", "tags": []}`, []string{"/tags"}},
+		{"escaped pointer", `{"name": "abc", "a~b/c": "x"}`, []string{"/a~0b~1c"}},
+		{"multiple errors", `{"name": "a", "count": "x", "extra": true}`, []string{"/count", "/", "/name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validate(t, tt.doc)
+			got := make([]string, 0, len(errs))
+			for _, err := range errs {
+				got = append(got, err.Path)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("errors = %v, want paths %v", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileInvalidSchema(t *testing.T) {
+	if _, err := Compile([]byte(`{`)); err == nil {
+		t.Fatal("Compile accepted invalid JSON")
+	}
+}
+
+func TestValidateInvalidDocument(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := schema.Validate([]byte(`{"name":`)); err == nil {
+		t.Fatal("Validate accepted invalid JSON")
+	}
+}
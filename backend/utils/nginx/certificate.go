@@ -0,0 +1,156 @@
+package nginx
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Nginx容器中证书与 HTTP-01 验证文件所在目录
+const (
+	containerCertsDir = "/etc/nginx/ssl/plugins"
+	containerACMEDir  = "/etc/nginx/acme"
+)
+
+// ACMEChallengePath HTTP-01 验证文件的访问路径前缀
+const ACMEChallengePath = "/.well-known/acme-challenge/"
+
+// CertificatePaths 返回证书与私钥在Nginx容器中的路径，通配符证书的 * 替换为 _wildcard
+func CertificatePaths(domain string) (string, string) {
+	name := strings.ReplaceAll(domain, "*", "_wildcard")
+	return fmt.Sprintf("%s/%s.crt", containerCertsDir, name), fmt.Sprintf("%s/%s.key", containerCertsDir, name)
+}
+
+// certificateFile 写入Nginx容器的证书或私钥文件，记录原来的内容用于回滚
+type certificateFile struct {
+	path     string
+	mode     string
+	content  []byte
+	previous []byte
+	existed  bool
+}
+
+// InstallCertificate 将证书与私钥写入Nginx容器，reload 为 true 时检测并重新加载Nginx，使续期后的证书生效
+// 写入或重新加载失败时恢复原来的证书与私钥，避免容器中留下不匹配的证书与私钥
+func (nm *NginxManager) InstallCertificate(domain string, cert, key []byte, reload bool) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	log.Infof("Installing certificate for: %s", domain)
+
+	certPath, keyPath := CertificatePaths(domain)
+	files := []*certificateFile{
+		{path: certPath, mode: "644", content: cert},
+		{path: keyPath, mode: "600", content: key},
+	}
+	for _, file := range files {
+		exists, err := nm.dockerClient.FileExistsInContainer(nm.containerID, file.path)
+		if err != nil {
+			return fmt.Errorf("failed to check certificate in container: %w", err)
+		}
+		if !exists {
+			continue
+		}
+		if file.previous, err = nm.dockerClient.ReadFileFromContainer(nm.containerID, file.path); err != nil {
+			return fmt.Errorf("failed to read certificate from container: %w", err)
+		}
+		file.existed = true
+	}
+
+	for i, file := range files {
+		if err := nm.copyContentToContainer(file.content, file.path, file.mode); err != nil {
+			log.Errorf("Failed to copy %s to container: %v", file.path, err)
+			nm.revertCertificate(files[:i+1])
+			return fmt.Errorf("failed to copy %s to container: %w", file.path, err)
+		}
+	}
+	if !reload {
+		return nil
+	}
+	if err := nm.testAndReload(); err != nil {
+		log.Errorf("Failed to reload nginx after installing certificate: %v", err)
+		nm.revertCertificate(files)
+		return fmt.Errorf("failed to test and reload nginx: %w", err)
+	}
+	return nil
+}
+
+// revertCertificate 将证书与私钥恢复为写入前的内容，写入前不存在的文件删除
+func (nm *NginxManager) revertCertificate(files []*certificateFile) {
+	for _, file := range files {
+		var err error
+		if file.existed {
+			err = nm.copyContentToContainer(file.previous, file.path, file.mode)
+		} else {
+			err = nm.dockerClient.RemoveFileFormContainer(nm.containerID, file.path)
+		}
+		if err != nil {
+			log.Warnf("Failed to restore %s during revert: %v", file.path, err)
+		}
+	}
+}
+
+// HasCertificate 判断Nginx容器中是否已存在证书与私钥，容器重建后需要重新写入
+func (nm *NginxManager) HasCertificate(domain string) (bool, error) {
+	certPath, keyPath := CertificatePaths(domain)
+	for _, file := range []string{certPath, keyPath} {
+		exists, err := nm.dockerClient.FileExistsInContainer(nm.containerID, file)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// RemoveCertificate 删除Nginx容器中的证书与私钥，调用前需确保已没有配置引用该证书
+func (nm *NginxManager) RemoveCertificate(domain string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	log.Infof("Removing certificate for: %s", domain)
+
+	certPath, keyPath := CertificatePaths(domain)
+	for _, file := range []string{certPath, keyPath} {
+		if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, file); err != nil {
+			log.Errorf("Failed to remove certificate from container: %v", err)
+			return fmt.Errorf("failed to remove certificate from container: %w", err)
+		}
+	}
+	return nil
+}
+
+// PutChallenge 写入 HTTP-01 验证文件，由独立域名 server 块中的 ACMEChallenge location 提供访问
+func (nm *NginxManager) PutChallenge(token, keyAuth string) error {
+	return nm.copyContentToContainer([]byte(keyAuth), containerACMEDir+ACMEChallengePath+token, "644")
+}
+
+// RemoveChallenge 删除 HTTP-01 验证文件
+func (nm *NginxManager) RemoveChallenge(token string) error {
+	return nm.dockerClient.RemoveFileFormContainer(nm.containerID, containerACMEDir+ACMEChallengePath+token)
+}
+
+// copyContentToContainer 将内容写入Nginx容器中的文件，目录不存在时自动创建
+func (nm *NginxManager) copyContentToContainer(content []byte, containerPath, mode string) error {
+	tmp, err := os.CreateTemp("", "nginx-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(content)
+	_ = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if _, _, err := nm.dockerClient.ExecInContainer(nm.containerID, []string{"mkdir", "-p", path.Dir(containerPath)}); err != nil {
+		return fmt.Errorf("failed to create directory in container: %w", err)
+	}
+	if err := nm.dockerClient.CopyFileToContainer(nm.containerID, tmp.Name(), containerPath); err != nil {
+		return err
+	}
+	if _, _, err := nm.dockerClient.ExecInContainer(nm.containerID, []string{"chmod", mode, containerPath}); err != nil {
+		return fmt.Errorf("failed to change file mode in container: %w", err)
+	}
+	return nil
+}
@@ -1,8 +1,46 @@
 package nginx
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"doo-store/backend/config"
+	"doo-store/backend/constant"
+)
+
+// location 配置的挂载方式
+const (
+	LocationModePath = "path" // 以 /plugin/<key>/ 路径挂载在DooTask的域名下
+	LocationModeHost = "host" // 使用独立域名，模板为完整的 server 块
+)
+
+// ErrServerNameRequired 使用独立域名时未设置域名
+var ErrServerNameRequired = errors.New("server name is required for host mode")
+
+// serverBlockRegexp 匹配顶层的 server 块
+var serverBlockRegexp = regexp.MustCompile(`(?m)^\s*server\s*\{`)
+
+// DetectMode 根据模板内容判断挂载方式，包含 server 块的模板使用独立域名
+func DetectMode(tmpl string) string {
+	if serverBlockRegexp.MatchString(tmpl) {
+		return LocationModeHost
+	}
+	return LocationModePath
+}
+
 type LocationConfig struct {
 	// 模板内容
 	Template string
+	// 挂载方式
+	Mode string
+	// 独立域名，Mode 为 host 时使用
+	ServerName string
+	// 证书对应的域名，为空时不启用HTTPS
+	Certificate string
+	// 访问控制与限流配置
+	Options *LocationOptions
 	// location名称, Key
 	Name string
 	// 代理服务器名称
@@ -17,14 +55,34 @@ type LocationConfig struct {
 func NewLocationConfig(name, proxyServer string) *LocationConfig {
 	return &LocationConfig{
 		Name:            name,
+		Mode:            LocationModePath,
 		ProxyServerName: proxyServer,
 		CustomOptions:   make(map[string]string),
 	}
 }
 
-// WithTemplate 设置模板
+// WithTemplate 设置模板，并根据模板内容设置挂载方式
 func (lc *LocationConfig) WithTemplate(tmpl string) *LocationConfig {
 	lc.Template = tmpl
+	lc.Mode = DetectMode(tmpl)
+	return lc
+}
+
+// WithServerName 设置独立域名
+func (lc *LocationConfig) WithServerName(serverName string) *LocationConfig {
+	lc.ServerName = serverName
+	return lc
+}
+
+// WithCertificate 设置使用的证书，domain 为证书对应的域名
+func (lc *LocationConfig) WithCertificate(domain string) *LocationConfig {
+	lc.Certificate = domain
+	return lc
+}
+
+// WithOptions 设置访问控制与限流配置
+func (lc *LocationConfig) WithOptions(options *LocationOptions) *LocationConfig {
+	lc.Options = options
 	return lc
 }
 
@@ -34,6 +92,79 @@ func (lc *LocationConfig) WithPort(port int) *LocationConfig {
 	return lc
 }
 
+// TemplateData 渲染 location 模板时可用的变量
+// AuthLocation 与 AuthRequest 用于开启登录校验：AuthLocation 放在 location 之外，AuthRequest 放在需要校验的 location 中
+// TLS 与 ACMEChallenge 用于独立域名的 server 块：TLS 在有证书时启用HTTPS，ACMEChallenge 用于申请证书时的 HTTP-01 验证
+// Options 为访问控制与限流指令，模板中未使用时自动插入到转发请求的 location 中
+func (lc *LocationConfig) TemplateData() map[string]interface{} {
+	return map[string]interface{}{
+		"Key":           lc.Name,
+		"ContainerName": lc.ProxyServerName,
+		"Port":          lc.Port,
+		"ServerName":    lc.ServerName,
+		"AuthLocation":  lc.AuthLocation(),
+		"AuthRequest":   lc.AuthRequest(),
+		"TLS":           lc.TLS(),
+		"ACMEChallenge": lc.ACMEChallenge(),
+		"Options":       lc.Options.Directives(lc.Name),
+	}
+}
+
+// TLS 生成启用HTTPS的指令，未设置证书时为空
+func (lc *LocationConfig) TLS() string {
+	if lc.Certificate == "" {
+		return ""
+	}
+	certPath, keyPath := CertificatePaths(lc.Certificate)
+	return fmt.Sprintf(`listen 443 ssl;
+	ssl_certificate %s;
+	ssl_certificate_key %s;`, certPath, keyPath)
+}
+
+// ACMEChallenge 生成提供 HTTP-01 验证文件的 location
+func (lc *LocationConfig) ACMEChallenge() string {
+	return fmt.Sprintf(`location ^~ %s {
+	root %s;
+	default_type text/plain;
+}`, ACMEChallengePath, containerACMEDir)
+}
+
+// authPath 登录校验子请求的内部路径
+func (lc *LocationConfig) authPath() string {
+	return fmt.Sprintf("/plugin-auth/%s", lc.Name)
+}
+
+// AuthLocation 生成登录校验使用的内部 location，将子请求转发到商店的校验接口
+func (lc *LocationConfig) AuthLocation() string {
+	return fmt.Sprintf(`location = %s {
+	internal;
+	proxy_pass %s/api/v1/public/auth-check;
+	proxy_pass_request_body off;
+	proxy_set_header Content-Length "";
+	proxy_set_header X-Original-URI $request_uri;
+}`, lc.authPath(), config.EnvConfig.GetStoreURL())
+}
+
+// AuthRequest 生成开启登录校验的指令，校验通过后将用户信息通过请求头传递给插件
+func (lc *LocationConfig) AuthRequest() string {
+	return fmt.Sprintf(`auth_request %s;
+	auth_request_set $dootask_userid $upstream_http_%s;
+	auth_request_set $dootask_identity $upstream_http_%s;
+	proxy_set_header %s $dootask_userid;
+	proxy_set_header %s $dootask_identity;`,
+		lc.authPath(),
+		upstreamHeaderVar(constant.AuthHeaderUserID),
+		upstreamHeaderVar(constant.AuthHeaderIdentity),
+		constant.AuthHeaderUserID,
+		constant.AuthHeaderIdentity,
+	)
+}
+
+// upstreamHeaderVar 将响应头名称转换为 Nginx 变量名中使用的格式
+func upstreamHeaderVar(header string) string {
+	return strings.ReplaceAll(strings.ToLower(header), "-", "_")
+}
+
 // WithCustomOption 添加自定义配置项
 func (lc *LocationConfig) WithCustomOption(key, value string) *LocationConfig {
 	lc.CustomOptions[key] = value
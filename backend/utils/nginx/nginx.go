@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"strings"
 	"text/template"
 
 	"doo-store/backend/config"
@@ -65,6 +66,8 @@ func NewNginxManager() (*NginxManager, error) {
 // It handles the entire process including file generation, container updates,
 // and configuration testing
 func (nm *NginxManager) AddLocation(locationConfig *LocationConfig) error {
+	configMu.Lock()
+	defer configMu.Unlock()
 	log.Infof("Adding new location block for: %s", locationConfig.Name)
 	locationPath := nm.getLocationPath(locationConfig.Name)
 
@@ -82,25 +85,45 @@ func (nm *NginxManager) AddLocation(locationConfig *LocationConfig) error {
 	}
 	log.Debugf("Successfully wrote configuration to: %s", locationPath)
 
-	// Handle default configuration backup
-	if err := nm.handleDefaultConfig(locationConfig.Name); err != nil {
-		log.Errorf("Failed to handle default config: %v", err)
-		return fmt.Errorf("failed to handle default config: %w", err)
+	// Handle default configuration backup, only path mode locations replace the default one
+	if locationConfig.Mode != LocationModeHost {
+		if err := nm.handleDefaultConfig(locationConfig.Name); err != nil {
+			log.Errorf("Failed to handle default config: %v", err)
+			return fmt.Errorf("failed to handle default config: %w", err)
+		}
+	}
+
+	// Write the rate limit zones and basic auth users the configuration depends on
+	if err := nm.writeAuxiliaryFiles(locationConfig); err != nil {
+		log.Errorf("Failed to write auxiliary files: %v", err)
+		nm.rollbackChanges(locationConfig)
+		return fmt.Errorf("failed to write auxiliary files: %w", err)
 	}
 
 	// Copy configuration to container
-	containerPath := fmt.Sprintf("/etc/nginx/conf.d/apps/%s.conf", locationConfig.Name)
+	containerPath := nm.getContainerPath(locationConfig.Name, locationConfig.Mode)
 	if err := nm.dockerClient.CopyFileToContainer(nm.containerID, locationPath, containerPath); err != nil {
 		log.Errorf("Failed to copy config to container: %v", err)
-		nm.rollbackChanges(locationConfig.Name)
+		nm.rollbackChanges(locationConfig)
 		return fmt.Errorf("failed to copy config to container: %w", err)
 	}
 	log.Debugf("Successfully copied configuration to container path: %s", containerPath)
 
+	// Remove the configuration of the other mode when the mode has changed
+	otherPath := nm.getContainerPath(locationConfig.Name, otherMode(locationConfig.Mode))
+	if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, otherPath); err != nil {
+		log.Warnf("Failed to remove config of the other mode: %v", err)
+	}
+	if locationConfig.Mode == LocationModeHost {
+		if err := nm.restoreDefaultConfig(locationConfig.Name); err != nil {
+			log.Warnf("Failed to restore default config: %v", err)
+		}
+	}
+
 	// Test and reload configuration
 	if err := nm.testAndReload(); err != nil {
 		log.Errorf("Failed to test and reload nginx: %v", err)
-		nm.rollbackChanges(locationConfig.Name)
+		nm.rollbackChanges(locationConfig)
 		return fmt.Errorf("failed to test and reload nginx: %w", err)
 	}
 
@@ -111,13 +134,16 @@ func (nm *NginxManager) AddLocation(locationConfig *LocationConfig) error {
 // RemoveLocation removes a location block from Nginx configuration
 // It handles cleanup of both container and local files
 func (nm *NginxManager) RemoveLocation(locationName string) error {
+	configMu.Lock()
+	defer configMu.Unlock()
 	log.Infof("Removing location block for: %s", locationName)
 
-	// Remove configuration file from container
-	containerPath := fmt.Sprintf("/etc/nginx/conf.d/apps/%s.conf", locationName)
-	if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, containerPath); err != nil {
-		log.Errorf("Failed to remove config from container: %v", err)
-		return fmt.Errorf("failed to remove config from container: %w", err)
+	// Remove configuration file of both modes and the files it depends on from container
+	for _, containerPath := range append(nm.containerPaths(locationName), auxiliaryPaths(locationName)...) {
+		if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, containerPath); err != nil {
+			log.Errorf("Failed to remove config from container: %v", err)
+			return fmt.Errorf("failed to remove config from container: %w", err)
+		}
 	}
 
 	// Restore default configuration if exists
@@ -186,14 +212,18 @@ func (nm *NginxManager) restoreDefaultConfig(locationName string) error {
 
 // rollbackChanges rolls back any changes made during the configuration process
 // It attempts to restore the system to its previous state in case of failure
-func (nm *NginxManager) rollbackChanges(locationName string) error {
+func (nm *NginxManager) rollbackChanges(locationConfig *LocationConfig) error {
+	locationName := locationConfig.Name
 	log.Infof("Rolling back changes for: %s", locationName)
-	containerPath := fmt.Sprintf("/etc/nginx/conf.d/apps/%s.conf", locationName)
+	containerPath := nm.getContainerPath(locationName, locationConfig.Mode)
 
 	// Remove the new configuration file if it exists
 	if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, containerPath); err != nil {
 		log.Warnf("Failed to remove new config during rollback: %v", err)
 	}
+	if err := nm.removeAuxiliaryFiles(locationName); err != nil {
+		log.Warnf("Failed to remove auxiliary files during rollback: %v", err)
+	}
 
 	// Restore the default configuration if it was backed up
 	if err := nm.restoreDefaultConfig(locationName); err != nil {
@@ -232,8 +262,11 @@ func (nm *NginxManager) ExtractLocations(nginxConfig string) []string {
 // It either uses a custom template or generates a default one
 func (nm *NginxManager) generateLocationContent(locationConfig *LocationConfig) (string, error) {
 	log.Debugf("Generating location content for: %s", locationConfig.Name)
+	if locationConfig.Mode == LocationModeHost && locationConfig.ServerName == "" {
+		return "", ErrServerNameRequired
+	}
 	if locationConfig.Template == "" {
-		return nm.generateDefaultTemplate(locationConfig), nil
+		return injectDirectives(nm.generateDefaultTemplate(locationConfig), locationConfig.Options.Directives(locationConfig.Name)), nil
 	}
 
 	t, err := template.New("nginx").Parse(locationConfig.Template)
@@ -243,17 +276,15 @@ func (nm *NginxManager) generateLocationContent(locationConfig *LocationConfig)
 	}
 
 	var buf bytes.Buffer
-	data := map[string]interface{}{
-		"Key":           locationConfig.Name,
-		"ContainerName": locationConfig.ProxyServerName,
-		"Port":          locationConfig.Port,
-	}
-
-	if err := t.Execute(&buf, data); err != nil {
+	if err := t.Execute(&buf, locationConfig.TemplateData()); err != nil {
 		log.Errorf("Failed to execute template: %v", err)
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
+	// Insert the access control directives when the template does not place them itself
+	if !strings.Contains(locationConfig.Template, ".Options") {
+		return injectDirectives(buf.String(), locationConfig.Options.Directives(locationConfig.Name)), nil
+	}
 	return buf.String(), nil
 }
 
@@ -0,0 +1,253 @@
+package nginx
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 访问控制配置的取值上限
+const (
+	maxRateLimit  = 10000
+	maxRateBurst  = 100000
+	maxConnLimit  = 100000
+	maxListLength = 100
+)
+
+var (
+	// bodySizeRegexp 匹配 client_max_body_size 的取值，例如 10m
+	bodySizeRegexp = regexp.MustCompile(`^[0-9]{1,6}[kKmMgG]?$`)
+	// usernameRegexp 匹配 Basic Auth 的用户名，不能包含冒号
+	usernameRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+	// headerNameRegexp 匹配响应头名称
+	headerNameRegexp = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+)
+
+// BasicAuthUser Basic Auth 用户，保存时只保留密码的哈希值
+type BasicAuthUser struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"` // 明文密码，只在修改时提交
+	Hash     string `json:"hash,omitempty"`     // {SSHA} 格式的密码哈希
+}
+
+// LocationOptions 插件 location 的访问控制与限流配置，所有取值经过校验后渲染为Nginx指令
+type LocationOptions struct {
+	Allow       []string          `json:"allow"`         // 允许访问的IP或CIDR，设置后其他地址禁止访问
+	Deny        []string          `json:"deny"`          // 禁止访问的IP或CIDR
+	RateLimit   int               `json:"rate_limit"`    // 每个IP每秒的请求数，0 表示不限制
+	RateBurst   int               `json:"rate_burst"`    // 超出请求速率时允许的突发请求数
+	ConnLimit   int               `json:"conn_limit"`    // 每个IP的并发连接数，0 表示不限制
+	MaxBodySize string            `json:"max_body_size"` // 请求体大小上限，例如 10m
+	BasicAuth   []BasicAuthUser   `json:"basic_auth"`    // Basic Auth 用户，为空时不开启
+	Headers     map[string]string `json:"headers"`       // 额外添加的响应头
+}
+
+// Validate 校验配置，避免将任意内容写入Nginx配置
+func (o *LocationOptions) Validate() error {
+	if len(o.Allow) > maxListLength || len(o.Deny) > maxListLength || len(o.BasicAuth) > maxListLength || len(o.Headers) > maxListLength {
+		return fmt.Errorf("too many entries, at most %d are allowed", maxListLength)
+	}
+	for _, list := range [][]string{o.Allow, o.Deny} {
+		for _, address := range list {
+			if net.ParseIP(address) == nil {
+				if _, _, err := net.ParseCIDR(address); err != nil {
+					return fmt.Errorf("invalid IP or CIDR: %s", address)
+				}
+			}
+		}
+	}
+	if o.RateLimit < 0 || o.RateLimit > maxRateLimit {
+		return fmt.Errorf("rate_limit must be between 0 and %d", maxRateLimit)
+	}
+	if o.RateBurst < 0 || o.RateBurst > maxRateBurst {
+		return fmt.Errorf("rate_burst must be between 0 and %d", maxRateBurst)
+	}
+	if o.ConnLimit < 0 || o.ConnLimit > maxConnLimit {
+		return fmt.Errorf("conn_limit must be between 0 and %d", maxConnLimit)
+	}
+	if o.MaxBodySize != "" && !bodySizeRegexp.MatchString(o.MaxBodySize) {
+		return fmt.Errorf("invalid max_body_size: %s", o.MaxBodySize)
+	}
+	usernames := map[string]bool{}
+	for _, user := range o.BasicAuth {
+		if !usernameRegexp.MatchString(user.Username) {
+			return fmt.Errorf("invalid basic auth username: %s", user.Username)
+		}
+		if usernames[user.Username] {
+			return fmt.Errorf("duplicate basic auth username: %s", user.Username)
+		}
+		usernames[user.Username] = true
+		if user.Hash == "" && user.Password == "" {
+			return fmt.Errorf("password is required for basic auth user: %s", user.Username)
+		}
+		if strings.ContainsAny(user.Hash, ":\r\n") {
+			return fmt.Errorf("invalid password hash for basic auth user: %s", user.Username)
+		}
+	}
+	for name, value := range o.Headers {
+		if !headerNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid header name: %s", name)
+		}
+		if len(value) > 1024 || strings.ContainsAny(value, "\"\\${};") || strings.IndexFunc(value, isControl) >= 0 {
+			return fmt.Errorf("invalid value for header %s", name)
+		}
+	}
+	return nil
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// Empty 是否没有任何配置
+func (o *LocationOptions) Empty() bool {
+	return o == nil || (len(o.Allow) == 0 && len(o.Deny) == 0 && o.RateLimit == 0 && o.ConnLimit == 0 &&
+		o.MaxBodySize == "" && len(o.BasicAuth) == 0 && len(o.Headers) == 0)
+}
+
+// Directives 生成放在 location 中的指令，key 为插件Key
+func (o *LocationOptions) Directives(key string) string {
+	if o.Empty() {
+		return ""
+	}
+	var lines []string
+	for _, address := range o.Deny {
+		lines = append(lines, fmt.Sprintf("deny %s;", address))
+	}
+	for _, address := range o.Allow {
+		lines = append(lines, fmt.Sprintf("allow %s;", address))
+	}
+	if len(o.Allow) > 0 {
+		lines = append(lines, "deny all;")
+	}
+	if o.RateLimit > 0 {
+		line := fmt.Sprintf("limit_req zone=%s", zoneName(key, "req"))
+		if o.RateBurst > 0 {
+			line += fmt.Sprintf(" burst=%d nodelay", o.RateBurst)
+		}
+		lines = append(lines, line+";")
+	}
+	if o.ConnLimit > 0 {
+		lines = append(lines, fmt.Sprintf("limit_conn %s %d;", zoneName(key, "conn"), o.ConnLimit))
+	}
+	if o.MaxBodySize != "" {
+		lines = append(lines, fmt.Sprintf("client_max_body_size %s;", o.MaxBodySize))
+	}
+	if len(o.BasicAuth) > 0 {
+		lines = append(lines, fmt.Sprintf("auth_basic \"%s\";", key))
+		lines = append(lines, fmt.Sprintf("auth_basic_user_file %s;", htpasswdPath(key)))
+	}
+	names := make([]string, 0, len(o.Headers))
+	for name := range o.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("add_header %s \"%s\" always;", name, o.Headers[name]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Zones 生成限流使用的共享内存区域，需要放在 http 块中
+func (o *LocationOptions) Zones(key string) string {
+	if o == nil {
+		return ""
+	}
+	var lines []string
+	if o.RateLimit > 0 {
+		lines = append(lines, fmt.Sprintf("limit_req_zone $binary_remote_addr zone=%s:10m rate=%dr/s;", zoneName(key, "req"), o.RateLimit))
+	}
+	if o.ConnLimit > 0 {
+		lines = append(lines, fmt.Sprintf("limit_conn_zone $binary_remote_addr zone=%s:10m;", zoneName(key, "conn")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Htpasswd 生成 Basic Auth 的密码文件
+func (o *LocationOptions) Htpasswd() string {
+	if o == nil || len(o.BasicAuth) == 0 {
+		return ""
+	}
+	var builder strings.Builder
+	for _, user := range o.BasicAuth {
+		builder.WriteString(fmt.Sprintf("%s:%s\n", user.Username, user.Hash))
+	}
+	return builder.String()
+}
+
+// HashPassword 生成Nginx支持的 {SSHA} 格式的密码哈希
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, 8)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(append([]byte(password), salt...))
+	return "{SSHA}" + base64.StdEncoding.EncodeToString(append(sum[:], salt...)), nil
+}
+
+// zoneName 限流区域的名称
+func zoneName(key, kind string) string {
+	return fmt.Sprintf("plugin_%s_%s", key, kind)
+}
+
+// locationOpenRegexp 匹配单独占一行的 location 块开始
+var locationOpenRegexp = regexp.MustCompile(`^(\s*)location\b[^{]*\{\s*$`)
+
+// injectDirectives 将指令插入到所有转发请求的 location 块开头，跳过 internal 的 location
+// 嵌套的 location 分别判断，外层块只根据不属于嵌套 location 的内容判断，嵌套 location 之外的请求同样受到限制
+func injectDirectives(content, directives string) string {
+	if directives == "" {
+		return content
+	}
+	type block struct {
+		line     int
+		depth    int
+		indent   string
+		proxy    bool
+		internal bool
+	}
+	lines := strings.Split(content, "\n")
+	var stack []*block
+	inserts := map[int]string{}
+	depth := 0
+	for i, line := range lines {
+		if match := locationOpenRegexp.FindStringSubmatch(line); match != nil {
+			stack = append(stack, &block{line: i, depth: depth, indent: match[1]})
+		} else if len(stack) > 0 {
+			// 内容属于最内层的 location
+			b := stack[len(stack)-1]
+			b.proxy = b.proxy || strings.Contains(line, "proxy_pass")
+			b.internal = b.internal || strings.Contains(line, "internal;")
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		for len(stack) > 0 && depth <= stack[len(stack)-1].depth {
+			b := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if b.proxy && !b.internal {
+				inserts[b.line] = b.indent
+			}
+		}
+	}
+
+	var builder strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(line)
+		if indent, ok := inserts[i]; ok {
+			for _, directive := range strings.Split(directives, "\n") {
+				builder.WriteString("\n" + indent + "\t" + directive)
+			}
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,265 @@
+package nginx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocationOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options LocationOptions
+		wantErr bool
+	}{
+		{name: "empty", options: LocationOptions{}},
+		{name: "ipv4", options: LocationOptions{Allow: []string{"192.168.1.10"}}},
+		{name: "ipv6", options: LocationOptions{Deny: []string{"fd00::1"}}},
+		{name: "ipv4 cidr", options: LocationOptions{Allow: []string{"10.0.0.0/8"}}},
+		{name: "ipv6 cidr", options: LocationOptions{Deny: []string{"fd00::/64"}}},
+		{name: "invalid ip", options: LocationOptions{Allow: []string{"192.168.1.256"}}, wantErr: true},
+		{name: "invalid cidr prefix", options: LocationOptions{Allow: []string{"10.0.0.0/33"}}, wantErr: true},
+		{name: "ip with directive", options: LocationOptions{Deny: []string{"10.0.0.1; allow all"}}, wantErr: true},
+		{name: "all keyword", options: LocationOptions{Allow: []string{"all"}}, wantErr: true},
+		{name: "empty address", options: LocationOptions{Allow: []string{""}}, wantErr: true},
+
+		{name: "body size without unit", options: LocationOptions{MaxBodySize: "1024"}},
+		{name: "body size k", options: LocationOptions{MaxBodySize: "512k"}},
+		{name: "body size M", options: LocationOptions{MaxBodySize: "10M"}},
+		{name: "body size g", options: LocationOptions{MaxBodySize: "1g"}},
+		{name: "body size unknown unit", options: LocationOptions{MaxBodySize: "10t"}, wantErr: true},
+		{name: "body size two units", options: LocationOptions{MaxBodySize: "10mb"}, wantErr: true},
+		{name: "body size negative", options: LocationOptions{MaxBodySize: "-1m"}, wantErr: true},
+		{name: "body size decimal", options: LocationOptions{MaxBodySize: "1.5m"}, wantErr: true},
+		{name: "body size too long", options: LocationOptions{MaxBodySize: "1234567m"}, wantErr: true},
+		{name: "body size with directive", options: LocationOptions{MaxBodySize: "10m; deny all"}, wantErr: true},
+
+		{name: "rate limit", options: LocationOptions{RateLimit: maxRateLimit, RateBurst: maxRateBurst, ConnLimit: maxConnLimit}},
+		{name: "rate limit negative", options: LocationOptions{RateLimit: -1}, wantErr: true},
+		{name: "rate limit too large", options: LocationOptions{RateLimit: maxRateLimit + 1}, wantErr: true},
+		{name: "burst too large", options: LocationOptions{RateBurst: maxRateBurst + 1}, wantErr: true},
+		{name: "conn limit negative", options: LocationOptions{ConnLimit: -1}, wantErr: true},
+
+		{name: "basic auth password", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Password: "secret"}}}},
+		{name: "basic auth hash", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "a.b-c_d", Hash: "{SSHA}abc="}}}},
+		{name: "username with colon", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "ad:min", Password: "secret"}}}, wantErr: true},
+		{name: "username with newline", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin\nroot", Password: "secret"}}}, wantErr: true},
+		{name: "username with space", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "ad min", Password: "secret"}}}, wantErr: true},
+		{name: "empty username", options: LocationOptions{BasicAuth: []BasicAuthUser{{Password: "secret"}}}, wantErr: true},
+		{name: "hash with colon", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Hash: "{SSHA}a:b"}}}, wantErr: true},
+		{name: "hash with newline", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Hash: "{SSHA}a\nroot:b"}}}, wantErr: true},
+		{name: "hash with carriage return", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Hash: "{SSHA}a\rb"}}}, wantErr: true},
+		{name: "missing password", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin"}}}, wantErr: true},
+		{name: "duplicate username", options: LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Password: "a"}, {Username: "admin", Password: "b"}}}, wantErr: true},
+
+		{name: "header", options: LocationOptions{Headers: map[string]string{"X-Frame-Options": "SAMEORIGIN"}}},
+		{name: "header with spaces and quotes", options: LocationOptions{Headers: map[string]string{"Content-Security-Policy": "default-src 'self'"}}},
+		{name: "header name with space", options: LocationOptions{Headers: map[string]string{"X Frame": "a"}}, wantErr: true},
+		{name: "header name with colon", options: LocationOptions{Headers: map[string]string{"X-Frame:": "a"}}, wantErr: true},
+		{name: "header value double quote", options: LocationOptions{Headers: map[string]string{"X-Test": `a" always; deny all; #`}}, wantErr: true},
+		{name: "header value semicolon", options: LocationOptions{Headers: map[string]string{"X-Test": "a; deny all"}}, wantErr: true},
+		{name: "header value variable", options: LocationOptions{Headers: map[string]string{"X-Test": "$remote_addr"}}, wantErr: true},
+		{name: "header value open brace", options: LocationOptions{Headers: map[string]string{"X-Test": "a {"}}, wantErr: true},
+		{name: "header value close brace", options: LocationOptions{Headers: map[string]string{"X-Test": "}"}}, wantErr: true},
+		{name: "header value backslash", options: LocationOptions{Headers: map[string]string{"X-Test": `a\`}}, wantErr: true},
+		{name: "header value newline", options: LocationOptions{Headers: map[string]string{"X-Test": "a\nb"}}, wantErr: true},
+		{name: "header value tab", options: LocationOptions{Headers: map[string]string{"X-Test": "a\tb"}}, wantErr: true},
+		{name: "header value nul", options: LocationOptions{Headers: map[string]string{"X-Test": "a\x00b"}}, wantErr: true},
+		{name: "header value del", options: LocationOptions{Headers: map[string]string{"X-Test": "a\x7fb"}}, wantErr: true},
+		{name: "header value too long", options: LocationOptions{Headers: map[string]string{"X-Test": strings.Repeat("a", 1025)}}, wantErr: true},
+
+		{name: "too many entries", options: LocationOptions{Allow: make([]string, maxListLength+1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := tt.options.Validate()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: Validate succeeded, want error", tt.name)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: Validate: %v", tt.name, err)
+		}
+	}
+}
+
+func TestLocationOptionsDirectives(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *LocationOptions
+		want    string
+	}{
+		{name: "nil", options: nil, want: ""},
+		{name: "empty", options: &LocationOptions{}, want: ""},
+		{
+			name:    "deny only",
+			options: &LocationOptions{Deny: []string{"10.0.0.1"}},
+			want:    "deny 10.0.0.1;",
+		},
+		{
+			name:    "allow adds deny all",
+			options: &LocationOptions{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.0.0.1"}},
+			want:    "deny 10.0.0.1;\nallow 10.0.0.0/8;\ndeny all;",
+		},
+		{
+			name:    "rate limit without burst",
+			options: &LocationOptions{RateLimit: 10},
+			want:    "limit_req zone=plugin_demo_req;",
+		},
+		{
+			name: "all options",
+			options: &LocationOptions{
+				RateLimit:   10,
+				RateBurst:   20,
+				ConnLimit:   5,
+				MaxBodySize: "10m",
+				BasicAuth:   []BasicAuthUser{{Username: "admin", Hash: "{SSHA}abc"}},
+				Headers:     map[string]string{"X-Frame-Options": "DENY", "Cache-Control": "no-store"},
+			},
+			want: strings.Join([]string{
+				"limit_req zone=plugin_demo_req burst=20 nodelay;",
+				"limit_conn plugin_demo_conn 5;",
+				"client_max_body_size 10m;",
+				`auth_basic "demo";`,
+				"auth_basic_user_file " + htpasswdPath("demo") + ";",
+				`add_header Cache-Control "no-store" always;`,
+				`add_header X-Frame-Options "DENY" always;`,
+			}, "\n"),
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.options.Directives("demo"); got != tt.want {
+			t.Errorf("%s: Directives() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLocationOptionsZonesAndHtpasswd(t *testing.T) {
+	options := &LocationOptions{
+		RateLimit: 10,
+		ConnLimit: 5,
+		BasicAuth: []BasicAuthUser{{Username: "admin", Hash: "{SSHA}abc"}, {Username: "guest", Hash: "{SSHA}def"}},
+	}
+	wantZones := "limit_req_zone $binary_remote_addr zone=plugin_demo_req:10m rate=10r/s;\n" +
+		"limit_conn_zone $binary_remote_addr zone=plugin_demo_conn:10m;\n"
+	if got := options.Zones("demo"); got != wantZones {
+		t.Errorf("Zones() = %q, want %q", got, wantZones)
+	}
+	if got := (&LocationOptions{}).Zones("demo"); got != "" {
+		t.Errorf("Zones() without limits = %q, want empty", got)
+	}
+	if got, want := options.Htpasswd(), "admin:{SSHA}abc\nguest:{SSHA}def\n"; got != want {
+		t.Errorf("Htpasswd() = %q, want %q", got, want)
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "{SSHA}") {
+		t.Fatalf("HashPassword() = %q, want {SSHA} prefix", hash)
+	}
+	// 每次生成的盐不同
+	again, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == hash {
+		t.Fatalf("HashPassword() returned the same hash twice: %q", hash)
+	}
+	options := LocationOptions{BasicAuth: []BasicAuthUser{{Username: "admin", Hash: hash}}}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("Validate() with generated hash: %v", err)
+	}
+}
+
+func TestInjectDirectives(t *testing.T) {
+	const directives = "deny all;\nclient_max_body_size 1m;"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no directives",
+			content: "location /demo/ {\n\tproxy_pass http://demo;\n}",
+			want:    "location /demo/ {\n\tproxy_pass http://demo;\n}",
+		},
+		{
+			name:    "single location",
+			content: "location /demo/ {\n\tproxy_pass http://demo;\n}",
+			want:    "location /demo/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n\tproxy_pass http://demo;\n}",
+		},
+		{
+			name:    "indented location",
+			content: "server {\n    location /demo/ {\n        proxy_pass http://demo;\n    }\n}",
+			want:    "server {\n    location /demo/ {\n    \tdeny all;\n    \tclient_max_body_size 1m;\n        proxy_pass http://demo;\n    }\n}",
+		},
+		{
+			name:    "without proxy_pass",
+			content: "location /demo/ {\n\treturn 301 /demo/index.html;\n}",
+			want:    "location /demo/ {\n\treturn 301 /demo/index.html;\n}",
+		},
+		{
+			name:    "internal location",
+			content: "location /demo/internal/ {\n\tinternal;\n\tproxy_pass http://demo;\n}",
+			want:    "location /demo/internal/ {\n\tinternal;\n\tproxy_pass http://demo;\n}",
+		},
+		{
+			name:    "proxy_pass inside if",
+			content: "location /demo/ {\n\tif ($request_method = POST) {\n\t\tproxy_pass http://demo;\n\t}\n}",
+			want:    "location /demo/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n\tif ($request_method = POST) {\n\t\tproxy_pass http://demo;\n\t}\n}",
+		},
+		{
+			name: "multiple locations",
+			content: "location /demo/ {\n\tproxy_pass http://demo;\n}\n" +
+				"location /demo/static/ {\n\treturn 404;\n}\n" +
+				"location /demo/api/ {\n\tproxy_pass http://demo-api;\n}",
+			want: "location /demo/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n\tproxy_pass http://demo;\n}\n" +
+				"location /demo/static/ {\n\treturn 404;\n}\n" +
+				"location /demo/api/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n\tproxy_pass http://demo-api;\n}",
+		},
+		{
+			name: "nested location",
+			content: "location /demo/ {\n" +
+				"\tlocation /demo/api/ {\n\t\tproxy_pass http://demo-api;\n\t}\n" +
+				"\tlocation /demo/static/ {\n\t\troot /var/www;\n\t}\n" +
+				"}",
+			want: "location /demo/ {\n" +
+				"\tlocation /demo/api/ {\n\t\tdeny all;\n\t\tclient_max_body_size 1m;\n\t\tproxy_pass http://demo-api;\n\t}\n" +
+				"\tlocation /demo/static/ {\n\t\troot /var/www;\n\t}\n" +
+				"}",
+		},
+		{
+			// 外层 location 自身转发请求时同样需要限制，否则未匹配嵌套 location 的请求绕过访问控制
+			name: "nested location with outer proxy_pass",
+			content: "location /demo/ {\n" +
+				"\tlocation /demo/api/ {\n\t\tproxy_pass http://demo-api;\n\t}\n" +
+				"\tproxy_pass http://demo;\n" +
+				"}",
+			want: "location /demo/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n" +
+				"\tlocation /demo/api/ {\n\t\tdeny all;\n\t\tclient_max_body_size 1m;\n\t\tproxy_pass http://demo-api;\n\t}\n" +
+				"\tproxy_pass http://demo;\n" +
+				"}",
+		},
+		{
+			name: "nested internal location",
+			content: "location /demo/ {\n" +
+				"\tlocation /demo/auth {\n\t\tinternal;\n\t\tproxy_pass http://demo-auth;\n\t}\n" +
+				"\tproxy_pass http://demo;\n" +
+				"}",
+			want: "location /demo/ {\n\tdeny all;\n\tclient_max_body_size 1m;\n" +
+				"\tlocation /demo/auth {\n\t\tinternal;\n\t\tproxy_pass http://demo-auth;\n\t}\n" +
+				"\tproxy_pass http://demo;\n" +
+				"}",
+		},
+	}
+	for _, tt := range tests {
+		d := directives
+		if tt.name == "no directives" {
+			d = ""
+		}
+		if got := injectDirectives(tt.content, d); got != tt.want {
+			t.Errorf("%s: injectDirectives() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
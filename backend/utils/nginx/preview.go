@@ -0,0 +1,118 @@
+package nginx
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// previewDir Nginx容器中用于检测配置的临时目录
+const previewDir = "/tmp/doo-store-nginx-preview"
+
+// previewScript 复制一份完整的Nginx配置到临时目录，将其中的绝对路径指向临时目录，
+// 删除 $4、$5 并将插件的配置 $3 放到 $1 后执行 nginx -t，不会影响正在运行的配置。
+// 其余参数为成对的依赖文件的相对路径与内容文件，内容文件为 - 时删除该文件
+const previewScript = `target="$1" dir="$2" scratch="$3" default="$4" other="$5"
+shift 5
+files="$scratch"
+{
+	rm -rf "$dir" && cp -a /etc/nginx "$dir" &&
+	find "$dir" -type f -name '*.conf' -exec sed -i "s#/etc/nginx/#$dir/#g" {} + &&
+	rm -f "$dir/$default" "$dir/$other" &&
+	cp "$scratch" "$dir/$target" &&
+	while [ $# -ge 2 ]; do
+		if [ "$2" = "-" ]; then
+			rm -f "$dir/$1"
+		else
+			files="$files $2"
+			mkdir -p "$(dirname "$dir/$1")" && cp "$2" "$dir/$1" || break
+		fi
+		shift 2
+	done &&
+	[ $# -lt 2 ] &&
+	nginx -t -c "$dir/nginx.conf"
+} 2>&1
+rc=$?
+rm -rf "$dir" $files
+exit $rc`
+
+// PreviewResult location配置的检测结果
+type PreviewResult struct {
+	Content string `json:"content"` // 渲染后的配置
+	Valid   bool   `json:"valid"`   // nginx -t 是否通过
+	Output  string `json:"output"`  // nginx -t 的输出
+}
+
+// Render 渲染location配置
+func (nm *NginxManager) Render(locationConfig *LocationConfig) (string, error) {
+	return nm.generateLocationContent(locationConfig)
+}
+
+// Preview 渲染location配置并在Nginx容器的临时目录中执行 nginx -t，不会重新加载Nginx
+func (nm *NginxManager) Preview(locationConfig *LocationConfig) (*PreviewResult, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	log.Infof("Previewing location block for: %s", locationConfig.Name)
+
+	content, err := nm.generateLocationContent(locationConfig)
+	if err != nil {
+		// 模板本身有误时直接作为检测结果返回
+		return &PreviewResult{Output: err.Error()}, nil
+	}
+
+	tmp, err := os.CreateTemp("", locationConfig.Name+"-*.conf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(content)
+	_ = tmp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	scratchFile := fmt.Sprintf("/tmp/%s.preview.conf", locationConfig.Name)
+	if err := nm.dockerClient.CopyFileToContainer(nm.containerID, tmp.Name(), scratchFile); err != nil {
+		log.Errorf("Failed to copy preview config to container: %v", err)
+		return nil, fmt.Errorf("failed to copy preview config to container: %w", err)
+	}
+
+	// 临时目录中的相对路径：插件的配置、需要删除的默认配置以及另一种挂载方式的配置
+	relative := func(containerPath string) string {
+		return strings.TrimPrefix(containerPath, "/etc/nginx/")
+	}
+	target := relative(nm.getContainerPath(locationConfig.Name, locationConfig.Mode))
+	other := relative(nm.getContainerPath(locationConfig.Name, otherMode(locationConfig.Mode)))
+	defaultConf := other
+	if locationConfig.Mode != LocationModeHost {
+		defaultConf = relative(fmt.Sprintf("%s/%s-default.conf", containerAppsDir, locationConfig.Name))
+	}
+
+	args := []string{"sh", "-c", previewScript, "sh", target, previewDir, scratchFile, defaultConf, other}
+	for i, file := range locationConfig.auxiliaryFiles() {
+		if file.content == "" {
+			args = append(args, relative(file.path), "-")
+			continue
+		}
+		auxiliaryScratch := fmt.Sprintf("/tmp/%s.preview.%d", locationConfig.Name, i)
+		if err := nm.copyContentToContainer([]byte(file.content), auxiliaryScratch, "644"); err != nil {
+			log.Errorf("Failed to copy preview file to container: %v", err)
+			return nil, fmt.Errorf("failed to copy preview file to container: %w", err)
+		}
+		args = append(args, relative(file.path), auxiliaryScratch)
+	}
+
+	output, exitCode, err := nm.dockerClient.ExecInContainer(nm.containerID, args)
+	if err != nil {
+		log.Errorf("Failed to test preview config: %v", err)
+		return nil, fmt.Errorf("failed to test preview config: %w", err)
+	}
+
+	return &PreviewResult{
+		Content: content,
+		Valid:   exitCode == 0,
+		Output:  strings.TrimSpace(strings.ReplaceAll(output, previewDir+"/", "/etc/nginx/")),
+	}, nil
+}
@@ -0,0 +1,366 @@
+package nginx
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// configMu 串行化对Nginx容器中插件配置的修改
+var configMu sync.Mutex
+
+// Nginx容器中插件配置所在目录
+const (
+	containerAppsDir     = "/etc/nginx/conf.d/apps" // 路径挂载的 location，包含在DooTask的 server 块中
+	containerServersDir  = "/etc/nginx/conf.d"      // 独立域名的 server 块以及限流区域
+	containerHtpasswdDir = "/etc/nginx/htpasswd"    // Basic Auth 的密码文件
+)
+
+// ReconcileReport Nginx配置核对结果
+type ReconcileReport struct {
+	Desired  int      `json:"desired"`  // 应存在的插件配置数量
+	Created  []string `json:"created"`  // 容器中缺失并重新写入的配置
+	Updated  []string `json:"updated"`  // 内容不一致并重新写入的配置
+	Removed  []string `json:"removed"`  // 已删除的残留配置
+	Failed   []string `json:"failed"`   // 生成失败的配置
+	Reloaded bool     `json:"reloaded"` // 是否重新加载了Nginx
+}
+
+// Changed 是否修改了容器中的配置
+func (r *ReconcileReport) Changed() bool {
+	return len(r.Created) > 0 || len(r.Updated) > 0 || len(r.Removed) > 0
+}
+
+// reconcileChange 记录被覆盖的配置，用于检测失败时回滚
+type reconcileChange struct {
+	name     string
+	path     string // 容器中的配置路径
+	previous []byte // 为空表示容器中原本不存在该配置
+	removed  bool   // 配置是作为残留被删除的，previous 为删除前的内容
+}
+
+// Reconcile 对比期望的插件配置与Nginx容器中的实际配置，重新写入缺失或内容不一致的配置，
+// 删除已卸载插件残留的配置，有修改时只重新加载一次。
+// keep 中的配置即使不在期望列表中也不会被当作残留删除，例如安装中的插件
+func (nm *NginxManager) Reconcile(desired []*LocationConfig, keep []string) (*ReconcileReport, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	report := &ReconcileReport{
+		Desired: len(desired),
+		Created: []string{},
+		Updated: []string{},
+		Removed: []string{},
+		Failed:  []string{},
+	}
+
+	// 容器中已存在的配置文件，以完整路径为键
+	existing := map[string]bool{}
+	for _, dir := range []string{containerAppsDir, containerServersDir, containerHtpasswdDir} {
+		files, err := nm.dockerClient.ListFilesInContainer(nm.containerID, dir)
+		if err != nil {
+			log.Errorf("Failed to list nginx configs: %v", err)
+			return nil, fmt.Errorf("failed to list nginx configs: %w", err)
+		}
+		for _, file := range files {
+			existing[dir+"/"+file] = true
+		}
+	}
+
+	wanted := make(map[string]bool, len(desired)+len(keep))
+	for _, name := range keep {
+		wanted[name] = true
+	}
+
+	var changes []reconcileChange
+	for _, locationConfig := range desired {
+		name := locationConfig.Name
+		wanted[name] = true
+
+		content, err := nm.generateLocationContent(locationConfig)
+		if err != nil {
+			log.Warnf("Failed to generate location content for %s: %v", name, err)
+			report.Failed = append(report.Failed, name)
+			continue
+		}
+		if err := os.WriteFile(nm.getLocationPath(name), []byte(content), 0644); err != nil {
+			log.Warnf("Failed to write configuration file for %s: %v", name, err)
+			report.Failed = append(report.Failed, name)
+			continue
+		}
+
+		containerPath := nm.getContainerPath(name, locationConfig.Mode)
+		otherPath := nm.getContainerPath(name, otherMode(locationConfig.Mode))
+		defaultPath := fmt.Sprintf("%s/%s-default.conf", containerAppsDir, name)
+		// 路径挂载时需要备份默认配置，容器重建后默认配置会重新出现
+		needDefaultBackup := locationConfig.Mode != LocationModeHost && existing[defaultPath]
+
+		// 先同步依赖的文件，配置引用的限流区域不存在时Nginx配置检测会失败
+		auxiliaryChanged := false
+		for _, file := range locationConfig.auxiliaryFiles() {
+			change, changed, err := nm.reconcileFile(name, file, existing[file.path])
+			if err != nil {
+				log.Warnf("Failed to sync %s for %s: %v", file.path, name, err)
+				continue
+			}
+			if changed {
+				changes = append(changes, change)
+				auxiliaryChanged = true
+			}
+		}
+
+		var previous []byte
+		if existing[containerPath] {
+			previous, err = nm.dockerClient.ReadFileFromContainer(nm.containerID, containerPath)
+			if err != nil {
+				log.Warnf("Failed to read nginx config %s: %v", name, err)
+			} else if bytes.Equal(previous, []byte(content)) && !needDefaultBackup && !existing[otherPath] {
+				if auxiliaryChanged {
+					report.Updated = append(report.Updated, name)
+				}
+				continue
+			}
+		}
+
+		if needDefaultBackup {
+			if err := nm.handleDefaultConfig(name); err != nil {
+				log.Warnf("Failed to handle default config for %s: %v", name, err)
+			}
+		}
+		if err := nm.dockerClient.CopyFileToContainer(nm.containerID, nm.getLocationPath(name), containerPath); err != nil {
+			log.Warnf("Failed to copy config %s to container: %v", name, err)
+			report.Failed = append(report.Failed, name)
+			continue
+		}
+		changes = append(changes, reconcileChange{name: name, path: containerPath, previous: previous})
+		// 挂载方式变化后删除另一种方式的配置
+		if existing[otherPath] {
+			if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, otherPath); err != nil {
+				log.Warnf("Failed to remove config of the other mode for %s: %v", name, err)
+			}
+		}
+		if existing[containerPath] {
+			report.Updated = append(report.Updated, name)
+		} else {
+			report.Created = append(report.Created, name)
+		}
+	}
+
+	// 容器中存在插件配置但插件已不存在，说明是卸载时未能清理的残留
+	var restoredDefaults []string
+	for _, name := range orphanNames(existing, wanted) {
+		removed := true
+		for _, containerPath := range append(nm.containerPaths(name), auxiliaryPaths(name)...) {
+			if !existing[containerPath] {
+				continue
+			}
+			// 保存原来的内容，重新加载失败时恢复
+			previous, err := nm.dockerClient.ReadFileFromContainer(nm.containerID, containerPath)
+			if err == nil {
+				err = nm.dockerClient.RemoveFileFormContainer(nm.containerID, containerPath)
+			}
+			if err != nil {
+				log.Warnf("Failed to remove orphaned config %s: %v", name, err)
+				removed = false
+				continue
+			}
+			changes = append(changes, reconcileChange{name: name, path: containerPath, previous: previous, removed: true})
+		}
+		if !removed {
+			continue
+		}
+		if existing[fmt.Sprintf("%s/%s-default.conf.bak", containerAppsDir, name)] {
+			if err := nm.restoreDefaultConfig(name); err != nil {
+				log.Warnf("Failed to restore default config for %s: %v", name, err)
+			} else {
+				restoredDefaults = append(restoredDefaults, name)
+			}
+		}
+		report.Removed = append(report.Removed, name)
+	}
+
+	if report.Changed() {
+		if err := nm.testAndReload(); err != nil {
+			log.Errorf("Failed to reload nginx after reconcile: %v", err)
+			nm.revertChanges(changes, restoredDefaults)
+			return report, fmt.Errorf("failed to test and reload nginx: %w", err)
+		}
+		report.Reloaded = true
+	}
+
+	// 删除本地残留的配置文件，本地文件不影响Nginx，不需要重新加载
+	localFiles, _ := filepath.Glob(filepath.Join(nm.nginxConfig.ConfigDir, "*.conf"))
+	for _, localFile := range localFiles {
+		if !wanted[strings.TrimSuffix(filepath.Base(localFile), ".conf")] {
+			_ = os.Remove(localFile)
+		}
+	}
+	if !report.Reloaded {
+		return report, nil
+	}
+	log.Infof("Nginx configs reconciled, created: %v, updated: %v, removed: %v", report.Created, report.Updated, report.Removed)
+	return report, nil
+}
+
+// orphanNames 从容器中已存在的配置文件解析插件名称，返回不在 wanted 中的插件
+func orphanNames(existing, wanted map[string]bool) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for path := range existing {
+		dir, file := filepath.Dir(path), filepath.Base(path)
+		var name string
+		switch {
+		case dir == containerAppsDir && strings.HasSuffix(file, ".conf") && !strings.HasSuffix(file, "-default.conf"):
+			name = strings.TrimSuffix(file, ".conf")
+		case dir == containerServersDir && strings.HasPrefix(file, "plugin-") && strings.HasSuffix(file, ".zones.conf"):
+			name = strings.TrimSuffix(strings.TrimPrefix(file, "plugin-"), ".zones.conf")
+		case dir == containerServersDir && strings.HasPrefix(file, "plugin-") && strings.HasSuffix(file, ".conf"):
+			name = strings.TrimSuffix(strings.TrimPrefix(file, "plugin-"), ".conf")
+		case dir == containerHtpasswdDir && strings.HasPrefix(file, "plugin-"):
+			name = strings.TrimPrefix(file, "plugin-")
+		}
+		if name == "" || wanted[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// revertChanges 将核对过程中写入或删除的配置恢复为原来的内容，restoredDefaults 中插件的默认配置重新备份
+func (nm *NginxManager) revertChanges(changes []reconcileChange, restoredDefaults []string) {
+	for _, name := range restoredDefaults {
+		if err := nm.handleDefaultConfig(name); err != nil {
+			log.Warnf("Failed to back up default config %s during revert: %v", name, err)
+		}
+	}
+	for _, change := range changes {
+		if change.previous == nil && !change.removed {
+			if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, change.path); err != nil {
+				log.Warnf("Failed to remove config %s during revert: %v", change.name, err)
+			}
+			continue
+		}
+		tmp, err := os.CreateTemp("", change.name+"-*.conf")
+		if err != nil {
+			log.Warnf("Failed to create temp file during revert: %v", err)
+			continue
+		}
+		_, err = tmp.Write(change.previous)
+		_ = tmp.Close()
+		if err == nil {
+			err = nm.dockerClient.CopyFileToContainer(nm.containerID, tmp.Name(), change.path)
+		}
+		if err != nil {
+			log.Warnf("Failed to restore config %s during revert: %v", change.name, err)
+		}
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// reconcileFile 同步一个依赖的文件，内容为空时删除，返回用于回滚的记录以及是否有修改
+func (nm *NginxManager) reconcileFile(name string, file auxiliaryFile, exists bool) (reconcileChange, bool, error) {
+	change := reconcileChange{name: name, path: file.path}
+	if exists {
+		previous, err := nm.dockerClient.ReadFileFromContainer(nm.containerID, file.path)
+		if err != nil {
+			return change, false, err
+		}
+		if string(previous) == file.content {
+			return change, false, nil
+		}
+		change.previous = previous
+	} else if file.content == "" {
+		return change, false, nil
+	}
+
+	if file.content == "" {
+		return change, true, nm.dockerClient.RemoveFileFormContainer(nm.containerID, file.path)
+	}
+	return change, true, nm.copyContentToContainer([]byte(file.content), file.path, "644")
+}
+
+// auxiliaryFile 插件配置依赖的其他文件，内容为空表示不应存在
+type auxiliaryFile struct {
+	path    string
+	content string
+}
+
+// auxiliaryFiles 插件配置依赖的限流区域与 Basic Auth 密码文件
+func (lc *LocationConfig) auxiliaryFiles() []auxiliaryFile {
+	return []auxiliaryFile{
+		{path: zonesPath(lc.Name), content: lc.Options.Zones(lc.Name)},
+		{path: htpasswdPath(lc.Name), content: lc.Options.Htpasswd()},
+	}
+}
+
+// writeAuxiliaryFiles 写入插件配置依赖的文件，并删除不再需要的文件
+func (nm *NginxManager) writeAuxiliaryFiles(locationConfig *LocationConfig) error {
+	for _, file := range locationConfig.auxiliaryFiles() {
+		var err error
+		if file.content == "" {
+			err = nm.dockerClient.RemoveFileFormContainer(nm.containerID, file.path)
+		} else {
+			err = nm.copyContentToContainer([]byte(file.content), file.path, "644")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+	}
+	return nil
+}
+
+// removeAuxiliaryFiles 删除插件配置依赖的文件
+func (nm *NginxManager) removeAuxiliaryFiles(key string) error {
+	for _, file := range auxiliaryPaths(key) {
+		if err := nm.dockerClient.RemoveFileFormContainer(nm.containerID, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auxiliaryPaths 插件配置依赖的文件在Nginx容器中的路径
+func auxiliaryPaths(key string) []string {
+	return []string{zonesPath(key), htpasswdPath(key)}
+}
+
+// zonesPath 限流区域配置的路径，需要包含在 http 块中
+func zonesPath(key string) string {
+	return fmt.Sprintf("%s/plugin-%s.zones.conf", containerServersDir, key)
+}
+
+// htpasswdPath Basic Auth 密码文件的路径
+func htpasswdPath(key string) string {
+	return fmt.Sprintf("%s/plugin-%s", containerHtpasswdDir, key)
+}
+
+// containerPaths returns the paths of both modes of a location configuration file inside the Nginx container
+func (nm *NginxManager) containerPaths(key string) []string {
+	return []string{nm.getContainerPath(key, LocationModePath), nm.getContainerPath(key, LocationModeHost)}
+}
+
+// getContainerPath returns the path of a location configuration file inside the Nginx container,
+// server blocks of host mode are placed in the http level directory
+func (nm *NginxManager) getContainerPath(key, mode string) string {
+	if mode == LocationModeHost {
+		return fmt.Sprintf("%s/plugin-%s.conf", containerServersDir, key)
+	}
+	return fmt.Sprintf("%s/%s.conf", containerAppsDir, key)
+}
+
+// otherMode returns the mode other than the given one
+func otherMode(mode string) string {
+	if mode == LocationModeHost {
+		return LocationModePath
+	}
+	return LocationModeHost
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [archive]",
+	Short: "Export the whole store to a single archive for migrating hosts",
+	Long: `Export the whole store to a single archive for migrating hosts.
+
+Secret plugin parameters are decrypted before export and encrypted again with
+the target host's key on import, so the archive contains them in plain text.
+The archive is created with mode 0600; keep it somewhere safe.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		withVolumes, _ := cmd.Flags().GetBool("volumes")
+		dst := fmt.Sprintf("doo-store-%s.tar.gz", time.Now().Format("20060102150405"))
+		if len(args) > 0 {
+			dst = args[0]
+		}
+		app.Init()
+		if err := service.NewStoreMigrator().Export(dst, withVolumes); err != nil {
+			return err
+		}
+		fmt.Println("整站导出完成:", dst)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().BoolP("volumes", "v", false, "include volume data")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/cmd/migrate"
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "Import a whole-store archive exported from another host",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		autoMigrate, _ := cmd.Flags().GetBool("migrate")
+		if autoMigrate {
+			// 在新主机上导入时需要先创建数据表
+			fmt.Println("执行数据库自动迁移")
+			migrate.Migrate()
+		}
+		app.Init()
+		if err := service.NewStoreMigrator().Import(args[0]); err != nil {
+			return err
+		}
+		fmt.Println("整站导入完成")
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolP("migrate", "m", false, "databases auto migrate")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"doo-store/backend/utils/docker"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// ipamCmd represents the ipam command
+var ipamCmd = &cobra.Command{
+	Use:   "ipam",
+	Short: "Manage IP allocations of the plugin network",
+}
+
+// ipamReconcileCmd represents the ipam reconcile command
+var ipamReconcileCmd = &cobra.Command{
+	Use:          "reconcile",
+	Short:        "Compare IP allocations with the addresses used by containers and report conflicts",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app.Init()
+		client, err := docker.NewClient()
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		report, err := service.NewIPAllocationManager().Reconcile(client)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("网段: %s, 分配记录: %d, 容器IP: %d\n", report.Network, report.Allocations, report.Containers)
+		if len(report.Conflicts) == 0 {
+			fmt.Println("未发现冲突")
+			return nil
+		}
+		for _, conflict := range report.Conflicts {
+			fmt.Printf("[%s] %s\n", conflict.Type, conflict.Message)
+		}
+		return fmt.Errorf("发现 %d 个冲突", len(report.Conflicts))
+	},
+}
+
+func init() {
+	ipamCmd.AddCommand(ipamReconcileCmd)
+	rootCmd.AddCommand(ipamCmd)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// nginxCmd represents the nginx command
+var nginxCmd = &cobra.Command{
+	Use:   "nginx",
+	Short: "Manage nginx configurations of installed plugins",
+}
+
+// nginxReconcileCmd represents the nginx reconcile command
+var nginxReconcileCmd = &cobra.Command{
+	Use:          "reconcile",
+	Short:        "Re-push missing or drifted plugin locations into the nginx container and remove orphaned ones",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app.Init()
+		report, err := service.ReconcileNginx()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("期望配置: %d, 新增: %v, 更新: %v, 删除: %v\n", report.Desired, report.Created, report.Updated, report.Removed)
+		if len(report.Failed) > 0 {
+			return fmt.Errorf("生成失败的配置: %v", report.Failed)
+		}
+		if report.Reloaded {
+			fmt.Println("Nginx已重新加载")
+		} else {
+			fmt.Println("配置一致，无需重新加载")
+		}
+		return nil
+	},
+}
+
+func init() {
+	nginxCmd.AddCommand(nginxReconcileCmd)
+	rootCmd.AddCommand(nginxCmd)
+}
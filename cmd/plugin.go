@@ -0,0 +1,182 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/cmd/migrate"
+	"doo-store/backend/core/dto"
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Plugin development tools",
+}
+
+// pluginLintCmd represents the plugin lint command
+var pluginLintCmd = &cobra.Command{
+	Use:   "lint <file>",
+	Short: "Validate a plugin manifest or data.json",
+	// 检查失败不是用法错误，不输出帮助信息
+	SilenceUsage: true,
+	Args:         cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		linter, err := service.NewPluginLinter()
+		if err != nil {
+			return err
+		}
+		issues, err := linter.Lint(data)
+		if err != nil {
+			return err
+		}
+		errorCount := 0
+		for _, issue := range issues {
+			if issue.Level == service.LintError {
+				errorCount++
+			}
+			fmt.Println(issue)
+		}
+		if errorCount > 0 {
+			return fmt.Errorf("%d error(s), %d warning(s)", errorCount, len(issues)-errorCount)
+		}
+		fmt.Printf("检查通过，%d 个警告\n", len(issues))
+		return nil
+	},
+}
+
+// pluginInitCmd represents the plugin init command
+var pluginInitCmd = &cobra.Command{
+	Use:   "init <dir>",
+	Short: "Scaffold a plugin development directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, _ := cmd.Flags().GetString("key")
+		name, _ := cmd.Flags().GetString("name")
+		if key == "" {
+			key = filepath.Base(filepath.Clean(args[0]))
+		}
+		if name == "" {
+			name = key
+		}
+		if err := service.NewPluginScaffold(args[0]).Init(key, name); err != nil {
+			return err
+		}
+		fmt.Printf("插件模板已生成: %s\n", args[0])
+		return nil
+	},
+}
+
+// pluginPackCmd represents the plugin pack command
+var pluginPackCmd = &cobra.Command{
+	Use:          "pack <dir>",
+	Short:        "Build the uploadable plugin package from a plugin directory",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugin, err := packPlugin(args[0])
+		if err != nil {
+			return err
+		}
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = fmt.Sprintf("%s-%s.json", plugin.Key, plugin.Version)
+		}
+		data, err := json.MarshalIndent(plugin, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("插件已打包: %s\n", output)
+		return nil
+	},
+}
+
+// pluginTryCmd represents the plugin try command
+var pluginTryCmd = &cobra.Command{
+	Use:          "try <dir>",
+	Short:        "Install a plugin directory into the local store and tail its logs",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugin, err := packPlugin(args[0])
+		if err != nil {
+			return err
+		}
+		values, _ := cmd.Flags().GetStringToString("param")
+		params := map[string]interface{}{}
+		for key, value := range values {
+			params[key] = value
+		}
+		follow, _ := cmd.Flags().GetBool("follow")
+		force, _ := cmd.Flags().GetBool("force")
+		autoMigrate, _ := cmd.Flags().GetBool("migrate")
+		if autoMigrate {
+			fmt.Println("执行数据库自动迁移")
+			migrate.Migrate()
+		}
+		app.Init()
+		return service.NewPluginScaffold(args[0]).Try(plugin, params, follow, force, os.Stdout)
+	},
+}
+
+// packPlugin 打包插件目录并输出检查结果，存在错误时返回错误
+func packPlugin(dir string) (*dto.Plugin, error) {
+	plugin, issues, err := service.NewPluginScaffold(dir).Pack()
+	if err != nil {
+		return nil, err
+	}
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Level == service.LintError {
+			errorCount++
+		}
+		fmt.Println(issue)
+	}
+	if errorCount > 0 {
+		return nil, fmt.Errorf("%d error(s), %d warning(s)", errorCount, len(issues)-errorCount)
+	}
+	return plugin, nil
+}
+
+func init() {
+	pluginInitCmd.Flags().String("key", "", "plugin key (default: directory name)")
+	pluginInitCmd.Flags().String("name", "", "plugin name (default: key)")
+	pluginPackCmd.Flags().StringP("output", "o", "", "output file (default: <key>-<version>.json)")
+	pluginTryCmd.Flags().StringToStringP("param", "p", nil, "install parameters, e.g. -p KEY=VALUE")
+	pluginTryCmd.Flags().BoolP("follow", "f", true, "follow container logs")
+	pluginTryCmd.Flags().BoolP("migrate", "m", false, "databases auto migrate")
+	pluginTryCmd.Flags().Bool("force", false, "replace the plugin if it already exists in the store")
+	pluginCmd.AddCommand(pluginInitCmd)
+	pluginCmd.AddCommand(pluginPackCmd)
+	pluginCmd.AddCommand(pluginTryCmd)
+	pluginCmd.AddCommand(pluginLintCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/core/cmd/migrate"
+	"doo-store/backend/core/service"
+	"doo-store/backend/init/app"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a plugin from a backup archive",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		autoMigrate, _ := cmd.Flags().GetBool("migrate")
+		if autoMigrate {
+			// 在新主机上恢复时需要先创建数据表
+			fmt.Println("执行数据库自动迁移")
+			migrate.Migrate()
+		}
+		app.Init()
+		if err := service.NewAppRestoreProcess(args[0]).Run(); err != nil {
+			return err
+		}
+		fmt.Println("插件恢复完成")
+		return nil
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolP("migrate", "m", false, "databases auto migrate")
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2024 xxyijixx@gmail.com
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"doo-store/backend/config"
+	"doo-store/backend/core/service"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// rotateKeyCmd represents the rotate-key command
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Re-encrypt plugin secrets with a new master key",
+	Long: `Re-encrypt the secrets of all installed plugins and the private keys of certificates with a new master key.
+Secrets stored in plain text by older versions are encrypted as well.
+After rotation, set MASTER_KEY to the new key and restart the service.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldKey, _ := cmd.Flags().GetString("old-key")
+		newKey, _ := cmd.Flags().GetString("new-key")
+		if oldKey == "" {
+			oldKey = config.EnvConfig.SecretKey()
+		}
+		if newKey == "" {
+			return errors.New("--new-key is required")
+		}
+		if err := service.NewSecretManager().RotateKey(oldKey, newKey); err != nil {
+			return err
+		}
+		fmt.Println("密钥轮换完成，请将 MASTER_KEY 设置为新的密钥后重启服务")
+		return nil
+	},
+}
+
+func init() {
+	rotateKeyCmd.Flags().String("old-key", "", "current key (default: MASTER_KEY or APP_KEY)")
+	rotateKeyCmd.Flags().String("new-key", "", "new master key")
+	rootCmd.AddCommand(rotateKeyCmd)
+}
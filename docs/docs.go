@@ -216,7 +216,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/apps/installed/{id}/logs": {
+        "/apps/installed/{id}/backup-schedule": {
             "get": {
                 "security": [
                     {
@@ -229,7 +229,7 @@ const docTemplate = `{
                 "tags": [
                     "app"
                 ],
-                "summary": "获取插件日志信息",
+                "summary": "获取插件定时备份计划",
                 "parameters": [
                     {
                         "type": "string",
@@ -238,26 +238,6 @@ const docTemplate = `{
                         "name": "language",
                         "in": "header"
                     },
-                    {
-                        "type": "integer",
-                        "description": "开始时间(Unix时间戳，秒)",
-                        "name": "since",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "结束时间(Unix时间戳，秒)",
-                        "name": "until",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1000,
-                        "description": "查询条数",
-                        "name": "tail",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "description": "id",
@@ -270,26 +250,40 @@ const docTemplate = `{
                     "200": {
                         "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AppBackupSchedule"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/apps/installed/{id}/params": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "cron 为标准的5段表达式（分 时 日 月 周），retention 为保留的定时备份数量，0表示不限制，手动创建的备份不会被清理",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "app"
                 ],
-                "summary": "获取插件参数信息",
+                "summary": "修改插件定时备份计划",
                 "parameters": [
                     {
                         "type": "string",
@@ -304,6 +298,15 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppBackupSchedule"
+                        }
                     }
                 ],
                 "responses": {
@@ -318,7 +321,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.AppInstalledParamsResp"
+                                            "$ref": "#/definitions/model.AppBackupSchedule"
                                         }
                                     }
                                 }
@@ -326,8 +329,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/apps/installed/{id}/backups": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
@@ -339,7 +344,7 @@ const docTemplate = `{
                 "tags": [
                     "app"
                 ],
-                "summary": "修改插件参数信息",
+                "summary": "获取插件备份列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -354,41 +359,51 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "RequestBody",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.AppInstall"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/response.AppBackupFile"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/apps/manage/upload": {
-            "post": {
+        "/apps/installed/{id}/domain": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "设置插件的独立域名，domain 为空时使用 \u003ckey\u003e.\u003cPLUGIN_BASE_DOMAIN\u003e。插件使用独立域名时会重新写入Nginx配置",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "nginx"
                 ],
-                "summary": "上传插件",
+                "summary": "修改插件域名",
                 "parameters": [
                     {
                         "type": "string",
@@ -397,13 +412,20 @@ const docTemplate = `{
                         "name": "language",
                         "in": "header"
                     },
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "RequestBody",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.PluginUpload"
+                            "$ref": "#/definitions/request.AppDomain"
                         }
                     }
                 ],
@@ -417,20 +439,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/apps/plugin/info": {
+        "/apps/installed/{id}/health": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "容器配置了 HEALTHCHECK 时使用Docker的检查结果，否则使用插件声明的HTTP健康检查，log 为最近的检查记录",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "app"
                 ],
-                "summary": "获取已安装的插件信息(仅需要登录)",
+                "summary": "获取插件健康状态",
                 "parameters": [
                     {
                         "type": "string",
@@ -440,36 +463,36 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "success",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "data": {
-                                    "type": "object",
-                                    "additionalProperties": {}
-                                },
-                                "msg": {
-                                    "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
                                 },
-                                "ret": {
-                                    "type": "string"
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.AppHealth"
+                                        }
+                                    }
                                 }
-                            }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/apps/running": {
+        "/apps/installed/{id}/logs": {
             "get": {
                 "security": [
                     {
@@ -482,7 +505,7 @@ const docTemplate = `{
                 "tags": [
                     "app"
                 ],
-                "summary": "获取所有已安装的插件信息(仅需要登录)",
+                "summary": "获取插件日志信息",
                 "parameters": [
                     {
                         "type": "string",
@@ -492,49 +515,58 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "开始时间(Unix时间戳，秒)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "结束时间(Unix时间戳，秒)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "查询条数",
+                        "name": "tail",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "success",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "data": {
-                                    "type": "object",
-                                    "additionalProperties": {}
-                                },
-                                "msg": {
-                                    "type": "string"
-                                },
-                                "ret": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/dto.Response"
                         }
                     }
                 }
             }
         },
-        "/apps/tags": {
+        "/apps/installed/{id}/nginx": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "按版本号倒序返回插件每次应用的Nginx配置，active 为当前使用的版本",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "nginx"
                 ],
-                "summary": "获取插件分类信息",
+                "summary": "获取插件Nginx配置版本",
                 "parameters": [
                     {
                         "type": "string",
@@ -542,6 +574,13 @@ const docTemplate = `{
                         "description": "i18n",
                         "name": "language",
                         "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -558,7 +597,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/model.Tag"
+                                                "$ref": "#/definitions/model.NginxLocationVersion"
                                             }
                                         }
                                     }
@@ -569,13 +608,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/apps/{key}": {
-            "put": {
+        "/apps/installed/{id}/nginx/apply": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "检测通过后应用配置并保存为新版本，应用失败时恢复原配置。template 为空时使用 version 对应的历史版本",
                 "consumes": [
                     "application/json"
                 ],
@@ -583,9 +623,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "nginx"
                 ],
-                "summary": "app update",
+                "summary": "应用插件Nginx配置",
                 "parameters": [
                     {
                         "type": "string",
@@ -595,9 +635,9 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
@@ -607,7 +647,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.AppInstalledOperate"
+                            "$ref": "#/definitions/request.NginxLocationApply"
                         }
                     }
                 ],
@@ -615,27 +655,39 @@ const docTemplate = `{
                     "200": {
                         "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.NginxLocationVersion"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/apps/installed/{id}/nginx/options": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "获取插件的IP白名单/黑名单、限流、请求体大小、Basic Auth 与响应头配置，不返回 Basic Auth 的密码",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "nginx"
                 ],
-                "summary": "插件安装",
+                "summary": "获取插件访问控制配置",
                 "parameters": [
                     {
                         "type": "string",
@@ -645,37 +697,41 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "RequestBody",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/request.AppInstall"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/nginx.LocationOptions"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "配置经过校验后渲染到插件的 location 中，检测通过后重新加载Nginx生效，不会重启插件容器。Basic Auth 用户未提交密码时沿用原密码",
                 "consumes": [
                     "application/json"
                 ],
@@ -683,9 +739,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "app"
+                    "nginx"
                 ],
-                "summary": "插件卸载",
+                "summary": "修改插件访问控制配置",
                 "parameters": [
                     {
                         "type": "string",
@@ -695,9 +751,9 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
@@ -707,7 +763,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/request.AppUnInstall"
+                            "$ref": "#/definitions/request.NginxLocationOptions"
                         }
                     }
                 ],
@@ -715,13 +771,90 @@ const docTemplate = `{
                     "200": {
                         "description": "success",
                         "schema": {
-                            "$ref": "#/definitions/dto.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/nginx.LocationOptions"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/apps/{key}/detail": {
+        "/apps/installed/{id}/nginx/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "渲染模板并在Nginx容器的临时目录中执行 nginx -t，不会重新加载Nginx。template 为空时使用 version 对应的历史版本",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nginx"
+                ],
+                "summary": "预览插件Nginx配置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.NginxLocationApply"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/nginx.PreviewResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/installed/{id}/params": {
             "get": {
                 "security": [
                     {
@@ -734,7 +867,7 @@ const docTemplate = `{
                 "tags": [
                     "app"
                 ],
-                "summary": "获取插件详情",
+                "summary": "获取插件参数信息",
                 "parameters": [
                     {
                         "type": "string",
@@ -743,17 +876,82 @@ const docTemplate = `{
                         "name": "language",
                         "in": "header"
                     },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.AppInstalledParamsResp"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "修改插件参数信息",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "key",
-                        "name": "key",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppInstall"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数验证失败时返回每个字段的错误",
                         "schema": {
                             "allOf": [
                                 {
@@ -763,7 +961,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/response.AppDetail"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.FieldError"
+                                            }
                                         }
                                     }
                                 }
@@ -773,8 +974,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/health": {
+        "/apps/installed/{id}/restart-policy": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取插件自动重启策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AppRestartPolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "插件主容器异常退出后等待 backoff 秒重启，之后每次等待时间翻倍，最长 max_backoff 秒；window 分钟内异常退出超过 max_restarts 次时判定为崩溃循环，停止容器并标记为 Dead。compose 中设置了 restart: always 时同样生效。关闭时其他参数可以不传，并取消等待中的自动重启",
                 "consumes": [
                     "application/json"
                 ],
@@ -782,178 +1039,2393 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "app"
+                ],
+                "summary": "修改插件自动重启策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppRestartPolicy"
+                        }
+                    }
                 ],
-                "summary": "health",
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "success",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AppRestartPolicy"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.EnvElement": {
-            "type": "object",
-            "properties": {
-                "key": {
-                    "type": "string"
-                },
-                "name": {
+        },
+        "/apps/installed/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "从备份恢复插件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppRestore"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/installed/{id}/status-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "插件（kind=app）及其服务容器（kind=service）的状态变化，按时间倒序排列",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取插件状态变化记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "每页条数",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "app",
+                            "service"
+                        ],
+                        "type": "string",
+                        "description": "记录对象",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "开始时间(Unix时间戳，秒)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "结束时间(Unix时间戳，秒)",
+                        "name": "until",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/dto.PageResult"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "items": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/model.AppStatusTransition"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/installed/{id}/uptime": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "根据状态变化记录统计可用率与平均故障间隔（MTBF），只有 Running 计为运行正常，停止与安装中的时间不计入统计，从 Running 变为 Error、Dead 或 UnHealthy 计为一次故障",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取插件可用率",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 168,
+                        "description": "统计最近多少小时，最多 2160",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.AppUptime"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/manage/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "上传插件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.PluginUpload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/plugin/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取已安装的插件信息(仅需要登录)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "object",
+                                    "additionalProperties": {}
+                                },
+                                "msg": {
+                                    "type": "string"
+                                },
+                                "ret": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/running": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取所有已安装的插件信息(仅需要登录)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "data": {
+                                    "type": "object",
+                                    "additionalProperties": {}
+                                },
+                                "msg": {
+                                    "type": "string"
+                                },
+                                "ret": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取插件分类信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Tag"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{key}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "app update",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppInstalledOperate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "插件安装",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppInstall"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "参数验证失败时返回每个字段的错误",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.FieldError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "插件卸载",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.AppUnInstall"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/apps/{key}/detail": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "app"
+                ],
+                "summary": "获取插件详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.AppDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "获取插件独立域名使用的证书，按过期时间排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "获取证书列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Certificate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传PEM格式的证书链与私钥，域名已有证书时替换原证书，使用该域名的插件会启用HTTPS",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "上传证书",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CertificateUpload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Certificate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/acme": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "通过ACME的HTTP-01验证为插件的独立域名申请证书，申请在后台进行，证书会在过期前自动续期",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "申请证书",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.CertificateIssue"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Certificate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除证书，使用该证书的插件会停用HTTPS",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "删除证书",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/{id}/renew": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "立即续期通过ACME申请的证书，续期在后台进行",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "certificate"
+                ],
+                "summary": "续期证书",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Certificate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ipam": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "state 可选 reserved、allocated、excluded、untracked，不指定时返回所有非空闲的IP；空闲的IP和起始IP之前的地址只统计数量\nstate 可选 free、reserved、allocated、excluded、untracked，不指定时返回所有非空闲的IP",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ipam"
+                ],
+                "summary": "获取IP使用情况",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.IPAMOverview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ipam/exclude": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "排除的IP不会分配给插件，也不能在 docker-compose 中使用，end 为空时只排除 start",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ipam"
+                ],
+                "summary": "排除IP范围",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.IPRange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ipam/reconcile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "对比分配记录与容器实际使用的IP，返回发现的冲突",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ipam"
+                ],
+                "summary": "核对IP分配",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/response.IPReconcileReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ipam/reserve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "预留的IP不会分配给插件，end 为空时只预留 start",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ipam"
+                ],
+                "summary": "预留IP范围",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "RequestBody",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/request.IPRange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ipam/{ip}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "释放预留、排除或已失效的分配记录，仍被已安装插件使用的IP不允许释放",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ipam"
+                ],
+                "summary": "释放IP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ip",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/nginx/reconcile": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "对比已安装插件的location配置与Nginx容器中的配置，重新写入缺失或内容不一致的配置并删除残留的配置",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nginx"
+                ],
+                "summary": "核对Nginx配置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "zh",
+                        "description": "i18n",
+                        "name": "language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dto.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/nginx.ReconcileReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/public/auth-check": {
+            "get": {
+                "description": "供 Nginx auth_request 调用，校验请求中的 token（请求头、Cookie 或原始请求的参数），通过时返回 200 并在响应头中携带用户ID与身份，否则返回 401",
+                "tags": [
+                    "public"
+                ],
+                "summary": "插件登录校验",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token",
+                        "name": "token",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "原始请求地址",
+                        "name": "X-Original-URI",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/health": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "health",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/plugin-schema": {
+            "get": {
+                "description": "用于校验 data.json 中的插件清单（dto.Plugin）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "插件清单的 JSON Schema",
+                "responses": {
+                    "200": {
+                        "description": "JSON Schema",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.Dependency": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "依赖的字段",
+                    "type": "string"
+                },
+                "operator": {
+                    "description": "比较操作符：eq, neq, in, etc.",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "依赖字段的值"
+                }
+            }
+        },
+        "dto.EnvElement": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "dependency": {
+                    "$ref": "#/definitions/dto.Dependency"
+                },
+                "env_key": {
+                    "type": "string"
+                },
+                "generator": {
+                    "description": "服务端生成值的规则",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.Generator"
+                        }
+                    ]
+                },
+                "hidden": {
+                    "description": "是否隐藏",
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "label_i18n": {
+                    "description": "多语言的字段名称",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Option"
+                    }
+                },
+                "order": {
+                    "description": "显示顺序",
+                    "type": "integer"
+                },
+                "placeholder": {
+                    "type": "string"
+                },
+                "placeholder_i18n": {
+                    "description": "多语言的占位提示",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "readonly": {
+                    "description": "是否只读",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/dto.FieldType"
+                },
+                "validation": {
+                    "$ref": "#/definitions/dto.Validation"
+                }
+            }
+        },
+        "dto.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段对应的环境变量名",
+                    "type": "string"
+                },
+                "label": {
+                    "description": "字段名称",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "已翻译的错误信息",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FieldType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "select",
+                "number",
+                "password",
+                "radio",
+                "checkbox",
+                "textarea",
+                "switch",
+                "email",
+                "url",
+                "port",
+                "file",
+                "key_value",
+                "json",
+                "duration"
+            ],
+            "x-enum-comments": {
+                "FieldTypeDuration": "时长，如 30s、1h30m",
+                "FieldTypeEmail": "邮箱",
+                "FieldTypeFile": "文件上传，内容为文本或 data URL，保存到工作目录后挂载到容器",
+                "FieldTypeJSON": "JSON",
+                "FieldTypeKeyValue": "键值对列表",
+                "FieldTypePort": "端口，1-65535",
+                "FieldTypeSwitch": "开关，值为 true/false",
+                "FieldTypeTextarea": "多行文本",
+                "FieldTypeURL": "链接"
+            },
+            "x-enum-varnames": [
+                "FieldTypeText",
+                "FieldTypeSelect",
+                "FieldTypeNumber",
+                "FieldTypePassword",
+                "FieldTypeRadio",
+                "FieldTypeCheckbox",
+                "FieldTypeTextarea",
+                "FieldTypeSwitch",
+                "FieldTypeEmail",
+                "FieldTypeURL",
+                "FieldTypePort",
+                "FieldTypeFile",
+                "FieldTypeKeyValue",
+                "FieldTypeJSON",
+                "FieldTypeDuration"
+            ]
+        },
+        "dto.FormField": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "dependency": {
+                    "$ref": "#/definitions/dto.Dependency"
+                },
+                "env_key": {
+                    "type": "string"
+                },
+                "generator": {
+                    "description": "服务端生成值的规则",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.Generator"
+                        }
+                    ]
+                },
+                "hidden": {
+                    "description": "是否隐藏",
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "label_i18n": {
+                    "description": "多语言的字段名称",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Option"
+                    }
+                },
+                "order": {
+                    "description": "显示顺序",
+                    "type": "integer"
+                },
+                "placeholder": {
+                    "type": "string"
+                },
+                "placeholder_i18n": {
+                    "description": "多语言的占位提示",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "readonly": {
+                    "description": "是否只读",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/dto.FieldType"
+                },
+                "validation": {
+                    "$ref": "#/definitions/dto.Validation"
+                }
+            }
+        },
+        "dto.FormRule": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "规则涉及的字段（环境变量名）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "$ref": "#/definitions/dto.RuleType"
+                },
+                "when": {
+                    "description": "required_if 的触发条件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.Dependency"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.Generator": {
+            "type": "object",
+            "properties": {
+                "charset": {
+                    "description": "password 的字符集，与 common.GeneratePassword 的 t 参数一致",
+                    "type": "string"
+                },
+                "length": {
+                    "description": "random_string、password 的长度，默认16",
+                    "type": "integer"
+                },
+                "port_max": {
+                    "type": "integer"
+                },
+                "port_min": {
+                    "description": "port 的范围，默认 10000-65535",
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/dto.GeneratorType"
+                }
+            }
+        },
+        "dto.GeneratorType": {
+            "type": "string",
+            "enum": [
+                "random_string",
+                "uuid",
+                "password",
+                "port"
+            ],
+            "x-enum-comments": {
+                "GeneratorPassword": "随机密码",
+                "GeneratorPort": "下一个未被容器发布的端口，宿主机上非Docker进程占用的端口无法探测",
+                "GeneratorRandomString": "随机字符串",
+                "GeneratorUUID": "UUID"
+            },
+            "x-enum-varnames": [
+                "GeneratorRandomString",
+                "GeneratorUUID",
+                "GeneratorPassword",
+                "GeneratorPort"
+            ]
+        },
+        "dto.Option": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "label_i18n": {
+                    "description": "多语言的选项名称",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "sub_fields": {
+                    "description": "该选项特有的子字段",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FormField"
+                    }
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PageResult": {
+            "type": "object",
+            "properties": {
+                "items": {},
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.Response": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {},
+                "msg": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "dto.RuleType": {
+            "type": "string",
+            "enum": [
+                "equals",
+                "at_least_one",
+                "required_if"
+            ],
+            "x-enum-comments": {
+                "RuleAtLeastOne": "Fields 中至少填写一个",
+                "RuleEquals": "Fields 中所有字段的值必须相等，如确认密码",
+                "RuleRequiredIf": "满足 When 条件时 Fields 必填"
+            },
+            "x-enum-varnames": [
+                "RuleEquals",
+                "RuleAtLeastOne",
+                "RuleRequiredIf"
+            ]
+        },
+        "dto.Validation": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "description": "数值最大值",
+                    "type": "number"
+                },
+                "max_len": {
+                    "type": "integer"
+                },
+                "min": {
+                    "description": "数值最小值",
+                    "type": "number"
+                },
+                "min_len": {
+                    "type": "integer"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.Volume": {
+            "type": "object",
+            "properties": {
+                "local": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.App": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depends_version": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "description_i18n": {
+                    "$ref": "#/definitions/model.I18nText"
+                },
+                "github": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "name_i18n": {
+                    "description": "多语言的名称与描述，键为语言代码",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AppBackupSchedule": {
+            "type": "object",
+            "properties": {
+                "app_installed_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_result": {
+                    "type": "string"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "retention": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AppRestartPolicy": {
+            "type": "object",
+            "properties": {
+                "app_installed_id": {
+                    "type": "integer"
+                },
+                "backoff": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "gave_up_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_restart_at": {
+                    "type": "string"
+                },
+                "max_backoff": {
+                    "type": "integer"
+                },
+                "max_restarts": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "window": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AppStatusTransition": {
+            "type": "object",
+            "properties": {
+                "container_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "from": {
                     "type": "string"
                 },
-                "required": {
+                "id": {
+                    "type": "integer"
+                },
+                "install_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Certificate": {
+            "type": "object",
+            "properties": {
+                "auto_renew": {
                     "type": "boolean"
                 },
-                "type": {
+                "cert": {
                     "type": "string"
                 },
-                "value": {
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "not_after": {
+                    "type": "string"
+                },
+                "not_before": {
+                    "type": "string"
+                },
+                "renewed_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "dto.PageResult": {
+        "model.HealthCheck": {
             "type": "object",
             "properties": {
-                "items": {},
-                "total": {
+                "expect_status": {
+                    "description": "期望的HTTP状态码，为空时接受 2xx 与 3xx",
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "探测间隔（秒），默认 30",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "请求路径，例如 /healthz",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "容器端口",
+                    "type": "integer"
+                },
+                "retries": {
+                    "description": "连续失败多少次后判定为不健康，默认 3",
+                    "type": "integer"
+                },
+                "timeout": {
+                    "description": "超时时间（秒），默认 5",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.HealthProbe": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "exit_code": {
+                    "description": "0 表示成功",
+                    "type": "integer"
+                },
+                "output": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.I18nText": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "model.NginxLocationVersion": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "app_installed_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "remark": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Tag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "nginx.BasicAuthUser": {
+            "type": "object",
+            "properties": {
+                "hash": {
+                    "description": "{SSHA} 格式的密码哈希",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "明文密码，只在修改时提交",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "nginx.LocationOptions": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "description": "允许访问的IP或CIDR，设置后其他地址禁止访问",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "basic_auth": {
+                    "description": "Basic Auth 用户，为空时不开启",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nginx.BasicAuthUser"
+                    }
+                },
+                "conn_limit": {
+                    "description": "每个IP的并发连接数，0 表示不限制",
+                    "type": "integer"
+                },
+                "deny": {
+                    "description": "禁止访问的IP或CIDR",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "headers": {
+                    "description": "额外添加的响应头",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max_body_size": {
+                    "description": "请求体大小上限，例如 10m",
+                    "type": "string"
+                },
+                "rate_burst": {
+                    "description": "超出请求速率时允许的突发请求数",
+                    "type": "integer"
+                },
+                "rate_limit": {
+                    "description": "每个IP每秒的请求数，0 表示不限制",
+                    "type": "integer"
+                }
+            }
+        },
+        "nginx.PreviewResult": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "渲染后的配置",
+                    "type": "string"
+                },
+                "output": {
+                    "description": "nginx -t 的输出",
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "nginx -t 是否通过",
+                    "type": "boolean"
+                }
+            }
+        },
+        "nginx.ReconcileReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "容器中缺失并重新写入的配置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "desired": {
+                    "description": "应存在的插件配置数量",
                     "type": "integer"
+                },
+                "failed": {
+                    "description": "生成失败的配置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reloaded": {
+                    "description": "是否重新加载了Nginx",
+                    "type": "boolean"
+                },
+                "removed": {
+                    "description": "已删除的残留配置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated": {
+                    "description": "内容不一致并重新写入的配置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.AppBackupSchedule": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "retention": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "request.AppDomain": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "description": "为空时使用 \u003ckey\u003e.\u003c基础域名\u003e",
+                    "type": "string"
+                }
+            }
+        },
+        "request.AppInstall": {
+            "type": "object",
+            "required": [
+                "cpus",
+                "docker_compose",
+                "memory_limit",
+                "params"
+            ],
+            "properties": {
+                "cpus": {
+                    "type": "string"
+                },
+                "docker_compose": {
+                    "type": "string"
+                },
+                "domain": {
+                    "description": "插件的独立域名，为空时使用 \u003ckey\u003e.\u003c基础域名\u003e",
+                    "type": "string"
+                },
+                "memory_limit": {
+                    "type": "string"
+                },
+                "memory_unit": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "regenerate": {
+                    "description": "修改参数时需要重新生成值的字段",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "request.AppInstalledOperate": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
                 }
             }
         },
-        "dto.Response": {
+        "request.AppRestartPolicy": {
             "type": "object",
             "properties": {
-                "code": {
+                "backoff": {
+                    "description": "首次重启前等待的秒数",
                     "type": "integer",
-                    "example": 200
+                    "maximum": 3600,
+                    "minimum": 1
                 },
-                "data": {},
-                "msg": {
-                    "type": "string",
-                    "example": "success"
+                "enabled": {
+                    "type": "boolean"
+                },
+                "max_backoff": {
+                    "description": "重启前最长等待的秒数",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1
+                },
+                "max_restarts": {
+                    "description": "统计时间内最多自动重启的次数",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "window": {
+                    "description": "统计崩溃次数的时间范围（分钟）",
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
                 }
             }
         },
-        "dto.Volume": {
+        "request.AppRestore": {
             "type": "object",
+            "required": [
+                "file"
+            ],
             "properties": {
-                "local": {
-                    "type": "string"
-                },
-                "target": {
+                "file": {
                     "type": "string"
                 }
             }
         },
-        "model.App": {
+        "request.AppUnInstall": {
+            "type": "object"
+        },
+        "request.CertificateIssue": {
             "type": "object",
+            "required": [
+                "domain"
+            ],
             "properties": {
-                "class": {
-                    "type": "string"
-                },
-                "created_at": {
+                "domain": {
                     "type": "string"
-                },
-                "depends_version": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "github": {
+                }
+            }
+        },
+        "request.CertificateUpload": {
+            "type": "object",
+            "required": [
+                "cert",
+                "key"
+            ],
+            "properties": {
+                "cert": {
                     "type": "string"
                 },
-                "icon": {
+                "domain": {
+                    "description": "为空时使用证书中的第一个域名",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
                 "key": {
                     "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "sort": {
-                    "type": "integer"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
                 }
             }
         },
-        "model.Tag": {
+        "request.IPRange": {
             "type": "object",
+            "required": [
+                "start"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "key": {
+                "end": {
                     "type": "string"
                 },
-                "name": {
+                "note": {
                     "type": "string"
                 },
-                "sort": {
-                    "type": "integer"
-                },
-                "updated_at": {
+                "start": {
                     "type": "string"
                 }
             }
         },
-        "request.AppInstall": {
+        "request.NginxLocationApply": {
             "type": "object",
-            "required": [
-                "cpus",
-                "docker_compose",
-                "memory_limit",
-                "params"
-            ],
             "properties": {
-                "cpus": {
-                    "type": "string"
-                },
-                "docker_compose": {
+                "remark": {
                     "type": "string"
                 },
-                "memory_limit": {
+                "template": {
                     "type": "string"
                 },
-                "params": {
-                    "type": "object",
-                    "additionalProperties": true
+                "version": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "request.AppInstalledOperate": {
+        "request.NginxLocationOptions": {
             "type": "object",
             "properties": {
-                "action": {
-                    "type": "string"
+                "allow": {
+                    "description": "允许访问的IP或CIDR，设置后其他地址禁止访问",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "params": {
+                "basic_auth": {
+                    "description": "Basic Auth 用户，为空时不开启",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nginx.BasicAuthUser"
+                    }
+                },
+                "conn_limit": {
+                    "description": "每个IP的并发连接数，0 表示不限制",
+                    "type": "integer"
+                },
+                "deny": {
+                    "description": "禁止访问的IP或CIDR",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "headers": {
+                    "description": "额外添加的响应头",
                     "type": "object",
-                    "additionalProperties": true
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "max_body_size": {
+                    "description": "请求体大小上限，例如 10m",
+                    "type": "string"
+                },
+                "rate_burst": {
+                    "description": "超出请求速率时允许的突发请求数",
+                    "type": "integer"
+                },
+                "rate_limit": {
+                    "description": "每个IP每秒的请求数，0 表示不限制",
+                    "type": "integer"
                 }
             }
         },
-        "request.AppUnInstall": {
-            "type": "object"
-        },
         "request.PluginUpload": {
             "type": "object",
             "properties": {
@@ -969,6 +3441,14 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "description_i18n": {
+                    "description": "多语言的描述，键为语言代码",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
                 "docker_compose": {
                     "type": "string"
                 },
@@ -981,6 +3461,14 @@ const docTemplate = `{
                 "github": {
                     "type": "string"
                 },
+                "health_check": {
+                    "description": "镜像没有 HEALTHCHECK 时使用的HTTP健康检查",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.HealthCheck"
+                        }
+                    ]
+                },
                 "icon": {
                     "type": "string"
                 },
@@ -990,12 +3478,35 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "name_i18n": {
+                    "description": "多语言的名称，键为语言代码",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.I18nText"
+                        }
+                    ]
+                },
+                "nginx_auth": {
+                    "description": "生成的 location 是否需要登录 DooTask 后才能访问",
+                    "type": "boolean"
+                },
                 "nginx_config": {
                     "type": "string"
                 },
+                "nginx_root_path": {
+                    "description": "插件不支持子路径，需要使用独立域名挂载在根路径",
+                    "type": "boolean"
+                },
                 "repo": {
                     "type": "string"
                 },
+                "rules": {
+                    "description": "表单的跨字段验证规则",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FormRule"
+                    }
+                },
                 "version": {
                     "type": "string"
                 },
@@ -1007,6 +3518,24 @@ const docTemplate = `{
                 }
             }
         },
+        "response.AppBackupFile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduled": {
+                    "description": "是否为定时备份，只有定时备份会按保留数量清理",
+                    "type": "boolean"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "response.AppDetail": {
             "type": "object",
             "properties": {
@@ -1022,6 +3551,14 @@ const docTemplate = `{
                 "docker_compose": {
                     "type": "string"
                 },
+                "health_check": {
+                    "description": "HTTP健康检查",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.HealthCheck"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1045,6 +3582,38 @@ const docTemplate = `{
                 }
             }
         },
+        "response.AppHealth": {
+            "type": "object",
+            "properties": {
+                "health": {
+                    "description": "健康状态，没有健康检查时为空",
+                    "type": "string"
+                },
+                "health_check": {
+                    "description": "插件声明的HTTP健康检查",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.HealthCheck"
+                        }
+                    ]
+                },
+                "log": {
+                    "description": "最近的健康检查记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HealthProbe"
+                    }
+                },
+                "message": {
+                    "description": "状态说明",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "插件状态",
+                    "type": "string"
+                }
+            }
+        },
         "response.AppInstalledParamsResp": {
             "type": "object",
             "properties": {
@@ -1060,7 +3629,7 @@ const docTemplate = `{
                 "params": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/response.FormField"
+                        "$ref": "#/definitions/dto.FormField"
                     }
                 }
             }
@@ -1071,39 +3640,161 @@ const docTemplate = `{
                 "form_fields": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/response.FormField"
+                        "$ref": "#/definitions/dto.FormField"
+                    }
+                },
+                "rules": {
+                    "description": "跨字段验证规则",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FormRule"
                     }
                 }
             }
         },
-        "response.FormField": {
+        "response.AppUptime": {
             "type": "object",
             "properties": {
-                "default": {
+                "end": {
                     "type": "string"
                 },
-                "env_key": {
+                "failures": {
+                    "description": "从运行状态变为异常的次数",
+                    "type": "integer"
+                },
+                "mtbf_seconds": {
+                    "description": "平均故障间隔，没有故障时为 null",
+                    "type": "integer"
+                },
+                "observed_seconds": {
+                    "description": "计入统计的时长，不包括停止与安装中的时间",
+                    "type": "integer"
+                },
+                "start": {
                     "type": "string"
                 },
-                "key": {
+                "status_seconds": {
+                    "description": "各状态的持续时长",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "up_seconds": {
+                    "description": "运行正常的时长",
+                    "type": "integer"
+                },
+                "uptime_percent": {
+                    "description": "可用率，没有可统计的时长时为 0",
+                    "type": "number"
+                }
+            }
+        },
+        "response.IPAMOverview": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.IPAddressInfo"
+                    }
+                },
+                "allocated": {
+                    "type": "integer"
+                },
+                "excluded": {
+                    "type": "integer"
+                },
+                "free": {
+                    "type": "integer"
+                },
+                "network": {
                     "type": "string"
                 },
-                "label": {
+                "network6": {
                     "type": "string"
                 },
-                "required": {
-                    "type": "boolean"
+                "reserved": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "untracked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "response.IPAddressInfo": {
+            "type": "object",
+            "properties": {
+                "allocated_at": {
+                    "type": "string"
+                },
+                "install_id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "owner_type": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "response.IPConflict": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "install_id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
                 },
-                "rule": {
+                "owner_name": {
                     "type": "string"
                 },
                 "type": {
                     "type": "string"
+                }
+            }
+        },
+        "response.IPReconcileReport": {
+            "type": "object",
+            "properties": {
+                "allocations": {
+                    "type": "integer"
                 },
-                "value": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.IPConflict"
+                    }
+                },
+                "containers": {
+                    "type": "integer"
+                },
+                "network": {
                     "type": "string"
                 },
-                "values": {}
+                "network6": {
+                    "type": "string"
+                }
             }
         }
     },